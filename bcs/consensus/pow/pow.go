@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/consensus"
 	"github.com/xuperchain/xupercore/kernel/consensus/context"
@@ -441,8 +442,13 @@ func (pow *PoWConsensus) mining(task *mineTask) {
 		}
 		if pow.IsProofed(bid, pow.targetBits) {
 			task.block.SetItem("blockid", bid)
-			// 签名重置
-			s, err := pow.Crypto.SignECDSA(pow.Address.PrivateKey, bid)
+			// 签名重置，出块密钥可能被替换，需使用与打包时proposer一致的密钥签名
+			addr := (*xaddress.Address)(pow.Address).Snapshot()
+			if addr.Address != string(task.block.GetProposer()) {
+				task.doDone(BlockSignErr)
+				return
+			}
+			s, err := pow.Crypto.SignECDSA(addr.PrivateKey, bid)
 			if err != nil {
 				task.doDone(BlockSignErr)
 				return
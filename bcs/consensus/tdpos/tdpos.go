@@ -342,9 +342,10 @@ func (tp *tdposConsensus) initBFT() error {
 		pacemaker.CurrentView = tipHeight - 1
 	}
 	saftyrules := &chainedBft.DefaultSaftyRules{
-		Crypto: cryptoClient,
-		QcTree: qcTree,
-		Log:    tp.cCtx.XLog,
+		Crypto:            cryptoClient,
+		QcTree:            qcTree,
+		Log:               tp.cCtx.XLog,
+		JustifySampleSize: tp.cCtx.JustifySampleSize,
 	}
 	smr := chainedBft.NewSmr(tp.bcName, tp.election.address, tp.log, tp.cCtx.Network, cryptoClient, pacemaker, saftyrules, tp.election, qcTree)
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
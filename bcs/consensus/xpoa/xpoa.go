@@ -139,9 +139,10 @@ func (x *xpoaConsensus) initBFT() error {
 		pacemaker.CurrentView = tipHeight - 1
 	}
 	saftyrules := &chainedBft.DefaultSaftyRules{
-		Crypto: cryptoClient,
-		QcTree: qcTree,
-		Log:    x.cCtx.XLog,
+		Crypto:            cryptoClient,
+		QcTree:            qcTree,
+		Log:               x.cCtx.XLog,
+		JustifySampleSize: x.cCtx.JustifySampleSize,
 	}
 	smr := chainedBft.NewSmr(x.cCtx.BcName, x.election.address, x.log, x.cCtx.Network, cryptoClient, pacemaker, saftyrules, x.election, qcTree)
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
@@ -11,6 +11,7 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	"github.com/xuperchain/xvm/compile"
 	"github.com/xuperchain/xvm/exec"
 	"github.com/xuperchain/xvm/runtime/emscripten"
@@ -25,6 +26,12 @@ type xvmCreator struct {
 	cm       *codeManager
 	config   bridge.InstanceCreatorConfig
 	vmconfig *contract.WasmConfig
+	// gasSchedule 生效的gas计价表
+	gasSchedule contract.XVMGasSchedule
+	// wasiConfig 生效的wasi配置
+	wasiConfig contract.WASIConfig
+	// strictDeterminism 是否开启严格确定性模式
+	strictDeterminism bool
 
 	wasm2cPath string
 }
@@ -58,6 +65,16 @@ func newXVMCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.Instance
 		if optlevel < 0 || optlevel > 3 {
 			return nil, fmt.Errorf("bad xvm optlevel:%d", optlevel)
 		}
+		if err := creator.vmconfig.XVM.GasSchedule.Validate(); err != nil {
+			return nil, err
+		}
+		creator.gasSchedule = creator.vmconfig.XVM.GasSchedule
+		p2p.RegisterCapability(creator.gasSchedule.Capability())
+		if err := creator.vmconfig.XVM.WASI.Validate(); err != nil {
+			return nil, err
+		}
+		creator.wasiConfig = creator.vmconfig.XVM.WASI
+		creator.strictDeterminism = creator.vmconfig.XVM.StrictDeterminism
 	}
 	creator.cm, err = newCodeManager(creator.config.Basedir,
 		creator.CompileCode, creator.MakeExecCode)
@@ -76,6 +93,12 @@ func cpfile(dest, src string) error {
 }
 
 func (x *xvmCreator) CompileCode(buf []byte, outputPath string) error {
+	// 在写入临时文件和调用编译工具之前检查代码大小
+	if x.vmconfig != nil {
+		if err := x.vmconfig.XVM.CheckCodeSize(len(buf)); err != nil {
+			return err
+		}
+	}
 	tmpdir, err := ioutil.TempDir("", "xvm-compile")
 	if err != nil {
 		return err
@@ -105,12 +128,16 @@ func (x *xvmCreator) getContractCodeCache(name string, cp bridge.ContractCodePro
 }
 
 func (x *xvmCreator) MakeExecCode(libpath string) (exec.Code, bool, error) {
-	resolvers := []exec.Resolver{
+	resolvers, err := withCustomResolvers([]exec.Resolver{
 		gowasm.NewResolver(),
 		emscripten.NewResolver(),
 		newSyscallResolver(x.config.SyscallService),
 		builtinResolver,
+		newWASIResolver(&x.wasiConfig),
 		wasi.NewResolver(),
+	}, &x.config)
+	if err != nil {
+		return nil, false, err
 	}
 	//AOT only for experiment;
 	// if x.vmconfig.TEEConfig.Enable {
@@ -123,7 +150,7 @@ func (x *xvmCreator) MakeExecCode(libpath string) (exec.Code, bool, error) {
 	// }
 
 	resolver := exec.NewMultiResolver(
-		resolvers...,
+		withStrictDeterminism(resolvers, x.strictDeterminism)...,
 	)
 	// TODO @fengjin
 	// newAOTCode shoule accept []byte as arguement rather than string
@@ -145,7 +172,7 @@ func (x *xvmCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeP
 		return nil, err
 	}
 
-	return createInstance(ctx, code, x.config.SyscallService)
+	return createInstance(ctx, code, x.config.SyscallService, &x.gasSchedule)
 }
 
 func (x *xvmCreator) RemoveCache(contractName string) {
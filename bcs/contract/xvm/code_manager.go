@@ -22,6 +22,15 @@ import (
 	"github.com/xuperchain/xupercore/protos"
 )
 
+// artifactABIVersion 编译产物与xupercore运行时之间的接口版本，resolver导出的符号、
+// 编译参数等变化导致旧的code.so不再兼容时需要递增，节点升级后会自动重新编译合约
+const artifactABIVersion = "1"
+
+// artifactVersion 编译产物的版本，由xvm编译器版本和运行时接口版本组成，记录在code.desc中
+func artifactVersion() string {
+	return compile.Version + "+abi" + artifactABIVersion
+}
+
 type compileFunc func([]byte, string) error
 type makeExecCodeFunc func(libpath string) (exec.Code, bool, error)
 
@@ -38,6 +47,8 @@ type codeManager struct {
 	cachedir     string
 	compileCode  compileFunc
 	makeExecCode makeExecCodeFunc
+	// version 当前运行时的编译产物版本，磁盘缓存版本不一致时重新编译
+	version string
 
 	makeCacheLock singleflight.Group
 
@@ -63,6 +74,7 @@ func newCodeManager(basedir string, compile compileFunc, makeExec makeExecCodeFu
 		cachedir:     cacheDirFull,
 		compileCode:  compile,
 		makeExecCode: makeExec,
+		version:      artifactVersion(),
 		codes:        make(map[string]*contractCode),
 	}, nil
 }
@@ -137,7 +149,7 @@ func (c *codeManager) lookupDiskCache(name string, desc *protos.WasmCodeDesc) (s
 		return "", false
 	}
 	if !codeDescEqual(&localDesc, desc) ||
-		localDesc.GetVmCompiler() != compile.Version {
+		localDesc.GetVmCompiler() != c.version {
 		return "", false
 	}
 	return libpath, true
@@ -158,7 +170,7 @@ func (c *codeManager) makeDiskCache(name string, desc *protos.WasmCodeDesc, code
 		return "", err
 	}
 	localDesc := *desc
-	localDesc.VmCompiler = compile.Version
+	localDesc.VmCompiler = c.version
 	descbuf, _ := json.Marshal(&localDesc)
 	err = ioutil.WriteFile(descpath, descbuf, 0600)
 	if err != nil {
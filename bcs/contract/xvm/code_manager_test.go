@@ -95,6 +95,55 @@ func TestGetCacheExecCode(t *testing.T) {
 
 }
 
+func TestArtifactVersionBump(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "xvm-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	compiled := 0
+	compileFunc := func(code []byte, output string) error {
+		compiled++
+		return ioutil.WriteFile(output, code, 0700)
+	}
+	makeExecCodeFunc := func(libpath string) (exec.Code, bool, error) {
+		return new(fakeCode), false, nil
+	}
+	cp := &memCodeProvider{
+		code: []byte("binary code"),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest1"),
+		},
+	}
+	// 模拟节点重启，version为运行时的编译产物版本
+	restart := func(version string) {
+		cm, err := newCodeManager(tmpdir, compileFunc, makeExecCodeFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cm.version = version
+		if _, err := cm.GetExecCode("c1", cp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restart("v1")
+	restart("v1")
+	if compiled != 1 {
+		t.Fatalf("expect disk cache reused with same version, compiled %d times", compiled)
+	}
+	// 升级后版本不一致，旧的编译产物需要重新编译
+	restart("v2")
+	if compiled != 2 {
+		t.Fatalf("expect recompile after version bump, compiled %d times", compiled)
+	}
+	restart("v2")
+	if compiled != 2 {
+		t.Fatalf("expect recompiled artifact cached, compiled %d times", compiled)
+	}
+}
+
 func TestMakeCacheBlocking(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "xvm-test")
 	if err != nil {
@@ -0,0 +1,41 @@
+package xvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+func TestCompileCodeSizeLimit(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "xvm-codesize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(basedir)
+
+	xvmconfig := contract.XVMConfig{MaxCodeSize: int64(len(randomWasm) - 1)}
+	compilers := map[string]func([]byte, string) error{
+		"xvm":  (&xvmCreator{vmconfig: &contract.WasmConfig{XVM: xvmconfig}}).CompileCode,
+		"ixvm": (&xvmInterpCreator{xvmconfig: xvmconfig}).compileCode,
+	}
+	for name, compile := range compilers {
+		output := filepath.Join(basedir, name+".so")
+		err := compile(randomWasm, output)
+		if err == nil || !strings.Contains(err.Error(), "contract too large") {
+			t.Fatalf("%s: expect contract too large, got %v", name, err)
+		}
+		// 超限的代码不落盘，也不调用编译工具
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Fatalf("%s: oversized code should not be written, stat err %v", name, err)
+		}
+	}
+
+	interp := &xvmInterpCreator{xvmconfig: contract.XVMConfig{MaxCodeSize: int64(len(randomWasm))}}
+	if err := interp.compileCode(randomWasm, filepath.Join(basedir, "fit.wasm")); err != nil {
+		t.Fatalf("code within limit should compile, got %v", err)
+	}
+}
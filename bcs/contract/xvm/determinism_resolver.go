@@ -0,0 +1,74 @@
+package xvm
+
+import (
+	"fmt"
+
+	"github.com/xuperchain/xvm/exec"
+)
+
+// 严格确定性模式
+// 合约执行结果必须在所有节点上一致，时间和随机数等宿主函数的返回值依赖节点本地环境。
+// go运行时的时间、随机数和定时器函数已由gowasm返回固定值，这里不再处理；
+// 开启后由strictResolver接管其余时间、随机数相关的函数，调用时直接trap，
+// 即使自定义resolver提供了这些函数的实现也不会生效
+
+// nonDeterministicFuncs 严格模式下调用即trap的宿主函数及其参数个数
+// xvm按参数个数匹配宿主函数签名，i64参数同样按一个参数计算
+var nonDeterministicFuncs = map[string]int{
+	"wasi_unstable.clock_time_get":          3,
+	"wasi_unstable.clock_res_get":           2,
+	"wasi_unstable.random_get":              2,
+	"wasi_snapshot_preview1.clock_time_get": 3,
+	"wasi_snapshot_preview1.clock_res_get":  2,
+	"wasi_snapshot_preview1.random_get":     2,
+	"env._time":                             1,
+	"env._gettimeofday":                     2,
+	"env._clock_gettime":                    2,
+	"env._emscripten_get_now":               0,
+}
+
+type strictResolver struct{}
+
+// newStrictResolver 创建严格确定性模式下的resolver，需放在所有resolver之前
+func newStrictResolver() exec.Resolver {
+	return strictResolver{}
+}
+
+func (strictResolver) ResolveFunc(module, name string) (interface{}, bool) {
+	fullname := module + "." + name
+	if arity, ok := nonDeterministicFuncs[fullname]; ok {
+		return forbiddenFunc(fullname, arity), true
+	}
+	return nil, false
+}
+
+func (strictResolver) ResolveGlobal(module, name string) (int64, bool) {
+	return 0, false
+}
+
+// forbiddenFunc 返回调用即trap的宿主函数
+func forbiddenFunc(name string, arity int) interface{} {
+	trap := func() uint32 {
+		exec.Throw(exec.NewTrap(fmt.Sprintf("non-deterministic function %s is forbidden", name)))
+		return 0
+	}
+	switch arity {
+	case 0:
+		return func(ctx exec.Context) uint32 { return trap() }
+	case 1:
+		return func(ctx exec.Context, x uint32) uint32 { return trap() }
+	case 2:
+		return func(ctx exec.Context, x, y uint32) uint32 { return trap() }
+	default:
+		return func(ctx exec.Context, x, y, z uint32) uint32 { return trap() }
+	}
+}
+
+// withStrictDeterminism 开启严格确定性模式时把strictResolver放在最前面，
+// 保证包括WithShadowCore在内的任何resolver都无法提供不确定的实现
+func withStrictDeterminism(resolvers []exec.Resolver, strict bool) []exec.Resolver {
+	if !strict {
+		return resolvers
+	}
+	return append([]exec.Resolver{newStrictResolver()}, resolvers...)
+}
@@ -0,0 +1,96 @@
+package xvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+)
+
+// randomWasm 导出run函数，返回wasi_unstable.random_get(0, 0)的结果，等价于
+//
+//	(module
+//	  (import "wasi_unstable" "random_get" (func $random_get (param i32 i32) (result i32)))
+//	  (memory (export "memory") 1)
+//	  (func (export "run") (result i32)
+//	    (call $random_get (i32.const 0) (i32.const 0))))
+var randomWasm = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// type
+	0x01, 0x0b, 0x02, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f, 0x60, 0x00, 0x01, 0x7f,
+	// import
+	0x02, 0x1c, 0x01,
+	0x0d, 'w', 'a', 's', 'i', '_', 'u', 'n', 's', 't', 'a', 'b', 'l', 'e',
+	0x0a, 'r', 'a', 'n', 'd', 'o', 'm', '_', 'g', 'e', 't',
+	0x00, 0x00,
+	// function
+	0x03, 0x02, 0x01, 0x01,
+	// memory
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	// export
+	0x07, 0x10, 0x02,
+	0x03, 'r', 'u', 'n', 0x00, 0x01,
+	0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+	// code
+	0x0a, 0x0a, 0x01, 0x08, 0x00, 0x41, 0x00, 0x41, 0x00, 0x10, 0x00, 0x0b,
+}
+
+func TestStrictDeterminism(t *testing.T) {
+	// 自定义resolver提供了依赖本地环境的实现
+	RegisterResolverFactory("test_random", func(*bridge.InstanceCreatorConfig) (exec.Resolver, error) {
+		return exec.MapResolver{
+			"wasi_unstable.random_get": func(ctx exec.Context, buf, size uint32) uint32 {
+				return 7
+			},
+		}, nil
+	})
+	defer func() {
+		resolverMu.Lock()
+		delete(resolverFactories, "test_random")
+		resolverMu.Unlock()
+	}()
+
+	basedir, err := ioutil.TempDir("", "xvm-determinism")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(basedir)
+	codepath := filepath.Join(basedir, "random.wasm")
+	if err := ioutil.WriteFile(codepath, randomWasm, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(strict bool) (ret int64, err error) {
+		creator, err := newXVMInterpCreator(&bridge.InstanceCreatorConfig{
+			Basedir:  basedir,
+			VMConfig: &contract.WasmConfig{XVM: contract.XVMConfig{StrictDeterminism: strict}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, _, err := creator.(*xvmInterpCreator).makeExecCode(codepath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, err := code.NewContext(exec.DefaultContextConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Release()
+		return ctx.Exec("run", nil)
+	}
+
+	if ret, err := run(false); err != nil || ret != 7 {
+		t.Fatalf("expect custom random_get without strict mode, got %d %v", ret, err)
+	}
+	// 严格模式下即使有实现也会trap
+	if _, err := run(true); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("expect random_get trapped in strict mode, got %v", err)
+	}
+}
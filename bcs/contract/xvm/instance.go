@@ -14,10 +14,15 @@ import (
 	gowasm "github.com/xuperchain/xvm/runtime/go"
 )
 
-func createInstance(ctx *bridge.Context, code *contractCode, syscall *bridge.SyscallService) (bridge.Instance, error) {
+// wasmPageSize wasm内存页大小
+const wasmPageSize = 64 * 1024
+
+func createInstance(ctx *bridge.Context, code *contractCode, syscall *bridge.SyscallService,
+	schedule *contract.XVMGasSchedule) (bridge.Instance, error) {
 	// log.Info("instance resource limit", "limits", ctx.ResourceLimits)
+	// xvm内部按原始指令gas计数，需要按计价表系数折算限额
 	execCtx, err := code.ExecCode.NewContext(&exec.ContextConfig{
-		GasLimit: ctx.ResourceLimits.Cpu,
+		GasLimit: ctx.ResourceLimits.Cpu * contract.DefaultInstructionRatio / schedule.Ratio(),
 	})
 	if err != nil {
 		// log.Error("create contract context error", "error", err, "contract", ctx.ContractName)
@@ -33,11 +38,15 @@ func createInstance(ctx *bridge.Context, code *contractCode, syscall *bridge.Sys
 		}
 	}
 	execCtx.SetUserData(contextIDKey, ctx.ID)
+	execCtx.SetUserData(bridgeContextKey, ctx)
+	execCtx.SetUserData(syscallCountKey, new(int64))
+	execCtx.SetUserData(gasScheduleKey, schedule)
 	instance := &xvmInstance{
 		bridgeCtx: ctx,
 		execCtx:   execCtx,
 		desc:      code.Desc,
 		legacy:    code.legacy,
+		schedule:  schedule,
 	}
 	instance.InitDebugWriter(syscall)
 	return instance, nil
@@ -49,6 +58,7 @@ type xvmInstance struct {
 	desc      protos.WasmCodeDesc
 	syscall   *bridge.SyscallService
 	legacy    bool
+	schedule  *contract.XVMGasSchedule
 }
 
 func (x *xvmInstance) Exec() error {
@@ -73,12 +83,21 @@ func (x *xvmInstance) Exec() error {
 }
 
 func (x *xvmInstance) ResourceUsed() contract.Limits {
+	return resourceUsed(x.execCtx, x.schedule)
+}
+
+// resourceUsed 按计价表把虚拟机指令gas、内存页和系统调用次数折算为Cpu
+func resourceUsed(ctx exec.Context, schedule *contract.XVMGasSchedule) contract.Limits {
 	limits := contract.Limits{
-		Cpu: x.execCtx.GasUsed(),
+		Cpu: ctx.GasUsed() * schedule.Ratio() / contract.DefaultInstructionRatio,
 	}
-	mem := x.execCtx.Memory()
+	mem := ctx.Memory()
 	if mem != nil {
 		limits.Memory = int64(len(mem))
+		limits.Cpu += int64(len(mem)/wasmPageSize) * schedule.MemoryPageCost
+	}
+	if count, ok := ctx.GetUserData(syscallCountKey).(*int64); ok {
+		limits.Cpu += *count * schedule.SyscallCost
 	}
 	return limits
 }
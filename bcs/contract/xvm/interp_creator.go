@@ -11,18 +11,32 @@ import (
 	"github.com/xuperchain/xvm/runtime/emscripten"
 	gowasm "github.com/xuperchain/xvm/runtime/go"
 
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
 )
 
 type xvmInterpCreator struct {
 	cm     *codeManager
 	config bridge.InstanceCreatorConfig
+	// xvmconfig 生效的xvm配置
+	xvmconfig contract.XVMConfig
 }
 
 func newXVMInterpCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
 	creator := &xvmInterpCreator{
 		config: *creatorConfig,
 	}
+	if vmconfig, ok := creatorConfig.VMConfig.(*contract.WasmConfig); ok && vmconfig != nil {
+		if err := vmconfig.XVM.GasSchedule.Validate(); err != nil {
+			return nil, err
+		}
+		if err := vmconfig.XVM.WASI.Validate(); err != nil {
+			return nil, err
+		}
+		creator.xvmconfig = vmconfig.XVM
+		p2p.RegisterCapability(creator.xvmconfig.GasSchedule.Capability())
+	}
 	var err error
 	creator.cm, err = newCodeManager(creator.config.Basedir,
 		creator.compileCode, creator.makeExecCode)
@@ -33,6 +47,9 @@ func newXVMInterpCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.In
 }
 
 func (x *xvmInterpCreator) compileCode(buf []byte, outputPath string) error {
+	if err := x.xvmconfig.CheckCodeSize(len(buf)); err != nil {
+		return err
+	}
 	return ioutil.WriteFile(outputPath, buf, 0600)
 }
 
@@ -41,13 +58,18 @@ func (x *xvmInterpCreator) makeExecCode(codepath string) (exec.Code, bool, error
 	if err != nil {
 		return nil, false, err
 	}
-	resolver := exec.NewMultiResolver(
+	resolvers, err := withCustomResolvers([]exec.Resolver{
 		gowasm.NewResolver(),
 		emscripten.NewResolver(),
 		newSyscallResolver(x.config.SyscallService),
+		newWASIResolver(&x.xvmconfig.WASI),
 		wasi.NewResolver(),
 		builtinResolver,
-	)
+	}, &x.config)
+	if err != nil {
+		return nil, false, err
+	}
+	resolver := exec.NewMultiResolver(withStrictDeterminism(resolvers, x.xvmconfig.StrictDeterminism)...)
 	// not good to rely on wagon directly in xupercore,but no better solution
 	legacy, err := isLegacyInterp(codebuf)
 	if err != nil {
@@ -62,7 +84,7 @@ func (x *xvmInterpCreator) CreateInstance(ctx *bridge.Context, cp bridge.Contrac
 	if err != nil {
 		return nil, err
 	}
-	return createInstance(ctx, code, x.config.SyscallService)
+	return createInstance(ctx, code, x.config.SyscallService, &x.xvmconfig.GasSchedule)
 }
 
 func (x *xvmInterpCreator) RemoveCache(contractName string) {
@@ -5,16 +5,62 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/memrpc"
 	"github.com/xuperchain/xvm/exec"
 )
 
 const (
-	contextIDKey = "ctxid"
-	responseKey  = "callResponse"
+	contextIDKey     = "ctxid"
+	bridgeContextKey = "bridgeContext"
+	responseKey      = "callResponse"
+	syscallCountKey  = "syscallCount"
+	gasScheduleKey   = "gasSchedule"
 )
 
+// countSyscall 累加合约的系统调用次数，用于按计价表收取gas
+func countSyscall(ctx exec.Context) {
+	if count, ok := ctx.GetUserData(syscallCountKey).(*int64); ok {
+		*count++
+	}
+}
+
+// recordSyscall 把系统调用的次数和数据量记录到本次合约调用的上下文中，开启跟踪时同时记录调用明细，
+// 仅用于统计和调试，不影响gas
+func recordSyscall(ctx exec.Context, method string, request, response []byte, err error) {
+	if bctx, ok := ctx.GetUserData(bridgeContextKey).(*bridge.Context); ok {
+		bctx.SyscallStats.Record(method, len(request), len(response))
+		if bctx.Tracer != nil {
+			bctx.Tracer.Record(bctx.ContractName, method, request, response, err, tracedGasUsed(ctx))
+		}
+	}
+}
+
+// tracedGasUsed 返回按计价表折算后的累计gas，包含内存页和系统调用的附加费用，与ResourceUsed的Cpu一致
+func tracedGasUsed(ctx exec.Context) int64 {
+	schedule, ok := ctx.GetUserData(gasScheduleKey).(*contract.XVMGasSchedule)
+	if !ok {
+		return ctx.GasUsed()
+	}
+	return resourceUsed(ctx, schedule).Cpu
+}
+
+// checkSyscall 合约声明了可调用的系统调用时，调用未声明的系统调用会中止合约执行
+// 只读调用中调用修改状态的系统调用同样会中止合约执行
+func checkSyscall(ctx exec.Context, method string) {
+	bctx, ok := ctx.GetUserData(bridgeContextKey).(*bridge.Context)
+	if !ok {
+		return
+	}
+	if !bctx.AllowSyscall(method) {
+		exec.Throw(exec.NewTrap(fmt.Sprintf("syscall %s not allowed for contract %s", method, bctx.ContractName)))
+	}
+	if err := bctx.CheckWritable(method); err != nil {
+		exec.Throw(exec.NewTrap(fmt.Sprintf("syscall %s of contract %s: %s", method, bctx.ContractName, err)))
+	}
+}
+
 type responseDesc struct {
 	Body  []byte
 	Error bool
@@ -73,9 +119,12 @@ func (s *syscallResolver) ResolveFunc(module, name string) (interface{}, bool) {
 func (s *syscallResolver) goCallMethod(ctx exec.Context, sp uint32) uint32 {
 	codec := exec.NewCodec(ctx)
 	ctxid := ctx.GetUserData(contextIDKey).(int64)
+	countSyscall(ctx)
 	method := codec.GoString(sp + 8)
+	checkSyscall(ctx, method)
 	requestBuf := codec.GoBytes(sp + 24)
 	responseBuf, err := s.rpcserver.CallMethod(context.TODO(), ctxid, method, requestBuf)
+	recordSyscall(ctx, method, requestBuf, responseBuf, err)
 	var responseDesc responseDesc
 	if err != nil {
 		responseDesc.Error = true
@@ -112,9 +161,12 @@ func (s *syscallResolver) goFetchResponse(ctx exec.Context, sp uint32) uint32 {
 func (s *syscallResolver) cCallMethod(ctx exec.Context, methodAddr, methodLen, requestAddr, requestLen uint32) uint32 {
 	codec := exec.NewCodec(ctx)
 	ctxid := ctx.GetUserData(contextIDKey).(int64)
+	countSyscall(ctx)
 	method := codec.String(methodAddr, methodLen)
+	checkSyscall(ctx, method)
 	requestBuf := codec.Bytes(requestAddr, requestLen)
 	responseBuf, err := s.rpcserver.CallMethod(context.TODO(), ctxid, method, requestBuf)
+	recordSyscall(ctx, method, requestBuf, responseBuf, err)
 	var responseDesc responseDesc
 	if err != nil {
 		// log.Error("contract syscall error", "ctxid", ctxid, "method", method, "error", err)
@@ -156,11 +208,14 @@ func (s *syscallResolver) cCallMethodv2(
 
 	codec := exec.NewCodec(ctx)
 	ctxid := ctx.GetUserData(contextIDKey).(int64)
+	countSyscall(ctx)
 	method := codec.String(methodAddr, methodLen)
+	checkSyscall(ctx, method)
 	requestBuf := codec.Bytes(requestAddr, requestLen)
 	responseBuf := codec.Bytes(responseAddr, responseLen)
 
 	response, err := s.rpcserver.CallMethod(context.TODO(), ctxid, method, requestBuf)
+	recordSyscall(ctx, method, requestBuf, response, err)
 
 	// fast path
 	if err != nil {
@@ -0,0 +1,96 @@
+package xvm
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+)
+
+// ResolverFactory 根据合约虚拟机配置创建自定义的宿主函数resolver
+type ResolverFactory func(config *bridge.InstanceCreatorConfig) (exec.Resolver, error)
+
+// ResolverOption 设置自定义resolver的注册选项
+type ResolverOption func(*resolverEntry)
+
+// WithShadowCore 允许自定义resolver覆盖内置的宿主函数(包括syscall)，需谨慎使用
+func WithShadowCore() ResolverOption {
+	return func(e *resolverEntry) {
+		e.shadowCore = true
+	}
+}
+
+type resolverEntry struct {
+	name       string
+	factory    ResolverFactory
+	shadowCore bool
+}
+
+var (
+	resolverMu        sync.RWMutex
+	resolverFactories = make(map[string]*resolverEntry)
+)
+
+// RegisterResolverFactory 注册自定义resolver，创建合约代码时追加到内置resolver之后，按名字排序保证各节点顺序一致
+// 同名重复注册或factory为nil时panic
+func RegisterResolverFactory(name string, factory ResolverFactory, opts ...ResolverOption) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+
+	if factory == nil {
+		panic("xvm: RegisterResolverFactory factory is nil")
+	}
+	if _, dup := resolverFactories[name]; dup {
+		panic(fmt.Sprintf("xvm: resolver factory %s already registered", name))
+	}
+	entry := &resolverEntry{
+		name:    name,
+		factory: factory,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	resolverFactories[name] = entry
+}
+
+// sortedResolverEntries 按名字返回已注册的自定义resolver
+func sortedResolverEntries() []*resolverEntry {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+
+	entries := make([]*resolverEntry, 0, len(resolverFactories))
+	for _, entry := range resolverFactories {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// withCustomResolvers 将自定义resolver合并到内置resolver列表中
+// 默认追加在内置resolver之后，MultiResolver优先匹配靠前的resolver，因此无法覆盖内置宿主函数；
+// 使用WithShadowCore注册的resolver放在内置resolver之前。
+func withCustomResolvers(core []exec.Resolver, config *bridge.InstanceCreatorConfig) ([]exec.Resolver, error) {
+	var shadow, tail []exec.Resolver
+	for _, entry := range sortedResolverEntries() {
+		resolver, err := entry.factory(config)
+		if err != nil {
+			return nil, fmt.Errorf("create resolver %s error:%v", entry.name, err)
+		}
+		if entry.shadowCore {
+			shadow = append(shadow, resolver)
+		} else {
+			tail = append(tail, resolver)
+		}
+	}
+
+	resolvers := make([]exec.Resolver, 0, len(shadow)+len(core)+len(tail))
+	resolvers = append(resolvers, shadow...)
+	resolvers = append(resolvers, core...)
+	resolvers = append(resolvers, tail...)
+	return resolvers, nil
+}
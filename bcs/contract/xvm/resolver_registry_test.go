@@ -0,0 +1,51 @@
+package xvm
+
+import (
+	"testing"
+
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+)
+
+func TestCustomResolvers(t *testing.T) {
+	newFactory := func(v int64) ResolverFactory {
+		return func(*bridge.InstanceCreatorConfig) (exec.Resolver, error) {
+			return exec.MapResolver{"env.value": v}, nil
+		}
+	}
+	RegisterResolverFactory("test_b", newFactory(2))
+	RegisterResolverFactory("test_a", newFactory(1))
+	defer func() {
+		resolverMu.Lock()
+		delete(resolverFactories, "test_a")
+		delete(resolverFactories, "test_b")
+		delete(resolverFactories, "test_shadow")
+		resolverMu.Unlock()
+	}()
+
+	core := []exec.Resolver{exec.MapResolver{"env.value": int64(0)}}
+	resolvers, err := withCustomResolvers(core, &bridge.InstanceCreatorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolvers) != 3 {
+		t.Fatalf("expect 3 resolvers, got %d", len(resolvers))
+	}
+	// 自定义resolver按名字排序追加在内置resolver之后，无法覆盖内置符号
+	if v, _ := resolvers[1].ResolveGlobal("env", "value"); v != 1 {
+		t.Fatalf("expect test_a first, got %d", v)
+	}
+	if v, _ := exec.NewMultiResolver(resolvers...).ResolveGlobal("env", "value"); v != 0 {
+		t.Fatalf("expect core symbol, got %d", v)
+	}
+
+	RegisterResolverFactory("test_shadow", newFactory(3), WithShadowCore())
+	resolvers, err = withCustomResolvers(core, &bridge.InstanceCreatorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := exec.NewMultiResolver(resolvers...).ResolveGlobal("env", "value"); v != 3 {
+		t.Fatalf("expect shadowed symbol, got %d", v)
+	}
+}
@@ -0,0 +1,134 @@
+package xvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+)
+
+type userDataContext struct {
+	exec.Context
+	data map[string]interface{}
+	gas  int64
+}
+
+func (u *userDataContext) GasUsed() int64 {
+	return u.gas
+}
+
+func (u *userDataContext) GetUserData(key string) interface{} {
+	return u.data[key]
+}
+
+func (u *userDataContext) Memory() []byte {
+	return nil
+}
+
+func TestRecordSyscall(t *testing.T) {
+	bctx := &bridge.Context{SyscallStats: bridge.NewSyscallStats()}
+	ctx := &userDataContext{data: map[string]interface{}{bridgeContextKey: bctx}}
+
+	recordSyscall(ctx, "GetObject", make([]byte, 10), make([]byte, 100), nil)
+	recordSyscall(ctx, "GetObject", make([]byte, 20), make([]byte, 0), nil)
+	recordSyscall(ctx, "PutObject", make([]byte, 30), make([]byte, 5), nil)
+
+	stats := bctx.SyscallStats.Snapshot()
+	if len(stats) != 2 {
+		t.Fatalf("expect 2 methods, got %d", len(stats))
+	}
+	get := stats["GetObject"]
+	if get.Count != 2 || get.RequestBytes != 30 || get.ResponseBytes != 100 {
+		t.Fatalf("unexpected GetObject stat %+v", get)
+	}
+	if put := stats["PutObject"]; put.Count != 1 || put.RequestBytes != 30 || put.ResponseBytes != 5 {
+		t.Fatalf("unexpected PutObject stat %+v", put)
+	}
+
+	// 没有统计对象或上下文时忽略
+	recordSyscall(&userDataContext{data: map[string]interface{}{bridgeContextKey: &bridge.Context{}}}, "GetObject", make([]byte, 1), make([]byte, 1), nil)
+	recordSyscall(&userDataContext{}, "GetObject", make([]byte, 1), make([]byte, 1), nil)
+}
+
+func TestRecordSyscallTrace(t *testing.T) {
+	bctx := &bridge.Context{
+		ContractName: "counter",
+		SyscallStats: bridge.NewSyscallStats(),
+		Tracer:       contract.NewTracer(4),
+	}
+	ctx := &userDataContext{data: map[string]interface{}{bridgeContextKey: bctx}}
+
+	ctx.gas = 10
+	recordSyscall(ctx, "GetObject", []byte("key"), []byte("value"), nil)
+	// 设置计价表后，跟踪中的gas与计费一致，包含系统调用的附加费用
+	count := int64(2)
+	ctx.data[syscallCountKey] = &count
+	ctx.data[gasScheduleKey] = &contract.XVMGasSchedule{InstructionRatio: 2000, SyscallCost: 100}
+	ctx.gas = 25
+	recordSyscall(ctx, "PutObject", []byte("k"), nil, errors.New("put error"))
+
+	entries := bctx.Tracer.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, got %d", len(entries))
+	}
+	get := entries[0]
+	if get.Contract != "counter" || get.Method != "GetObject" || string(get.Request) != "key" ||
+		string(get.Response) != "valu" || !get.Truncated || get.GasUsed != 10 {
+		t.Fatalf("unexpected GetObject entry %+v", get)
+	}
+	put := entries[1]
+	if put.Method != "PutObject" || put.Error != "put error" || put.Truncated || put.GasUsed != 25*2+2*100 {
+		t.Fatalf("unexpected PutObject entry %+v", put)
+	}
+}
+
+func TestCheckSyscall(t *testing.T) {
+	trapped := func(bctx *bridge.Context, method string) (trap bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(exec.Trap); !ok {
+					panic(r)
+				}
+				trap = true
+			}
+		}()
+		checkSyscall(&userDataContext{data: map[string]interface{}{bridgeContextKey: bctx}}, method)
+		return false
+	}
+
+	// 未声明时不限制
+	if trapped(&bridge.Context{}, "Transfer") {
+		t.Fatal("contract without capabilities should call any syscall")
+	}
+
+	bctx := &bridge.Context{
+		ContractName: "counter",
+		Capabilities: []string{"GetObject", "PutObject"},
+	}
+	for _, method := range []string{"GetObject", "PutObject", "GetCallArgs", "SetOutput"} {
+		if trapped(bctx, method) {
+			t.Fatalf("%s should be allowed", method)
+		}
+	}
+	for _, method := range []string{"Transfer", "ContractCall"} {
+		if !trapped(bctx, method) {
+			t.Fatalf("%s should trap", method)
+		}
+	}
+
+	// 只读调用中修改状态的系统调用中止执行
+	view := &bridge.Context{ContractName: "counter", ReadOnly: true}
+	for _, method := range []string{"GetObject", "NewIterator", "ContractCall"} {
+		if trapped(view, method) {
+			t.Fatalf("%s should be allowed in read-only call", method)
+		}
+	}
+	for _, method := range []string{"PutObject", "DeleteObject", "Transfer", "EmitEvent"} {
+		if !trapped(view, method) {
+			t.Fatalf("%s should trap in read-only call", method)
+		}
+	}
+}
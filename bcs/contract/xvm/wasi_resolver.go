@@ -0,0 +1,57 @@
+package xvm
+
+import (
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+const (
+	wasiErrnoSuccess = 0
+)
+
+// newWASIResolver 根据配置提供wasi的args和environ，其余wasi调用仍由xvm内置的resolver处理
+func newWASIResolver(config *contract.WASIConfig) exec.Resolver {
+	args := config.Args
+	environ := config.Environ()
+	return exec.MapResolver(map[string]interface{}{
+		"wasi_unstable.args_sizes_get": func(ctx exec.Context, countAddr, sizeAddr uint32) uint32 {
+			return wasiSizesGet(ctx, args, countAddr, sizeAddr)
+		},
+		"wasi_unstable.args_get": func(ctx exec.Context, ptrsAddr, bufAddr uint32) uint32 {
+			return wasiStringsGet(ctx, args, ptrsAddr, bufAddr)
+		},
+		"wasi_unstable.environ_sizes_get": func(ctx exec.Context, countAddr, sizeAddr uint32) uint32 {
+			return wasiSizesGet(ctx, environ, countAddr, sizeAddr)
+		},
+		"wasi_unstable.environ_get": func(ctx exec.Context, ptrsAddr, bufAddr uint32) uint32 {
+			return wasiStringsGet(ctx, environ, ptrsAddr, bufAddr)
+		},
+	})
+}
+
+// wasiSizesGet 写入字符串个数以及包含结尾\0的总长度
+func wasiSizesGet(ctx exec.Context, strs []string, countAddr, sizeAddr uint32) uint32 {
+	codec := exec.NewCodec(ctx)
+	size := 0
+	for _, str := range strs {
+		size += len(str) + 1
+	}
+	codec.SetUint32(countAddr, uint32(len(strs)))
+	codec.SetUint32(sizeAddr, uint32(size))
+	return wasiErrnoSuccess
+}
+
+// wasiStringsGet 把以\0结尾的字符串依次写入bufAddr，并把每个字符串的地址写入ptrsAddr
+func wasiStringsGet(ctx exec.Context, strs []string, ptrsAddr, bufAddr uint32) uint32 {
+	codec := exec.NewCodec(ctx)
+	offset := bufAddr
+	for i, str := range strs {
+		codec.SetUint32(ptrsAddr+uint32(i)*4, offset)
+		buf := codec.Bytes(offset, uint32(len(str)+1))
+		copy(buf, str)
+		buf[len(str)] = 0
+		offset += uint32(len(str) + 1)
+	}
+	return wasiErrnoSuccess
+}
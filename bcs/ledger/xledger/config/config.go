@@ -17,6 +17,21 @@ type XLedgerConf struct {
 	BlockCacheSize int        `yaml:"blockCacheSize,omitempty"`
 	TxCacheSize    int        `yaml:"txCacheSize,omitempty"`
 	MempoolTxLimit int        `yaml:"mempoolTxLimit,omitempty"`
+	// 侧链分支保留策略
+	Branch BranchConfig `yaml:"branch,omitempty"`
+}
+
+// BranchConfig 侧链分支保留策略，各项为0表示不启用对应的裁剪条件
+type BranchConfig struct {
+	// 分支头落后主干高度超过该值时裁剪
+	MaxSideBranchDepth int64 `yaml:"maxSideBranchDepth,omitempty"`
+	// 分支头区块时间早于当前时间超过该秒数时裁剪
+	PruneAgeSeconds int64 `yaml:"pruneAgeSeconds,omitempty"`
+}
+
+// Enabled 是否配置了任一裁剪条件
+func (b BranchConfig) Enabled() bool {
+	return b.MaxSideBranchDepth > 0 || b.PruneAgeSeconds > 0
 }
 
 type UtxoConfig struct {
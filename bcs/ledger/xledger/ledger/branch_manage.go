@@ -3,9 +3,11 @@ package ledger
 import (
 	"bytes"
 	"strconv"
+	"time"
 
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
 )
 
 func (l *Ledger) updateBranchInfo(addedBlockid, deletedBlockid []byte, addedBlockHeight int64, batch kvdb.Batch) error {
@@ -23,6 +25,95 @@ func (l *Ledger) updateBranchInfo(addedBlockid, deletedBlockid []byte, addedBloc
 	return nil
 }
 
+// pruneBranches 按照配置的分支保留策略裁剪过旧的侧链分支，删除操作写入batch，
+// 与本次确认的区块一起提交。addedBlock为本次确认的区块，其所在分支总是保留。
+// 返回本次提交后保留的分支数量，未启用裁剪时不扫描分支，直接返回0
+func (l *Ledger) pruneBranches(addedBlock *pb.InternalBlock, trunkHeight int64, batch kvdb.Batch) (int, error) {
+	policy := l.ctx.LedgerCfg.Branch
+	if !policy.Enabled() {
+		return 0, nil
+	}
+	type branchTip struct {
+		blockid []byte
+		height  int64
+	}
+	tips := []branchTip{}
+	it := l.baseDB.NewIteratorWithPrefix([]byte(pb.BranchInfoPrefix))
+	for it.Next() {
+		key := it.Key()
+		if len(key) < len(pb.BranchInfoPrefix)+1 {
+			continue
+		}
+		blkId := append([]byte{}, key[len(pb.BranchInfoPrefix):]...)
+		// 父区块的分支记录已在本次确认中被替换
+		if bytes.Equal(blkId, addedBlock.PreHash) || bytes.Equal(blkId, addedBlock.Blockid) {
+			continue
+		}
+		height, err := strconv.ParseInt(string(it.Value()), 10, 64)
+		if err != nil {
+			it.Release()
+			return 0, err
+		}
+		tips = append(tips, branchTip{blockid: blkId, height: height})
+	}
+	it.Release()
+	if it.Error() != nil {
+		return 0, it.Error()
+	}
+	now := time.Now().Unix()
+	stale := [][]byte{}
+	keep := map[string]bool{}
+	markKeep := func(blockid []byte) {
+		for len(blockid) > 0 && !keep[string(blockid)] {
+			block, err := l.fetchBlock(blockid)
+			if err != nil || block.InTrunk {
+				return
+			}
+			keep[string(blockid)] = true
+			blockid = block.PreHash
+		}
+	}
+	markKeep(addedBlock.PreHash)
+	for _, tip := range tips {
+		block, err := l.fetchBlock(tip.blockid)
+		if err != nil {
+			return 0, err
+		}
+		if block.InTrunk {
+			continue
+		}
+		tooDeep := policy.MaxSideBranchDepth > 0 && trunkHeight-tip.height > policy.MaxSideBranchDepth
+		tooOld := policy.PruneAgeSeconds > 0 && now-block.Timestamp/int64(time.Second) > policy.PruneAgeSeconds
+		if tooDeep || tooOld {
+			stale = append(stale, tip.blockid)
+			continue
+		}
+		markKeep(tip.blockid)
+	}
+
+	for _, tipid := range stale {
+		if err := batch.Delete(append([]byte(pb.BranchInfoPrefix), tipid...)); err != nil {
+			return 0, err
+		}
+		// 从分支头向下删除，直到主干或仍被其他分支引用的区块
+		blockid := tipid
+		for len(blockid) > 0 && !keep[string(blockid)] {
+			block, err := l.fetchBlock(blockid)
+			if err != nil || block.InTrunk {
+				break
+			}
+			l.xlog.Info("prune side branch block", "blockid", utils.F(block.Blockid), "height", block.Height)
+			l.blkHeaderCache.Del(string(block.Blockid))
+			l.blockCache.Del(string(block.Blockid))
+			if err := batch.Delete(append([]byte(pb.BlocksTablePrefix), block.Blockid...)); err != nil {
+				return 0, err
+			}
+			blockid = block.PreHash
+		}
+	}
+	return len(tips) - len(stale) + 1, nil
+}
+
 func (l *Ledger) GetBranchInfo(targetBlockid []byte, targetBlockHeight int64) ([]string, error) {
 	result := []string{}
 	it := l.baseDB.NewIteratorWithPrefix([]byte(pb.BranchInfoPrefix))
@@ -55,6 +55,8 @@ type RootConfig struct {
 	IrreversibleSlideWindow string `json:"irreversibleslidewindow"`
 	// GroupChainContract
 	GroupChainContract InvokeRequest `json:"group_chain_contract"`
+	// AwardCheckHeight 从该高度起校验区块的矿工奖励，0表示不校验，已运行的链需约定升级高度
+	AwardCheckHeight int64 `json:"award_check_height"`
 }
 
 // GasPrice define gas rate for utxo
@@ -109,6 +111,11 @@ func (rc *RootConfig) GetIrreversibleSlideWindow() int64 {
 	return int64(irreversibleSlideWindow)
 }
 
+// IsAwardCheckEnabled 判断指定高度的区块是否需要校验矿工奖励
+func (rc *RootConfig) IsAwardCheckEnabled(height int64) bool {
+	return rc.AwardCheckHeight > 0 && height >= rc.AwardCheckHeight
+}
+
 // GetMaxBlockSizeInByte get max block size in Byte
 func (rc *RootConfig) GetMaxBlockSizeInByte() (n int64) {
 	maxSizeMB, _ := strconv.Atoi(rc.MaxBlockSize)
@@ -37,6 +37,20 @@ var (
 	ErrRootBlockAlreadyExist = errors.New("this ledger already has genesis block")
 	// ErrTxNotConfirmed return tx not confirmed error
 	ErrTxNotConfirmed = errors.New("transaction not confirmed")
+	// ErrBlockIdMismatch is returned when block id is not consistent with block content
+	ErrBlockIdMismatch = errors.New("block id mismatch")
+	// ErrBlockMerkleInvalid is returned when merkle root of block is invalid
+	ErrBlockMerkleInvalid = errors.New("block merkle root invalid")
+	// ErrBlockPubkeyInvalid is returned when public key of block can not be parsed
+	ErrBlockPubkeyInvalid = errors.New("block public key invalid")
+	// ErrBlockProposerMismatch is returned when proposer address doesn't match public key
+	ErrBlockProposerMismatch = errors.New("block proposer not match public key")
+	// ErrBlockSignInvalid is returned when block signature verification fails
+	ErrBlockSignInvalid = errors.New("block signature invalid")
+	// ErrBlockAwardInvalid is returned when coinbase award of block doesn't match the expected award
+	ErrBlockAwardInvalid = errors.New("block award invalid")
+	// ErrBlockNoTx is returned when a non-genesis block carries no transaction, not even the award tx
+	ErrBlockNoTx = errors.New("block has no transaction")
 	// NumCPU returns the number of CPU cores for the current system
 	NumCPU = runtime.NumCPU()
 )
@@ -484,18 +498,64 @@ func (l *Ledger) IsValidTx(idx int, tx *pb.Transaction, block *pb.InternalBlock)
 			return false
 		}
 		//交易奖励的金额是否符合策略?
-		awardTarget := l.GenesisBlock.CalcAward(block.Height)
-		amountBytes := tx.TxOutputs[0].Amount
-		awardN := big.NewInt(0)
-		awardN.SetBytes(amountBytes)
-		if awardN.Cmp(awardTarget) != 0 {
-			l.xlog.Warn("invalid block award found", "award", awardN.String(), "target", awardTarget.String())
+		if err := l.checkAwardAmount(tx, block.Height); err != nil {
 			return false
 		}
 	}
 	return true
 }
 
+// checkAwardAmount 检查coinbase交易的奖励金额是否等于该高度的系统奖励
+func (l *Ledger) checkAwardAmount(tx *pb.Transaction, height int64) error {
+	awardTarget := l.GenesisBlock.CalcAward(height)
+	awardN := big.NewInt(0)
+	awardN.SetBytes(tx.TxOutputs[0].Amount)
+	if awardN.Cmp(awardTarget) != 0 {
+		l.xlog.Warn("invalid block award found", "award", awardN.String(), "target", awardTarget.String(),
+			"height", height)
+		return fmt.Errorf("%w: award %s, expected %s", ErrBlockAwardInvalid, awardN.String(), awardTarget.String())
+	}
+	return nil
+}
+
+// verifyBlockAward 校验区块的矿工奖励，区块中最多一笔coinbase交易且金额与该高度的系统奖励一致。
+// 区块高度不在区块id的签名范围内，优先使用父区块高度推算。
+// 该规则自创世配置的award_check_height起生效，之前的区块不校验，避免新老节点对历史区块判断不一致
+func (l *Ledger) verifyBlockAward(block *pb.InternalBlock) error {
+	// 创世块的coinbase交易为预分配，不是矿工奖励
+	if len(block.PreHash) == 0 {
+		return nil
+	}
+	height := block.Height
+	if preBlock, err := l.fetchBlock(block.PreHash); err == nil {
+		height = preBlock.Height + 1
+	}
+	if !l.GenesisBlock.GetConfig().IsAwardCheckEnabled(height) {
+		return nil
+	}
+
+	var awardTx *pb.Transaction
+	for _, tx := range block.Transactions {
+		if !tx.Coinbase {
+			continue
+		}
+		if awardTx != nil {
+			return fmt.Errorf("%w: more than one coinbase tx", ErrBlockAwardInvalid)
+		}
+		awardTx = tx
+	}
+	if awardTx == nil {
+		if l.GenesisBlock.CalcAward(height).Sign() > 0 {
+			return fmt.Errorf("%w: award tx missing", ErrBlockAwardInvalid)
+		}
+		return nil
+	}
+	if len(awardTx.TxOutputs) < 1 {
+		return fmt.Errorf("%w: award tx has no output", ErrBlockAwardInvalid)
+	}
+	return l.checkAwardAmount(awardTx, height)
+}
+
 // UpdateBlockChainData modify tx which txid is txid
 func (l *Ledger) UpdateBlockChainData(txid string, ptxid string, publickey string, sign string, height int64) error {
 	if txid == "" || ptxid == "" {
@@ -705,6 +765,19 @@ func (l *Ledger) ConfirmBlock(block *pb.InternalBlock, isRoot bool) ConfirmStatu
 		l.xlog.Warn("update branch info fail", "updateBranchErr", updateBranchErr)
 		return confirmStatus
 	}
+	// 未启用裁剪时跳过，避免每次确认都扫描全部分支，分支数监控置为0
+	// 切主干时分支状态尚在batch中，留待下次确认时再裁剪
+	if !l.ctx.LedgerCfg.Branch.Enabled() {
+		metrics.LedgerBranchGauge.WithLabelValues(l.ctx.BCName).Set(0)
+	} else if !isRoot && !confirmStatus.TrunkSwitch {
+		branchCount, pruneErr := l.pruneBranches(block, newMeta.TrunkHeight, batchWrite)
+		if pruneErr != nil {
+			confirmStatus.Succ = false
+			l.xlog.Warn("prune side branch fail", "pruneErr", pruneErr)
+			return confirmStatus
+		}
+		metrics.LedgerBranchGauge.WithLabelValues(l.ctx.BCName).Set(float64(branchCount))
+	}
 	txExist, txData := l.parallelCheckTx(realTransactions, block)
 	cbNum := 0
 	oldBlockCache := map[string]*pb.InternalBlock{}
@@ -1238,40 +1311,64 @@ func (l *Ledger) Truncate(utxovmLastID []byte) error {
 
 // VerifyBlock verify block
 func (l *Ledger) VerifyBlock(block *pb.InternalBlock, logid string) (bool, error) {
+	return l.VerifyBlockWithReason(block, logid) == nil, nil
+}
+
+// VerifyBlockWithReason 校验区块，校验失败时返回具体原因，便于判断是否为恶意节点
+func (l *Ledger) VerifyBlockWithReason(block *pb.InternalBlock, logid string) error {
+	// 除创世块外区块至少包含矿工奖励交易，空区块是畸形区块，无需继续校验
+	if len(block.PreHash) != 0 && len(block.Transactions) == 0 {
+		l.xlog.Warn("VerifyBlock empty block", "logid", logid, "blockid", utils.F(block.Blockid))
+		return ErrBlockNoTx
+	}
+	if err := l.VerifyBlockHeader(block, logid); err != nil {
+		return err
+	}
+
+	errv := VerifyMerkle(block)
+	if errv != nil {
+		l.xlog.Warn("VerifyMerkle error", "logid", logid, "error", errv)
+		return fmt.Errorf("%w: %v", ErrBlockMerkleInvalid, errv)
+	}
+
+	if err := l.verifyBlockAward(block); err != nil {
+		l.xlog.Warn("VerifyBlock award error", "logid", logid, "error", err)
+		return err
+	}
+	return nil
+}
+
+// VerifyBlockHeader 只校验区块头：区块id、矿工公钥与地址以及区块签名，不依赖交易内容，
+// 轻节点只同步区块头时使用，交易内容需在获取后再通过VerifyMerkle与区块头中的merkle root比对
+func (l *Ledger) VerifyBlockHeader(block *pb.InternalBlock, logid string) error {
 	blkid, err := MakeBlockID(block)
 	if err != nil {
 		l.xlog.Warn("VerifyBlock MakeBlockID error", "logid", logid, "error", err)
-		return false, nil
+		return fmt.Errorf("%w: %v", ErrBlockIdMismatch, err)
 	}
 	if !(bytes.Equal(blkid, block.Blockid)) {
 		l.xlog.Warn("VerifyBlock equal blockid error", "logid", logid, "redo blockid", utils.F(blkid),
 			"get blockid", utils.F(block.Blockid))
-		return false, nil
-	}
-
-	errv := VerifyMerkle(block)
-	if errv != nil {
-		l.xlog.Warn("VerifyMerkle error", "logid", logid, "error", errv)
-		return false, nil
+		return ErrBlockIdMismatch
 	}
 
 	k, err := l.cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(block.Pubkey))
 	if err != nil {
 		l.xlog.Warn("VerifyBlock get ecdsa from block error", "logid", logid, "error", err)
-		return false, nil
+		return fmt.Errorf("%w: %v", ErrBlockPubkeyInvalid, err)
 	}
 	chkResult, _ := l.cryptoClient.VerifyAddressUsingPublicKey(string(block.Proposer), k)
 	if chkResult == false {
 		l.xlog.Warn("VerifyBlock address is not match publickey", "logid", logid)
-		return false, nil
+		return ErrBlockProposerMismatch
 	}
 
 	valid, err := l.cryptoClient.VerifyECDSA(k, block.Sign, block.Blockid)
 	if err != nil || !valid {
 		l.xlog.Warn("VerifyBlock VerifyECDSA error", "logid", logid, "error", err)
-		return false, nil
+		return ErrBlockSignInvalid
 	}
-	return true, nil
+	return nil
 }
 
 // QueryBlockByTxid query block by txid after it has confirmed
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,11 +15,13 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/metrics"
 	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
 	"github.com/xuperchain/xupercore/protos"
 )
@@ -211,6 +214,11 @@ func TestBasicFunc(t *testing.T) {
 		t.Fatal(err)
 	}
 	t.Log("verify res:", verifyRes)
+	reason := ledger.VerifyBlockWithReason(block2, "1")
+	if verifyRes != (reason == nil) {
+		t.Fatal("VerifyBlockWithReason not consistent with VerifyBlock", reason)
+	}
+	t.Log("verify reason:", reason)
 
 	ledger.Close()
 }
@@ -494,3 +502,195 @@ func TestTruncate(t *testing.T) {
 
 	ledger.Close()
 }
+
+func TestPruneBranches(t *testing.T) {
+	ledger, err := openLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	ledger.ctx.LedgerCfg.Branch.MaxSideBranchDepth = 1
+
+	ecdsaPk, pkErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if pkErr != nil {
+		t.Fatal("fail to generate publice/private key")
+	}
+	t1 := &pb.Transaction{}
+	t1.TxOutputs = append(t1.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	t1.Coinbase = true
+	t1.Desc = []byte(`{"maxblocksize" : "128"}`)
+	t1.Txid, _ = txhash.MakeTransactionID(t1)
+	block1, err := ledger.FormatRootBlock([]*pb.Transaction{t1})
+	if err != nil {
+		t.Fatalf("format block fail, %v", err)
+	}
+	if status := ledger.ConfirmBlock(block1, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	confirm := func(txid string, preHash []byte, ts int64) *pb.InternalBlock {
+		block, err := ledger.FormatBlock([]*pb.Transaction{&pb.Transaction{Txid: []byte(txid)}},
+			[]byte("xchain-Miner-"+txid),
+			ecdsaPk,
+			ts,
+			0,
+			0,
+			preHash, big.NewInt(0),
+		)
+		if err != nil {
+			t.Fatalf("format block fail, %v", err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal("confirm block fail", txid)
+		}
+		return block
+	}
+	// block1 <- block2 <- block4 <- block5
+	//        <- block3
+	block2 := confirm("dummy2", block1.Blockid, 223456789)
+	block3 := confirm("dummy3", block1.Blockid, 223456790)
+	block4 := confirm("dummy4", block2.Blockid, 223456791)
+	if !ledger.ExistBlock(block3.Blockid) {
+		t.Fatal("side branch within max depth should be retained")
+	}
+	confirm("dummy5", block4.Blockid, 223456792)
+	if ledger.ExistBlock(block3.Blockid) {
+		t.Fatal("side branch beyond max depth should be pruned")
+	}
+	if !ledger.ExistBlock(block1.Blockid) || !ledger.ExistBlock(block2.Blockid) {
+		t.Fatal("trunk blocks should not be pruned")
+	}
+	branches, err := ledger.GetBranchInfo(block1.Blockid, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 1 {
+		t.Fatal("unexpected branches after prune", len(branches))
+	}
+}
+
+func TestBranchGaugePruneDisabled(t *testing.T) {
+	ledger, err := openLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	if ledger.ctx.LedgerCfg.Branch.Enabled() {
+		t.Fatal("side branch pruning should be disabled by default")
+	}
+
+	t1 := &pb.Transaction{}
+	t1.TxOutputs = append(t1.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	t1.Coinbase = true
+	t1.Desc = []byte(`{"maxblocksize" : "128"}`)
+	t1.Txid, _ = txhash.MakeTransactionID(t1)
+	block1, err := ledger.FormatRootBlock([]*pb.Transaction{t1})
+	if err != nil {
+		t.Fatalf("format block fail, %v", err)
+	}
+
+	// 指标是全局的，先写入一个非0值，确认区块后未启用裁剪时应当读数为0
+	gauge := metrics.LedgerBranchGauge.WithLabelValues(ledger.ctx.BCName)
+	gauge.Set(7)
+	if status := ledger.ConfirmBlock(block1, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+	var m dto.Metric
+	if err := gauge.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if v := m.GetGauge().GetValue(); v != 0 {
+		t.Fatalf("expect branch gauge 0 when pruning disabled, got %v", v)
+	}
+}
+
+func TestVerifyBlockAward(t *testing.T) {
+	ledger, err := openLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	t1 := &pb.Transaction{}
+	t1.TxOutputs = append(t1.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	t1.Coinbase = true
+	t1.Desc = []byte(`{"maxblocksize" : "128"}`)
+	t1.Txid, _ = txhash.MakeTransactionID(t1)
+	block1, err := ledger.FormatRootBlock([]*pb.Transaction{t1})
+	if err != nil {
+		t.Fatalf("format block fail, %v", err)
+	}
+	if status := ledger.ConfirmBlock(block1, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	ecdsaPk, pkErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if pkErr != nil {
+		t.Fatal("fail to generate publice/private key")
+	}
+	proposer, err := ledger.cryptoClient.GetAddressFromPublicKey(&ecdsaPk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	award := ledger.GenesisBlock.CalcAward(1)
+	formatBlock := func(amount *big.Int) *pb.InternalBlock {
+		awardTx := &pb.Transaction{Coinbase: true, Desc: []byte("award")}
+		awardTx.TxOutputs = append(awardTx.TxOutputs, &protos.TxOutput{Amount: amount.Bytes(), ToAddr: []byte(proposer)})
+		awardTx.Txid, _ = txhash.MakeTransactionID(awardTx)
+		block, err := ledger.FormatBlock([]*pb.Transaction{awardTx},
+			[]byte(proposer),
+			ecdsaPk,
+			223456789,
+			0,
+			0,
+			block1.Blockid, big.NewInt(0),
+		)
+		if err != nil {
+			t.Fatalf("format block fail, %v", err)
+		}
+		return block
+	}
+
+	overAward := new(big.Int).Add(award, big.NewInt(1))
+	// 未到award_check_height的区块不校验奖励，与老版本节点保持一致
+	for _, checkHeight := range []int64{0, 2} {
+		ledger.GenesisBlock.GetConfig().AwardCheckHeight = checkHeight
+		if err := ledger.VerifyBlockWithReason(formatBlock(overAward), "1"); err != nil {
+			t.Fatalf("award should not be checked before height %d: %v", checkHeight, err)
+		}
+	}
+
+	ledger.GenesisBlock.GetConfig().AwardCheckHeight = 1
+	if err := ledger.VerifyBlockWithReason(formatBlock(award), "1"); err != nil {
+		t.Fatal("block with expected award should pass", err)
+	}
+	if err := ledger.VerifyBlockWithReason(formatBlock(overAward), "1"); !errors.Is(err, ErrBlockAwardInvalid) {
+		t.Fatal("over-reward block should be rejected", err)
+	}
+	underAward := new(big.Int).Sub(award, big.NewInt(1))
+	if err := ledger.VerifyBlockWithReason(formatBlock(underAward), "1"); !errors.Is(err, ErrBlockAwardInvalid) {
+		t.Fatal("under-reward block should be rejected", err)
+	}
+	emptyBlock, err := ledger.FormatBlock(nil, []byte(proposer), ecdsaPk, 223456789, 0, 0, block1.Blockid, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("format block fail, %v", err)
+	}
+	if err := ledger.VerifyBlockWithReason(emptyBlock, "1"); err != ErrBlockNoTx {
+		t.Fatal("block without tx should be rejected", err)
+	}
+
+	// 只校验区块头时不需要交易内容
+	header := *formatBlock(award)
+	header.Transactions = nil
+	if err := ledger.VerifyBlockHeader(&header, "1"); err != nil {
+		t.Fatal("header without txs should pass", err)
+	}
+	header.Sign = []byte("forged")
+	if err := ledger.VerifyBlockHeader(&header, "1"); err != ErrBlockSignInvalid {
+		t.Fatal("header with bad sign should be rejected", err)
+	}
+	header.Timestamp++
+	if err := ledger.VerifyBlockHeader(&header, "1"); err != ErrBlockIdMismatch {
+		t.Fatal("tampered header should be rejected", err)
+	}
+}
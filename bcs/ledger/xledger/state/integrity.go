@@ -0,0 +1,42 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// VerifyRecentBlocks 检查状态机最近depth个区块的写集是否都已落在状态库中，
+// 用于启动时发现状态库损坏（声称与账本一致但实际数据缺失）的情况
+func (t *State) VerifyRecentBlocks(depth int64) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	blockid := t.GetLatestBlockid()
+	for i := int64(0); i < depth && len(blockid) > 0; i++ {
+		block, err := t.sctx.Ledger.QueryBlock(blockid)
+		if err != nil {
+			return fmt.Errorf("query block %s failed: %v", utils.F(blockid), err)
+		}
+		for _, tx := range block.GetTransactions() {
+			for _, output := range tx.GetTxOutputsExt() {
+				verData, err := t.xmodel.Get(output.GetBucket(), output.GetKey())
+				if err != nil {
+					return fmt.Errorf("get state of tx %s at height %d failed: %v",
+						utils.F(tx.GetTxid()), block.GetHeight(), err)
+				}
+				// 被后续交易覆盖或删除的key仍然会有版本记录，没有任何版本说明写集丢失
+				if len(verData.GetRefTxid()) == 0 {
+					return fmt.Errorf("state diverged from ledger: missing key %s/%s written by tx %s at height %d",
+						output.GetBucket(), output.GetKey(), utils.F(tx.GetTxid()), block.GetHeight())
+				}
+			}
+		}
+		if block.GetHeight() == 0 {
+			break
+		}
+		blockid = block.GetPreHash()
+	}
+	return nil
+}
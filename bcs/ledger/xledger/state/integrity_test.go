@@ -0,0 +1,116 @@
+package state
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestVerifyRecentBlocks(t *testing.T) {
+	workspace, dirErr := ioutil.TempDir("/tmp", "")
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+	os.RemoveAll(workspace)
+	defer os.RemoveAll(workspace)
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	lctx, err := ledger_pkg.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = workspace
+	ledger, err := ledger_pkg.CreateLedger(lctx, GenesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	rootTx, err := txn.GenerateRootTx([]byte(`{"version": "1", "predistribution": [{"address": "` + BobAddress +
+		`", "quota": "10000000"}], "maxblocksize": "128", "award": "1000"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := ledger.FormatRootBlock([]*pb.Transaction{rootTx})
+	if status := ledger.ConfirmBlock(root, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", ledger, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx.EnvCfg.ChainDir = workspace
+	stateHandle, err := NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stateHandle.Close()
+	if err := stateHandle.Play(root.Blockid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 生成写入key的区块并确认到账本
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writeBlock := func(preHash []byte, key string) *pb.InternalBlock {
+		tx := &pb.Transaction{
+			Autogen:      true,
+			Version:      1,
+			TxInputsExt:  []*protos.TxInputExt{{Bucket: "integrity", Key: []byte(key)}},
+			TxOutputsExt: []*protos.TxOutputExt{{Bucket: "integrity", Key: []byte(key), Value: []byte("v")}},
+		}
+		if tx.Txid, err = txhash.MakeTransactionID(tx); err != nil {
+			t.Fatal(err)
+		}
+		block, err := ledger.FormatBlock([]*pb.Transaction{tx}, []byte("miner-1"), ecdsaPk, 123456789, 0, 0,
+			preHash, stateHandle.GetTotal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal("confirm block fail")
+		}
+		return block
+	}
+
+	played := writeBlock(root.Blockid, "played")
+	if err := stateHandle.PlayForMiner(played.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateHandle.VerifyRecentBlocks(10); err != nil {
+		t.Fatal(err)
+	}
+
+	// 状态机声称已执行到最新区块，但该区块的写集没有落在状态库中
+	lost := writeBlock(played.Blockid, "lost")
+	stateHandle.latestBlockid = lost.Blockid
+	err = stateHandle.VerifyRecentBlocks(10)
+	if err == nil || !strings.Contains(err.Error(), "missing key integrity/lost") {
+		t.Fatalf("expect state diverged error, got %v", err)
+	}
+	// 检查深度为0时不检查
+	if err := stateHandle.VerifyRecentBlocks(0); err != nil {
+		t.Fatal(err)
+	}
+}
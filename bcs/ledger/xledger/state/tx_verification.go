@@ -878,6 +878,21 @@ func (t *State) queryAccountACL(accountName string) (*protos.Acl, error) {
 	return t.sctx.AclMgr.GetAccountACL(accountName)
 }
 
+// VerifyBlockTxs 校验区块中交易的合法性，区块需接在状态机最新区块之后，不修改状态机。
+// 未确认交易池中已有的交易入池时已校验过，与Play时一样跳过
+func (t *State) VerifyBlockTxs(block *pb.InternalBlock) error {
+	if !bytes.Equal(block.PreHash, t.latestBlockid) {
+		return ErrPreBlockMissMatch
+	}
+	verified := map[string]bool{}
+	for _, tx := range block.Transactions {
+		if t.tx.Mempool.HasTx(string(tx.GetTxid())) {
+			verified[string(tx.GetTxid())] = true
+		}
+	}
+	return t.verifyBlockTxs(block, false, verified)
+}
+
 func (t *State) verifyBlockTxs(block *pb.InternalBlock, isRootTx bool, unconfirmToConfirm map[string]bool) error {
 	var err error
 	var once sync.Once
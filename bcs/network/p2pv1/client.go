@@ -3,6 +3,7 @@ package p2pv1
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -50,10 +51,11 @@ func (p *P2PServerV1) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage, optFu
 
 	p.log.Trace("SendMessage", "log_id", msg.GetHeader().GetLogid(),
 		"msgType", msg.GetHeader().GetType(), "checksum", msg.GetHeader().GetDataCheckSum(), "peerID", peerIDs)
-	return p.sendMessage(ctx, msg, peerIDs)
+	return p.sendMessage(ctx, msg, peerIDs, opt)
 }
 
-func (p *P2PServerV1) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []string) error {
+func (p *P2PServerV1) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []string, opt *p2p.Option) error {
+	var delivered int32
 	wg := sync.WaitGroup{}
 	for _, peerID := range peerIDs {
 		peerID := peerID
@@ -67,16 +69,17 @@ func (p *P2PServerV1) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerI
 		wg.Add(1)
 		go func(conn *Conn) {
 			defer wg.Done()
-			err = conn.SendMessage(ctx, msg)
-			if err != nil {
+			if err := conn.SendMessage(ctx, msg); err != nil {
 				p.log.Warn("p2p: SendMessage error",
 					"log_id", msg.GetHeader().GetLogid(), "peerID", conn.id, "error", err)
+				return
 			}
+			atomic.AddInt32(&delivered, 1)
 		}(conn)
 	}
 	wg.Wait()
 
-	return nil
+	return opt.CheckDelivered(int(delivered))
 }
 
 // SendMessageWithResponse send message to peers using given filter strategy, expect response from peers
@@ -112,10 +115,15 @@ func (p *P2PServerV1) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 
 	p.log.Trace("SendMessageWithResponse", "log_id", msg.GetHeader().GetLogid(),
 		"msgType", msg.GetHeader().GetType(), "checksum", msg.GetHeader().GetDataCheckSum(), "peerID", peerIDs)
-	return p.sendMessageWithResponse(ctx, msg, peerIDs, opt.Percent)
+	return p.sendMessageWithResponse(ctx, msg, peerIDs, opt)
 }
 
-func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []string, percent float32) ([]*pb.XuperMessage, error) {
+func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []string, opt *p2p.Option) ([]*pb.XuperMessage, error) {
+	// 提前返回或超时后取消尚未返回的请求
+	respCtx, cancel := p2p.WithResponseContext(ctx, opt)
+	defer cancel()
+	connCtx := &xctx.BaseCtx{Context: respCtx, XLog: ctx.GetLog(), Timer: ctx.GetTimer()}
+
 	wg := sync.WaitGroup{}
 	respCh := make(chan *pb.XuperMessage, len(peerIDs))
 	for _, peerID := range peerIDs {
@@ -131,7 +139,7 @@ func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 		go func(conn *Conn) {
 			defer wg.Done()
 
-			resp, err := conn.SendMessageWithResponse(ctx, msg)
+			resp, err := conn.SendMessageWithResponse(connCtx, msg)
 			if err != nil {
 				return
 			}
@@ -139,28 +147,19 @@ func (p *P2PServerV1) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 			respCh <- resp
 		}(conn)
 	}
-	wg.Wait()
 
-	if len(respCh) <= 0 {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	response := p2p.CollectResponses(respCtx, respCh, done, len(peerIDs), opt)
+	if len(response) <= 0 {
 		p.log.Warn("p2p: no response", "log_id", msg.GetHeader().GetLogid())
 		return nil, ErrNoResponse
 	}
 
-	i := 0
-	length := len(respCh)
-	threshold := int(float32(len(peerIDs)) * percent)
-	response := make([]*pb.XuperMessage, 0, len(peerIDs))
-	for resp := range respCh {
-		if p2p.VerifyChecksum(resp) {
-			response = append(response, resp)
-		}
-
-		i++
-		if i >= length || len(response) >= threshold {
-			break
-		}
-	}
-
 	return response, nil
 }
 
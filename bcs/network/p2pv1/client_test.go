@@ -0,0 +1,78 @@
+package p2pv1
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/timer"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// recvService 接收消息后回复，作为可以正常投递的节点
+type recvService struct {
+	pb.UnimplementedP2PServiceServer
+}
+
+func (s *recvService) SendP2PMessage(stream pb.P2PService_SendP2PMessageServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	return stream.Send(msg)
+}
+
+func startRecvPeer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterP2PServiceServer(server, &recvService{})
+	go server.Serve(l)
+	t.Cleanup(server.Stop)
+	return l.Addr().String()
+}
+
+// deadPeer 返回一个没有监听的地址，发往该节点的消息一定失败
+func deadPeer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func TestSendMessageMinDelivered(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, _ := mock.NewEnvConfForTest("p2pv1/node3/conf/env.yaml")
+	ctx, err := nctx.NewNetCtx(ecfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, _ := NewConnPool(ctx)
+	p := &P2PServerV1{ctx: ctx, log: ctx.GetLog(), config: ctx.P2PConf, pool: pool}
+
+	peers := []string{startRecvPeer(t), deadPeer(t)}
+	send := func(minDelivered int) error {
+		msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+		opt := p2p.Apply([]p2p.OptionFunc{p2p.WithMinDelivered(minDelivered)})
+		return p.sendMessage(&xctx.BaseCtx{XLog: ctx.GetLog(), Timer: timer.NewXTimer()}, msg, peers, opt)
+	}
+
+	// 只有一个节点投递成功
+	if err := send(1); err != nil {
+		t.Fatalf("expect delivered to one peer, got %v", err)
+	}
+	if err := send(2); !errors.Is(err, p2p.ErrNotEnoughDelivered) {
+		t.Fatalf("expect not enough delivered, got %v", err)
+	}
+}
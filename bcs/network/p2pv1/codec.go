@@ -0,0 +1,44 @@
+package p2pv1
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// sizeCheckCodec grpc服务端编解码，反序列化收到的XuperMessage之前先按消息类型检查大小，
+// 避免业务层限制只能在整条消息反序列化之后才生效
+type sizeCheckCodec struct {
+	limiter *p2p.SizeLimiter
+}
+
+func (c *sizeCheckCodec) Marshal(v interface{}) ([]byte, error) {
+	return proto.Marshal(v.(proto.Message))
+}
+
+func (c *sizeCheckCodec) Unmarshal(data []byte, v interface{}) error {
+	if _, ok := v.(*pb.XuperMessage); ok {
+		if err := c.limiter.CheckFrame(data); err != nil {
+			return err
+		}
+	}
+	return proto.Unmarshal(data, v.(proto.Message))
+}
+
+func (c *sizeCheckCodec) String() string {
+	return "proto"
+}
+
+// isOversize 判断接收消息的错误是否由消息超限引起，包括传输层的MaxMessageSize和编解码时的类型限制
+func isOversize(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	return st.Code() == codes.ResourceExhausted || strings.Contains(st.Message(), p2p.ErrMessageTooLarge.Error())
+}
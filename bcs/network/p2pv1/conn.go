@@ -10,6 +10,7 @@ import (
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/connectivity"
 
 	"github.com/xuperchain/xupercore/kernel/network/config"
@@ -71,6 +72,19 @@ func (c *Conn) newConn() error {
 		options = append(options, grpc.WithInsecure())
 	}
 
+	// grpc连接断开后自行重连，没有重试次数的限制，只使用超时和退避间隔
+	if c.config.DialTimeout > 0 {
+		options = append(options, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  time.Duration(c.config.ReconnectBackoff) * time.Second,
+				Multiplier: backoff.DefaultConfig.Multiplier,
+				Jitter:     backoff.DefaultConfig.Jitter,
+				MaxDelay:   time.Duration(c.config.MaxReconnectBackoff) * time.Second,
+			},
+			MinConnectTimeout: time.Duration(c.config.DialTimeout) * time.Second,
+		}))
+	}
+
 	conn, err := grpc.Dial(c.id, options...)
 	if err != nil {
 		c.log.Error("newGrpcConn error", "error", err, "peerID", c.id)
@@ -54,6 +54,7 @@ func (p *P2PServerV1) GetPeer(peerInfo pb.PeerInfo, addr string) []*pb.PeerInfo
 		}
 		peer.Address = addr
 		p.accounts.Set(peer.GetAccount(), peer.GetAddress(), cache.NoExpiration)
+		p.capabilities.Set(peer.GetAddress(), peer.GetCapabilities(), cache.NoExpiration)
 		remotePeers = append(remotePeers, &peer)
 	}
 	return remotePeers
@@ -96,6 +97,7 @@ func (p *P2PServerV1) handleGetPeerInfo(ctx xctx.XContext, request *pb.XuperMess
 		}
 		p.accounts.Set(peerInfo.GetAccount(), peerInfo.GetAddress(), cache.NoExpiration)
 	}
+	p.capabilities.Set(peerInfo.GetAddress(), peerInfo.GetCapabilities(), cache.NoExpiration)
 
 	return resp, nil
 }
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -14,6 +15,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	grpcpeer "google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
@@ -55,10 +57,15 @@ type P2PServerV1 struct {
 	staticNodes  map[string][]string
 	dynamicNodes []string
 
-	// local host account
-	account string
+	// local host account, replaced when the miner key rotates
+	accountMutex sync.RWMutex
+	account      string
 	// accounts store remote peer account: key:account => v:peer.ID
 	accounts *cache.Cache
+	// capabilities store remote peer capabilities: key:peer.ID => v:[]string
+	capabilities *cache.Cache
+	// sizeLimiter reject oversized messages and ban peers who keep sending them
+	sizeLimiter *p2p.SizeLimiter
 }
 
 var _ p2p.Server = &P2PServerV1{}
@@ -103,6 +110,12 @@ func (p *P2PServerV1) Init(ctx *nctx.NetCtx) error {
 		return ErrLoadAccount
 	}
 	p.accounts = cache.New(cache.NoExpiration, cache.NoExpiration)
+	p.capabilities = cache.New(cache.NoExpiration, cache.NoExpiration)
+	p.sizeLimiter, err = p2p.NewSizeLimiter(p.config)
+	if err != nil {
+		p.log.Error("create size limiter error", "error", err)
+		return err
+	}
 
 	p.bootNodes = make([]string, 0)
 	p.staticNodes = make(map[string][]string, 0)
@@ -123,13 +136,18 @@ func (p *P2PServerV1) Stop() {
 	p.log.Info("StopP2PServer", "address", p.config.Address)
 }
 
+// serverOptions grpc服务端选项，按MaxMessageSize限制传输层消息大小，反序列化前按类型检查消息大小
+func (p *P2PServerV1) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(p.config.MaxMessageSize) << 20),
+		grpc.MaxSendMsgSize(int(p.config.MaxMessageSize) << 20),
+		grpc.CustomCodec(&sizeCheckCodec{limiter: p.sizeLimiter}),
+	}
+}
+
 // serve
 func (p *P2PServerV1) serve() {
-	options := append([]grpc.ServerOption{},
-		grpc.MaxRecvMsgSize(int(p.config.MaxMessageSize)<<20),
-		grpc.MaxSendMsgSize(int(p.config.MaxMessageSize)<<20),
-	)
-
+	options := p.serverOptions()
 	if p.config.IsTls {
 		creds, err := p2p.NewTLS(p.config.KeyPath, p.config.ServiceName)
 		if err != nil {
@@ -165,9 +183,23 @@ func (p *P2PServerV1) serve() {
 
 // SendP2PMessage implement the SendP2PMessageServer
 func (p *P2PServerV1) SendP2PMessage(stream pb.P2PService_SendP2PMessageServer) error {
+	// p2pv1的消息来源未经认证，按连接的对端ip统计
+	remote := ""
+	if pr, ok := grpcpeer.FromContext(stream.Context()); ok {
+		remote, _, _ = net.SplitHostPort(pr.Addr.String())
+	}
+	if p.sizeLimiter.IsBanned(remote, time.Now()) {
+		p.log.Warn("reject message from banned peer", "remote", remote)
+		return p2p.ErrPeerBanned
+	}
+
 	msg, err := stream.Recv()
 	if err != nil {
-		p.log.Warn("SendP2PMessage Recv msg error", "error", err)
+		// 超过传输层或类型限制的消息在反序列化前被拒绝，同样计入发送方的超限次数
+		if isOversize(err) {
+			p.sizeLimiter.RecordOversize(remote, time.Now())
+		}
+		p.log.Warn("SendP2PMessage Recv msg error", "remote", remote, "error", err)
 		return err
 	}
 
@@ -184,6 +216,12 @@ func (p *P2PServerV1) SendP2PMessage(stream pb.P2PService_SendP2PMessageServer)
 		}()
 	}
 
+	if err = p.sizeLimiter.Check(remote, msg); err != nil {
+		p.log.Warn("reject message by size limit", "log_id", msg.GetHeader().GetLogid(),
+			"type", msg.GetHeader().GetType(), "remote", remote, "error", err)
+		return err
+	}
+
 	if err = p.dispatcher.Dispatch(msg, stream); err != nil {
 		p.log.Warn("handle new message dispatch error", "log_id", msg.GetHeader().GetLogid(),
 			"type", msg.GetHeader().GetType(), "from", msg.GetHeader().GetFrom(), "error", err)
@@ -208,6 +246,35 @@ func (p *P2PServerV1) Context() *nctx.NetCtx {
 	return p.ctx
 }
 
+// PeerCount 返回连接就绪的邻居节点数
+func (p *P2PServerV1) PeerCount() int {
+	return len(p.pool.GetAll())
+}
+
+// SetAccount 替换本节点宣告的账户，并向已连接的节点重新同步节点信息
+func (p *P2PServerV1) SetAccount(account string) {
+	p.accountMutex.Lock()
+	old := p.account
+	p.account = account
+	p.accountMutex.Unlock()
+	p.accounts.Delete(old)
+
+	peers := p.pool.GetAll()
+	addresses := make([]string, 0, len(peers))
+	for _, address := range peers {
+		addresses = append(addresses, address)
+	}
+	if len(addresses) > 0 {
+		go p.GetPeerInfo(addresses)
+	}
+}
+
+func (p *P2PServerV1) localAccount() string {
+	p.accountMutex.RLock()
+	defer p.accountMutex.RUnlock()
+	return p.account
+}
+
 func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 	_, ip, err := manet.DialArgs(p.address)
 	if err != nil {
@@ -215,9 +282,10 @@ func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 	}
 
 	peerInfo := pb.PeerInfo{
-		Id:      ip,
-		Address: ip,
-		Account: p.account,
+		Id:           ip,
+		Address:      ip,
+		Account:      p.localAccount(),
+		Capabilities: p2p.LocalCapabilities(),
 	}
 
 	accounts := p.accounts.Items()
@@ -235,9 +303,10 @@ func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 		}
 
 		remotePeerInfo := &pb.PeerInfo{
-			Id:      id,
-			Address: address,
-			Account: peerID2Accounts[id],
+			Id:           id,
+			Address:      address,
+			Account:      peerID2Accounts[id],
+			Capabilities: p.PeerCapabilities(id),
 		}
 		peerInfo.Peer = append(peerInfo.Peer, remotePeerInfo)
 	}
@@ -245,6 +314,14 @@ func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 	return peerInfo
 }
 
+// PeerCapabilities return capabilities advertised by remote peer in GET_PEER_INFO handshake
+func (p *P2PServerV1) PeerCapabilities(peerID string) []string {
+	if caps, ok := p.capabilities.Get(peerID); ok {
+		return caps.([]string)
+	}
+	return nil
+}
+
 // connectBootNodes connect to boot node
 func (p *P2PServerV1) connectBootNodes() {
 	p.bootNodes = p.config.BootNodes
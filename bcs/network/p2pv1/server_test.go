@@ -1,10 +1,15 @@
 package p2pv1
 
 import (
-	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"context"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	nctx "github.com/xuperchain/xupercore/kernel/network/context"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
@@ -97,3 +102,75 @@ func TestP2PServerV1(t *testing.T) {
 	startNode3(t)
 	time.Sleep(time.Second)
 }
+
+func TestSendP2PMessageOversize(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, _ := mock.NewEnvConfForTest("p2pv1/node3/conf/env.yaml")
+	ctx, err := nctx.NewNetCtx(ecfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := *ctx.P2PConf
+	cfg.MessageSizeLimits = map[string]int64{"posttx": 1}
+	cfg.OversizeBanThreshold = 2
+	cfg.OversizeBanTime = 60
+	limiter, err := p2p.NewSizeLimiter(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &P2PServerV1{ctx: ctx, log: ctx.GetLog(), config: &cfg, sizeLimiter: limiter}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(p.serverOptions()...)
+	pb.RegisterP2PServiceServer(server, p)
+	go server.Serve(l)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	send := func(size int) error {
+		stream, err := pb.NewP2PServiceClient(conn).SendP2PMessage(context.Background())
+		if err != nil {
+			return err
+		}
+		msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+		msg.Data.MsgInfo = make([]byte, size)
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	// 编解码时按类型检查，超限的消息不会反序列化
+	codec := &sizeCheckCodec{limiter: limiter}
+	oversized := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+	oversized.Data.MsgInfo = make([]byte, 2048)
+	data, err := codec.Marshal(oversized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded pb.XuperMessage
+	if err := codec.Unmarshal(data, &decoded); err != p2p.ErrMessageTooLarge || decoded.Data != nil {
+		t.Fatalf("expect rejected before unmarshal, got %v", err)
+	}
+
+	// 超过类型限制的消息被拒绝，并计入发送方的超限次数
+	for i := 0; i < cfg.OversizeBanThreshold; i++ {
+		if err := send(2048); err == nil {
+			t.Fatal("oversized message should be rejected")
+		}
+	}
+	if !limiter.IsBanned("127.0.0.1", time.Now()) {
+		t.Fatal("peer sending oversized messages should be banned")
+	}
+	if err := send(1); err == nil || !strings.Contains(err.Error(), p2p.ErrPeerBanned.Error()) {
+		t.Fatalf("expect banned peer rejected, got %v", err)
+	}
+}
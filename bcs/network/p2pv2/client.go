@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -70,10 +71,11 @@ func (p *P2PServerV2) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage,
 	}
 
 	ctx.GetLog().SetInfoField("peerCount", len(peerIDs))
-	return p.sendMessage(ctx, msg, peerIDs)
+	return p.sendMessage(ctx, msg, peerIDs, opt)
 }
 
-func (p *P2PServerV2) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []peer.ID) error {
+func (p *P2PServerV2) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerIDs []peer.ID, opt *p2p.Option) error {
+	var delivered int32
 	var wg sync.WaitGroup
 	for _, peerID := range peerIDs {
 		wg.Add(1)
@@ -100,11 +102,12 @@ func (p *P2PServerV2) sendMessage(ctx xctx.XContext, msg *pb.XuperMessage, peerI
 					"msgType", msg.GetHeader().GetType(), "error", err)
 				return
 			}
+			atomic.AddInt32(&delivered, 1)
 		}(peerID)
 	}
 	wg.Wait()
 	ctx.GetTimer().Mark("send")
-	return nil
+	return opt.CheckDelivered(int(delivered))
 }
 
 // SendMessageWithResponse send message to peers using given filter strategy, expect response from peers
@@ -159,13 +162,17 @@ func (p *P2PServerV2) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 func (p *P2PServerV2) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMessage,
 	peerIDs []peer.ID, opt *p2p.Option) ([]*pb.XuperMessage, error) {
 
+	// 提前返回或超时后取消尚未返回的请求
+	respCtx, cancel := p2p.WithResponseContext(ctx, opt)
+	defer cancel()
+
 	respCh := make(chan *pb.XuperMessage, len(peerIDs))
 	var wg sync.WaitGroup
 	ctx.GetLog().Debug("sendMessageWithResponse peers", "peers", peerIDs)
 	for _, peerID := range peerIDs {
 		wg.Add(1)
 		go func(peerID peer.ID) {
-			streamCtx := &xctx.BaseCtx{XLog: ctx.GetLog(), Timer: timer.NewXTimer()}
+			streamCtx := &xctx.BaseCtx{Context: respCtx, XLog: ctx.GetLog(), Timer: timer.NewXTimer()}
 			defer func() {
 				wg.Done()
 				streamCtx.GetLog().Debug("SendMessageWithResponse", "log_id", msg.GetHeader().GetLogid(),
@@ -191,31 +198,21 @@ func (p *P2PServerV2) sendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 			respCh <- resp
 		}(peerID)
 	}
-	wg.Wait()
-	ctx.GetTimer().Mark("send")
 
-	if len(respCh) <= 0 {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	response := p2p.CollectResponses(respCtx, respCh, done, len(peerIDs), opt)
+	ctx.GetTimer().Mark("recv")
+	if len(response) <= 0 {
 		p.log.Warn("p2p: no response", "log_id", msg.GetHeader().GetLogid(),
 			"msgType", msg.GetHeader().GetType())
 		return nil, ErrNoResponse
 	}
 
-	i := 0
-	length := len(respCh)
-	threshold := int(float32(len(peerIDs)) * opt.Percent)
-	response := make([]*pb.XuperMessage, 0, len(peerIDs))
-	for resp := range respCh {
-		if p2p.VerifyChecksum(resp) {
-			response = append(response, resp)
-		}
-
-		i++
-		if i >= length || len(response) >= threshold {
-			break
-		}
-	}
-
-	ctx.GetTimer().Mark("recv")
 	return response, nil
 }
 
@@ -0,0 +1,70 @@
+package p2pv2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/multiformats/go-multiaddr"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/timer"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// startIsolatedNode 使用随机端口启动节点，不连接bootNodes
+func startIsolatedNode(t *testing.T, conf string) *P2PServerV2 {
+	ecfg, err := mock.NewEnvConfForTest(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := nctx.NewNetCtx(ecfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.P2PConf.Address = "/ip4/127.0.0.1/tcp/0"
+	ctx.P2PConf.BootNodes = nil
+
+	node := NewP2PServerV2().(*P2PServerV2)
+	if err := node.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	node.Start()
+	t.Cleanup(node.Stop)
+	return node
+}
+
+func TestSendMessageMinDelivered(t *testing.T) {
+	mock.InitLogForTest()
+	recv := startIsolatedNode(t, "p2pv2/node1/conf/env.yaml")
+	sender := startIsolatedNode(t, "p2pv2/node3/conf/env.yaml")
+	sender.host.Peerstore().AddAddrs(recv.host.ID(), recv.host.Addrs(), peerstore.PermanentAddrTTL)
+
+	// node2没有启动，建立流一定失败
+	dead, err := peer.Decode("QmQKp8pLWSgV4JiGjuULKV1JsdpxUtnDEUMP8sGaaUbwVL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadAddr, _ := multiaddr.NewMultiaddr("/ip4/127.0.0.1/tcp/1")
+	sender.host.Peerstore().AddAddrs(dead, []multiaddr.Multiaddr{deadAddr}, peerstore.PermanentAddrTTL)
+
+	peers := []peer.ID{recv.host.ID(), dead}
+	send := func(minDelivered int) error {
+		ctx := &xctx.BaseCtx{XLog: sender.log, Timer: timer.NewXTimer()}
+		msg := p2p.NewMessage(pb.XuperMessage_POSTTX, nil)
+		opt := p2p.Apply([]p2p.OptionFunc{p2p.WithMinDelivered(minDelivered)})
+		return sender.sendMessage(ctx, msg, peers, opt)
+	}
+
+	// 只有一个节点投递成功
+	if err := send(1); err != nil {
+		t.Fatalf("expect delivered to one peer, got %v", err)
+	}
+	if err := send(2); !errors.Is(err, p2p.ErrNotEnoughDelivered) {
+		t.Fatalf("expect not enough delivered, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package p2pv2
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/routing"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	// 查到的dht记录缓存有效期，节点升级重启后重新从dht获取
+	dhtValueCacheExpired = 5 * time.Minute
+	// 对端未发布记录(如老版本节点没有宣告能力)时空结果的缓存有效期
+	dhtNotFoundCacheExpired = 30 * time.Minute
+	// 超时等临时错误的缓存有效期，避免每次调用都阻塞在dht查询上，同时尽快重试
+	dhtFailureCacheExpired = 30 * time.Second
+	dhtCacheGCInterval     = 10 * time.Minute
+)
+
+type dhtResult struct {
+	value []byte
+	err   error
+}
+
+// dhtCache 缓存dht查询结果，查询在timeout内完成，失败的结果同样缓存
+type dhtCache struct {
+	cache   *cache.Cache
+	timeout time.Duration
+	get     func(ctx context.Context, key string) ([]byte, error)
+}
+
+func newDhtCache(timeout time.Duration, get func(ctx context.Context, key string) ([]byte, error)) *dhtCache {
+	return &dhtCache{
+		cache:   cache.New(dhtValueCacheExpired, dhtCacheGCInterval),
+		timeout: timeout,
+		get:     get,
+	}
+}
+
+// lookup 查询key对应的dht记录，优先使用缓存
+func (d *dhtCache) lookup(ctx context.Context, key string) ([]byte, error) {
+	if v, ok := d.cache.Get(key); ok {
+		result := v.(dhtResult)
+		return result.value, result.err
+	}
+
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	value, err := d.get(ctx, key)
+
+	expire := cache.DefaultExpiration
+	switch {
+	case err == nil:
+	case errors.Is(err, routing.ErrNotFound):
+		expire = dhtNotFoundCacheExpired
+	default:
+		expire = dhtFailureCacheExpired
+	}
+	d.cache.Set(key, dhtResult{value: value, err: err}, expire)
+	return value, err
+}
@@ -0,0 +1,57 @@
+package p2pv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/routing"
+)
+
+func TestDhtCacheLookup(t *testing.T) {
+	calls := map[string]int{}
+	get := func(ctx context.Context, key string) ([]byte, error) {
+		calls[key]++
+		switch key {
+		case "found":
+			return []byte("value"), nil
+		case "missing":
+			return nil, routing.ErrNotFound
+		default:
+			// 不可达的记录一直阻塞到超时
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+	}
+	d := newDhtCache(50*time.Millisecond, get)
+
+	for i := 0; i < 2; i++ {
+		value, err := d.lookup(context.Background(), "found")
+		if err != nil || string(value) != "value" {
+			t.Fatalf("unexpected lookup result: %s, %v", value, err)
+		}
+		if _, err := d.lookup(context.Background(), "missing"); !errors.Is(err, routing.ErrNotFound) {
+			t.Fatalf("expect not found, got %v", err)
+		}
+
+		begin := time.Now()
+		if _, err := d.lookup(context.Background(), "unreachable"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expect deadline exceeded, got %v", err)
+		}
+		if cost := time.Since(begin); cost > time.Second {
+			t.Fatalf("lookup should be bounded by timeout, cost %v", cost)
+		}
+	}
+	for key, n := range calls {
+		if n != 1 {
+			t.Fatalf("%s should be looked up once, got %d", key, n)
+		}
+	}
+
+	// 临时错误的缓存时间短于记录不存在的缓存时间
+	items := d.cache.Items()
+	if items["unreachable"].Expiration >= items["missing"].Expiration {
+		t.Fatal("transient failure should expire before not found result")
+	}
+}
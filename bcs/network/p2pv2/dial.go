@@ -0,0 +1,69 @@
+package p2pv2
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/xuperchain/xupercore/kernel/network/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// dialPolicy 连接启动节点和静态节点时的超时和重试策略
+type dialPolicy struct {
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	sleep      func(time.Duration)
+}
+
+func newDialPolicy(cfg *config.NetConf) *dialPolicy {
+	return &dialPolicy{
+		timeout:    time.Duration(cfg.DialTimeout) * time.Second,
+		retries:    cfg.MaxConnectRetries,
+		backoff:    time.Duration(cfg.ReconnectBackoff) * time.Second,
+		maxBackoff: time.Duration(cfg.MaxReconnectBackoff) * time.Second,
+		sleep:      time.Sleep,
+	}
+}
+
+// wait 返回第attempt次重试前的等待时间，每次重试翻倍，不超过maxBackoff
+func (d *dialPolicy) wait(attempt int) time.Duration {
+	wait := d.backoff
+	for i := 1; i < attempt && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+// connect 逐个连接对端节点，全部失败时按退避间隔重试，超过重试次数后放弃，返回连接成功的节点数
+func (d *dialPolicy) connect(ctx context.Context, log logs.Logger, addrInfos []peer.AddrInfo,
+	dial func(context.Context, peer.AddrInfo) error) int {
+	success := 0
+	for attempt := 1; attempt <= d.retries; attempt++ {
+		for _, addrInfo := range addrInfos {
+			dctx, cancel := context.WithTimeout(ctx, d.timeout)
+			err := dial(dctx, addrInfo)
+			cancel()
+			if err != nil {
+				log.Error("p2p: connection with peer node error", "error", err, "attempt", attempt)
+				continue
+			}
+
+			success++
+			log.Info("p2p: connection established", "addrInfo", addrInfo)
+		}
+
+		if success > 0 || attempt == d.retries {
+			break
+		}
+		d.sleep(d.wait(attempt))
+	}
+
+	return success
+}
@@ -0,0 +1,69 @@
+package p2pv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestDialPolicyGiveUp(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "p2pv2")
+
+	cfg := config.GetDefP2PConf()
+	cfg.DialTimeout = 2
+	cfg.MaxConnectRetries = 5
+	cfg.ReconnectBackoff = 1
+	cfg.MaxReconnectBackoff = 4
+	policy := newDialPolicy(cfg)
+	var waits []time.Duration
+	policy.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	dials := 0
+	refuse := func(ctx context.Context, addrInfo peer.AddrInfo) error {
+		dials++
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 2*time.Second {
+			t.Errorf("dial should be bounded by dial timeout")
+		}
+		return errors.New("connection refused")
+	}
+	success := policy.connect(context.Background(), log, []peer.AddrInfo{{ID: "peer"}}, refuse)
+	if success != 0 {
+		t.Fatalf("expect no connection, got %d", success)
+	}
+	if dials != cfg.MaxConnectRetries {
+		t.Fatalf("expect %d dials, got %d", cfg.MaxConnectRetries, dials)
+	}
+	expect := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+	if len(waits) != len(expect) {
+		t.Fatalf("expect waits %v, got %v", expect, waits)
+	}
+	for i := range expect {
+		if waits[i] != expect[i] {
+			t.Fatalf("expect waits %v, got %v", expect, waits)
+		}
+	}
+
+	// 第二轮连接成功后不再重试
+	dials, waits = 0, nil
+	flaky := func(ctx context.Context, addrInfo peer.AddrInfo) error {
+		dials++
+		if dials < 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	if success := policy.connect(context.Background(), log, []peer.AddrInfo{{ID: "peer"}}, flaky); success != 1 {
+		t.Fatalf("expect 1 connection, got %d", success)
+	}
+	if dials != 2 || len(waits) != 1 {
+		t.Fatalf("expect 2 dials and 1 wait, got %d dials and waits %v", dials, waits)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/xuperchain/xupercore/lib/metrics"
+	"sync"
 	"time"
 
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
@@ -35,7 +36,6 @@ const (
 	ServerName = "p2pv2"
 
 	namespace = "xuper"
-	retry     = 10
 )
 
 func init() {
@@ -84,11 +84,16 @@ type P2PServerV2 struct {
 
 	staticNodes map[string][]peer.ID
 
-	// local host account
-	account string
+	// local host account, replaced when the miner key rotates
+	accountMutex sync.RWMutex
+	account      string
 	// accounts store remote peer account: key:account => v:peer.ID
 	// accounts as cache, store in dht
 	accounts *cache.Cache
+	// dhtValues caches remote peer records looked up in dht, such as id=>account and capabilities
+	dhtValues *dhtCache
+	// sizeLimiter reject oversized messages and ban peers who keep sending them
+	sizeLimiter *p2p.SizeLimiter
 }
 
 var _ p2p.Server = &P2PServerV2{}
@@ -148,6 +153,14 @@ func (p *P2PServerV2) Init(ctx *nctx.NetCtx) error {
 	}
 
 	p.accounts = cache.New(cache.NoExpiration, cache.NoExpiration)
+	p.dhtValues = newDhtCache(time.Duration(cfg.Timeout)*time.Second,
+		func(ctx context.Context, key string) ([]byte, error) {
+			return p.kdht.GetValue(ctx, key)
+		})
+	p.sizeLimiter, err = p2p.NewSizeLimiter(cfg)
+	if err != nil {
+		return err
+	}
 
 	// dispatcher
 	p.dispatcher = p2p.NewDispatcher(ctx)
@@ -226,7 +239,8 @@ func setStaticNodes(ctx *nctx.NetCtx, p *P2PServerV2) {
 
 func (p *P2PServerV2) setKdhtValue() {
 	// store: account => address
-	account := GenAccountKey(p.account)
+	localAccount := p.localAccount()
+	account := GenAccountKey(localAccount)
 	address := p.getMultiAddr(p.host.ID(), p.host.Addrs())
 	err := p.kdht.PutValue(context.Background(), account, []byte(address))
 	if err != nil {
@@ -235,10 +249,17 @@ func (p *P2PServerV2) setKdhtValue() {
 
 	// store: peer.ID => account
 	id := GenPeerIDKey(p.id)
-	err = p.kdht.PutValue(context.Background(), id, []byte(p.account))
+	err = p.kdht.PutValue(context.Background(), id, []byte(localAccount))
 	if err != nil {
 		p.log.Error("dht put id=>account value error", "error", err)
 	}
+
+	// store: peer.ID => capabilities
+	capKey := GenPeerCapabilitiesKey(p.id)
+	err = p.kdht.PutValue(context.Background(), capKey, encodeCapabilities(p2p.LocalCapabilities()))
+	if err != nil {
+		p.log.Error("dht put id=>capabilities value error", "error", err)
+	}
 }
 
 // Start start the node
@@ -349,26 +370,48 @@ func (p *P2PServerV2) Context() *nctx.NetCtx {
 	return p.ctx
 }
 
+// PeerCount 返回当前已建立连接的邻居节点数
+func (p *P2PServerV2) PeerCount() int {
+	return len(p.host.Network().Peers())
+}
+
+// SetAccount 替换本节点宣告的账户，并重新写入dht中账户与节点的映射
+func (p *P2PServerV2) SetAccount(account string) {
+	p.accountMutex.Lock()
+	p.account = account
+	p.accountMutex.Unlock()
+
+	go p.setKdhtValue()
+}
+
+func (p *P2PServerV2) localAccount() string {
+	p.accountMutex.RLock()
+	defer p.accountMutex.RUnlock()
+	return p.account
+}
+
 func (p *P2PServerV2) PeerInfo() pb.PeerInfo {
 	peerInfo := pb.PeerInfo{
-		Id:      p.host.ID().Pretty(),
-		Address: p.getMultiAddr(p.host.ID(), p.host.Addrs()),
-		Account: p.account,
+		Id:           p.host.ID().Pretty(),
+		Address:      p.getMultiAddr(p.host.ID(), p.host.Addrs()),
+		Account:      p.localAccount(),
+		Capabilities: p2p.LocalCapabilities(),
 	}
 
 	peerStore := p.host.Peerstore()
 	for _, peerID := range p.kdht.RoutingTable().ListPeers() {
 		key := GenPeerIDKey(peerID)
-		account, err := p.kdht.GetValue(context.Background(), key)
+		account, err := p.dhtValues.lookup(p.ctx, key)
 		if err != nil {
-			p.log.Warn("get account error", "peerID", peerID)
+			p.log.Warn("get account error", "peerID", peerID, "error", err)
 		}
 
 		addrInfo := peerStore.PeerInfo(peerID)
 		remotePeerInfo := &pb.PeerInfo{
-			Id:      peerID.String(),
-			Address: p.getMultiAddr(addrInfo.ID, addrInfo.Addrs),
-			Account: string(account),
+			Id:           peerID.String(),
+			Address:      p.getMultiAddr(addrInfo.ID, addrInfo.Addrs),
+			Account:      string(account),
+			Capabilities: p.PeerCapabilities(peerID.String()),
 		}
 		peerInfo.Peer = append(peerInfo.Peer, remotePeerInfo)
 	}
@@ -376,6 +419,22 @@ func (p *P2PServerV2) PeerInfo() pb.PeerInfo {
 	return peerInfo
 }
 
+// PeerCapabilities return capabilities advertised by remote peer in dht
+func (p *P2PServerV2) PeerCapabilities(peerID string) []string {
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		p.log.Warn("decode peer id error", "peerID", peerID, "error", err)
+		return nil
+	}
+	value, err := p.dhtValues.lookup(p.ctx, GenPeerCapabilitiesKey(id))
+	if err != nil {
+		// 老版本节点没有宣告能力，按不支持任何新特性处理
+		p.log.Trace("get capabilities error", "peerID", peerID, "error", err)
+		return nil
+	}
+	return decodeCapabilities(value)
+}
+
 func (p *P2PServerV2) getMultiAddr(peerID peer.ID, addrs []multiaddr.Multiaddr) string {
 	peerInfo := &peer.AddrInfo{
 		ID:    peerID,
@@ -427,26 +486,5 @@ func (p *P2PServerV2) connectPeer(addrInfos []peer.AddrInfo) int {
 		return 0
 	}
 
-	retry := retry
-	success := 0
-	for retry > 0 {
-		for _, addrInfo := range addrInfos {
-			if err := p.host.Connect(p.ctx, addrInfo); err != nil {
-				p.log.Error("p2p: connection with peer node error", "error", err)
-				continue
-			}
-
-			success++
-			p.log.Info("p2p: connection established", "addrInfo", addrInfo)
-		}
-
-		if success > 0 {
-			break
-		}
-
-		retry--
-		time.Sleep(3 * time.Second)
-	}
-
-	return success
+	return newDialPolicy(p.config).connect(p.ctx, p.log, addrInfos, p.host.Connect)
 }
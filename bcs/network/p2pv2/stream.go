@@ -141,12 +141,29 @@ func (s *Stream) Recv() {
 			s.log.Trace("Stream Recv", "error", "io.EOF")
 			s.reset()
 			return
+		case io.ErrShortBuffer:
+			// 消息长度超过MaxMessageSize，读取数据之前就被拒绝
+			s.log.Warn("Stream Recv message too large", "peer", s.id.Pretty())
+			s.srv.sizeLimiter.RecordOversize(s.id.Pretty(), time.Now())
+			s.reset()
+			return
 		case nil:
 		default:
 			s.log.Trace("Stream Recv error to reset", "error", err)
 			s.reset()
 			return
 		}
+		// 超过类型限制的消息直接丢弃，被拒绝的节点断开连接
+		err = s.srv.sizeLimiter.Check(s.id.Pretty(), msg)
+		if err != nil {
+			s.log.Warn("Stream Recv reject message by size limit", "log_id", msg.GetHeader().GetLogid(),
+				"type", msg.GetHeader().GetType(), "peer", s.id.Pretty(), "error", err)
+			if err == p2p.ErrPeerBanned {
+				s.reset()
+				return
+			}
+			continue
+		}
 		err = s.srv.HandleMessage(s, msg)
 		if err != nil {
 			s.reset()
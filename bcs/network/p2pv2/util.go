@@ -2,6 +2,7 @@ package p2pv2
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 )
@@ -22,3 +23,19 @@ func GenAccountKey(account string) string {
 func GenPeerIDKey(id peer.ID) string {
 	return fmt.Sprintf("/%s/id/%s", namespace, id)
 }
+
+func GenPeerCapabilitiesKey(id peer.ID) string {
+	return fmt.Sprintf("/%s/capabilities/%s", namespace, id)
+}
+
+// encodeCapabilities 能力列表在dht中以逗号分隔存储
+func encodeCapabilities(caps []string) []byte {
+	return []byte(strings.Join(caps, ","))
+}
+
+func decodeCapabilities(value []byte) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(string(value), ",")
+}
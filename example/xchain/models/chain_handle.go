@@ -6,6 +6,7 @@ import (
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	sctx "github.com/xuperchain/xupercore/example/xchain/common/context"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract"
 	ecom "github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/reader"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
@@ -48,6 +49,15 @@ func (t *ChainHandle) PreExec(req []*protos.InvokeRequest,
 	return t.chain.PreExec(t.genXctx(), req, initiator, authRequires)
 }
 
+func (t *ChainHandle) TracePreExec(req []*protos.InvokeRequest, initiator string,
+	authRequires []string) (*protos.InvokeResponse, []*contract.InvokeTrace, error) {
+	return t.chain.TracePreExec(t.genXctx(), req, initiator, authRequires)
+}
+
+func (t *ChainHandle) ViewCall(req *protos.InvokeRequest, initiator string) (*protos.ContractResponse, error) {
+	return t.chain.ViewCall(t.genXctx(), req, initiator)
+}
+
 func (t *ChainHandle) QueryTx(txId []byte) (*xpb.TxInfo, error) {
 	return reader.NewLedgerReader(t.chain.Context(), t.genXctx()).QueryTx(txId)
 }
@@ -66,6 +76,14 @@ func (t *ChainHandle) QueryChainStatus(needBranch bool) (*xpb.ChainStatus, error
 	return reader.NewChainReader(t.chain.Context(), t.genXctx()).GetChainStatus()
 }
 
+func (t *ChainHandle) RecentRoundTimings() []ecom.RoundTiming {
+	return t.chain.RecentRoundTimings()
+}
+
+func (t *ChainHandle) RefreshContractCache(contractName string, warmup bool) error {
+	return t.chain.RefreshContractCache(contractName, warmup)
+}
+
 func (t *ChainHandle) genXctx() xctx.XContext {
 	return &xctx.BaseCtx{
 		XLog:  t.reqCtx.GetLog(),
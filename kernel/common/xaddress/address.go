@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sync"
 
 	cryptoClinet "github.com/xuperchain/xupercore/lib/crypto/client/base"
 )
@@ -17,6 +18,23 @@ type Address struct {
 	PublicKeyStr  string
 }
 
+// rotateMutex 保护运行中被替换的地址，出块、共识签名等并发读取方通过Snapshot获取一致的副本
+var rotateMutex sync.RWMutex
+
+// Snapshot 返回地址的一致副本，不会读到替换过程中新旧混合的身份
+func (a *Address) Snapshot() Address {
+	rotateMutex.RLock()
+	defer rotateMutex.RUnlock()
+	return *a
+}
+
+// Rotate 用newAddr替换地址内容，与Snapshot互斥，所有共享该地址对象的模块随之生效
+func (a *Address) Rotate(newAddr *Address) {
+	rotateMutex.Lock()
+	defer rotateMutex.Unlock()
+	*a = *newAddr
+}
+
 func LoadAddress(keyDir string) (string, error) {
 	addr, err := ioutil.ReadFile(filepath.Join(keyDir, "address"))
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/xuperchain/crypto/core/hash"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
 	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 )
@@ -28,13 +29,14 @@ func (c *CBFTCrypto) SignProposalMsg(msg *pb.ProposalMsg) (*pb.ProposalMsg, erro
 		return nil, err
 	}
 	msg.MsgDigest = msgDigest
-	sign, err := c.CryptoClient.SignECDSA(c.Address.PrivateKey, msgDigest)
+	addr := (*xaddress.Address)(c.Address).Snapshot()
+	sign, err := c.CryptoClient.SignECDSA(addr.PrivateKey, msgDigest)
 	if err != nil {
 		return nil, err
 	}
 	msg.Sign = &pb.QuorumCertSign{
-		Address:   c.Address.Address,
-		PublicKey: c.Address.PublicKeyStr,
+		Address:   addr.Address,
+		PublicKey: addr.PublicKeyStr,
 		Sign:      sign,
 	}
 	return msg, nil
@@ -70,13 +72,14 @@ func encodeProposalMsg(msg *pb.ProposalMsg) ([]byte, error) {
 
 // SignVoteMsg make ChainedBftVoteMessage sign
 func (c *CBFTCrypto) SignVoteMsg(msg []byte) (*pb.QuorumCertSign, error) {
-	sign, err := c.CryptoClient.SignECDSA(c.Address.PrivateKey, msg)
+	addr := (*xaddress.Address)(c.Address).Snapshot()
+	sign, err := c.CryptoClient.SignECDSA(addr.PrivateKey, msg)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.QuorumCertSign{
-		Address:   c.Address.Address,
-		PublicKey: c.Address.PublicKeyStr,
+		Address:   addr.Address,
+		PublicKey: addr.PublicKeyStr,
 		Sign:      sign,
 	}, nil
 }
@@ -1,9 +1,13 @@
 package chained_bft
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
+	"sort"
 
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
 	"github.com/xuperchain/xupercore/lib/logs"
 )
@@ -44,6 +48,9 @@ type DefaultSaftyRules struct {
 	preferredRound int64
 	Crypto         *cCrypto.CBFTCrypto
 	QcTree         *storage.QCPendingTree
+	// JustifySampleSize 校验justify时抽样验证的签名个数，0表示验证全部签名。
+	// 抽样个数不少于法定票数，只有验证通过的签名计入票数；抽样由proposalId确定，各节点验证相同的签名
+	JustifySampleSize int
 
 	Log logs.Logger
 }
@@ -143,9 +150,13 @@ func (s *DefaultSaftyRules) CheckProposal(proposal, parent storage.QuorumCertInt
 		}
 	}
 
-	// 检查justify的所有vote签名
 	justifySigns := parent.GetSignsInfo()
 	s.Log.Debug("DefaultSaftyRules::CheckProposal", "parent", parent, "justifyValidators", justifyValidators)
+	if s.JustifySampleSize > 0 {
+		return s.checkSampledSigns(justifySigns, parent.GetProposalId(), justifyValidators)
+	}
+
+	// 检查justify的所有vote签名
 	validCnt := 0
 	for _, v := range justifySigns {
 		if !isInSlice(v.GetAddress(), justifyValidators) {
@@ -163,6 +174,64 @@ func (s *DefaultSaftyRules) CheckProposal(proposal, parent storage.QuorumCertInt
 	return nil
 }
 
+// checkSampledSigns 抽样校验justify的vote签名，同一验证人的签名只计一次，只有验证通过的签名计入票数
+func (s *DefaultSaftyRules) checkSampledSigns(justifySigns []*chainedBftPb.QuorumCertSign, proposalId []byte,
+	justifyValidators []string) error {
+	var validSigns []*chainedBftPb.QuorumCertSign
+	seen := make(map[string]bool)
+	for _, v := range justifySigns {
+		if !isInSlice(v.GetAddress(), justifyValidators) || seen[v.GetAddress()] {
+			continue
+		}
+		seen[v.GetAddress()] = true
+		validSigns = append(validSigns, v)
+	}
+	if !s.CalVotesThreshold(len(validSigns), len(justifyValidators)) {
+		return NoEnoughVotes
+	}
+	verified := s.sampleSigns(validSigns, proposalId, len(justifyValidators))
+	for _, v := range verified {
+		// 签名和公钥是否匹配
+		if ok, _ := s.Crypto.VerifyVoteMsgSign(v, proposalId); !ok {
+			return InvalidVoteSign
+		}
+	}
+	if !s.CalVotesThreshold(len(verified), len(justifyValidators)) {
+		return NoEnoughVotes
+	}
+	return nil
+}
+
+// sampleSigns 返回需要验证的签名，未配置抽样或签名数不超过抽样数时返回全部签名。
+// 抽样个数不少于达到法定票数所需的签名数，按sha256(proposalId+address)排序选取，
+// 保证所有节点对同一proposal验证相同的签名子集，得出一致的结果
+func (s *DefaultSaftyRules) sampleSigns(signs []*chainedBftPb.QuorumCertSign, proposalId []byte, sum int) []*chainedBftPb.QuorumCertSign {
+	size := s.JustifySampleSize
+	if size <= 0 || len(signs) <= size {
+		return signs
+	}
+	for size < len(signs) && !s.CalVotesThreshold(size, sum) {
+		size++
+	}
+	type keyedSign struct {
+		key  []byte
+		sign *chainedBftPb.QuorumCertSign
+	}
+	keyed := make([]keyedSign, 0, len(signs))
+	for _, v := range signs {
+		key := sha256.Sum256(append(append([]byte{}, proposalId...), v.GetAddress()...))
+		keyed = append(keyed, keyedSign{key: key[:], sign: v})
+	}
+	sort.Slice(keyed, func(i, j int) bool {
+		return bytes.Compare(keyed[i].key, keyed[j].key) < 0
+	})
+	sampled := make([]*chainedBftPb.QuorumCertSign, 0, size)
+	for _, v := range keyed[:size] {
+		sampled = append(sampled, v.sign)
+	}
+	return sampled
+}
+
 // CheckPacemaker
 // 注意： 由于本smr支持不同节点产生同一round， 因此下述round比较和leader比较与原文(验证Proposal的Round是否和pacemaker的Round相等)并不同。
 // 仅需proposal round不超过范围即可
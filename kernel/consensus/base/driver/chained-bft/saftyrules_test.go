@@ -6,6 +6,7 @@ import (
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/mock"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
 )
 
 func TestCalVotesThreshold(t *testing.T) {
@@ -76,3 +77,96 @@ func TestCheckProposal(t *testing.T) {
 	s.VoteProposal([]byte{2}, 2, generic)
 	s.CheckVote(generic, "123", []string{"gNhga8vLc4JcmoHB2yeef2adBhntkc5d1"})
 }
+
+func TestCheckProposalJustify(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	s := &DefaultSaftyRules{
+		QcTree: mock.MockInitQcTree(),
+		Log:    th.Log,
+	}
+	var signs []*chainedBftPb.QuorumCertSign
+	for _, node := range []string{"nodeA", "nodeB", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		c := cCrypto.NewCBFTCrypto(&a, cc)
+		if s.Crypto == nil {
+			s.Crypto = c
+		}
+		sign, err := c.SignVoteMsg([]byte{1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs = append(signs, sign)
+	}
+	newJustify := func(signs []*chainedBftPb.QuorumCertSign) storage.QuorumCertInterface {
+		return storage.NewQuorumCert(&storage.VoteInfo{
+			ProposalId:   []byte{1},
+			ProposalView: 1,
+			ParentId:     []byte{0},
+			ParentView:   0,
+		}, nil, signs)
+	}
+	justify := newJustify(signs)
+	if err := s.QcTree.UpdateQcStatus(mock.MockCreateNode(justify, signs)); err != nil {
+		t.Fatal(err)
+	}
+	proposal := mock.MockCreateQC([]byte{2}, 2, []byte{1}, 1)
+	validators := []string{NodeA, NodeB, NodeC}
+	if err := s.CheckProposal(proposal, justify, validators); err != nil {
+		t.Fatalf("valid justify rejected: %v", err)
+	}
+
+	// 冒用nodeC的身份，签名实际来自nodeA
+	forged := append([]*chainedBftPb.QuorumCertSign{}, signs[:2]...)
+	forged = append(forged, &chainedBftPb.QuorumCertSign{
+		Address:   signs[2].Address,
+		PublicKey: signs[2].PublicKey,
+		Sign:      signs[0].Sign,
+	})
+	if err := s.CheckProposal(proposal, newJustify(forged), validators); err != InvalidVoteSign {
+		t.Fatalf("forged justify should be rejected, err: %v", err)
+	}
+
+	// 抽样校验时，全部签名伪造的justify仍会被拒绝
+	s.JustifySampleSize = 1
+	if err := s.CheckProposal(proposal, justify, validators); err != nil {
+		t.Fatalf("valid justify rejected when sampling: %v", err)
+	}
+	allForged := []*chainedBftPb.QuorumCertSign{forged[2], {
+		Address:   signs[1].Address,
+		PublicKey: signs[1].PublicKey,
+		Sign:      signs[0].Sign,
+	}}
+	if err := s.CheckProposal(proposal, newJustify(allForged), validators); err != InvalidVoteSign {
+		t.Fatalf("forged justify should be rejected when sampling, err: %v", err)
+	}
+
+	// 抽样个数提升到法定票数，且对同一proposal各节点抽中相同的签名
+	sampled := s.sampleSigns(forged, []byte{1}, len(validators))
+	if len(sampled) != 2 {
+		t.Fatalf("sample size should be raised to quorum, got %d", len(sampled))
+	}
+	other := &DefaultSaftyRules{JustifySampleSize: 1}
+	for i, v := range other.sampleSigns(forged, []byte{1}, len(validators)) {
+		if v != sampled[i] {
+			t.Fatalf("sample should be deterministic")
+		}
+	}
+	expect := error(nil)
+	for _, v := range sampled {
+		if v == forged[2] {
+			expect = InvalidVoteSign
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := s.CheckProposal(proposal, newJustify(forged), validators); err != expect {
+			t.Fatalf("sampled check should be deterministic, expect %v, got %v", expect, err)
+		}
+	}
+
+	// 抽样校验时重复的签名只计一次，不能凑够票数
+	padded := []*chainedBftPb.QuorumCertSign{signs[0], signs[0], signs[0]}
+	if err := s.CheckProposal(proposal, newJustify(padded), validators); err != NoEnoughVotes {
+		t.Fatalf("duplicated signs should not reach quorum, err: %v", err)
+	}
+}
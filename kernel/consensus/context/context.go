@@ -36,4 +36,6 @@ type ConsensusCtx struct {
 	Contract contract.Manager
 	Ledger   LedgerRely
 	Network  network.Network
+	// JustifySampleSize 校验区块justify时抽样验证的签名个数，不少于法定票数，0表示全部验证
+	JustifySampleSize int
 }
@@ -384,6 +384,14 @@ func (m *FakeManager) GetKernRegistry() contract.KernRegistry {
 	return m.R
 }
 
+func (m *FakeManager) RefreshContractCache(contractName string, warmup bool) error {
+	return nil
+}
+
+func (m *FakeManager) TraceEnabled() bool {
+	return false
+}
+
 type FakeRegistry struct {
 	M map[string]contract.KernMethod
 }
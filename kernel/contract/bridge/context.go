@@ -53,6 +53,37 @@ type Context struct {
 
 	// Write by contract
 	Output *pb.Response
+
+	// SyscallStats 本次调用的系统调用统计，由虚拟机在每次系统调用时记录
+	SyscallStats *SyscallStats
+
+	// Tracer 本次调用的执行跟踪，未开启跟踪时为nil
+	Tracer *contract.Tracer
+
+	// Capabilities 合约部署时声明的可调用的系统调用，为空时不限制
+	Capabilities []string
+
+	// ReadOnly 只读调用，合约不能修改状态，用于不生成交易的查询
+	ReadOnly bool
+}
+
+// baseSyscalls 合约读取参数和返回结果必需的系统调用，不受Capabilities限制
+var baseSyscalls = map[string]bool{
+	"GetCallArgs": true,
+	"SetOutput":   true,
+}
+
+// AllowSyscall 判断合约能否调用指定的系统调用，结果只取决于合约部署时的声明，各节点一致
+func (c *Context) AllowSyscall(method string) bool {
+	if len(c.Capabilities) == 0 || baseSyscalls[method] {
+		return true
+	}
+	for _, capability := range c.Capabilities {
+		if capability == method {
+			return true
+		}
+	}
+	return false
 }
 
 // DiskUsed returns the bytes written to xmodel
@@ -116,6 +147,7 @@ func (n *ContextManager) MakeContext() *Context {
 	n.ctxid++
 	ctx := new(Context)
 	ctx.ID = n.ctxid
+	ctx.SyscallStats = NewSyscallStats()
 	n.ctxs[ctx.ID] = ctx
 	return ctx
 }
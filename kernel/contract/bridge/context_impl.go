@@ -3,14 +3,20 @@ package bridge
 import (
 	"errors"
 	"fmt"
+	"runtime/debug"
 
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/metrics"
 )
 
 const (
 	initMethod = "initialize"
 )
 
+// ErrContractPanic 合约执行过程中发生了运行时panic
+var ErrContractPanic = errors.New("contract panic")
+
 // ContractError indicates the error of the contract running result
 type ContractError struct {
 	Status  int
@@ -28,6 +34,8 @@ type vmContextImpl struct {
 	ctx      *Context
 	instance Instance
 	release  func()
+	// bcname 所属链名，用于资源统计
+	bcname string
 }
 
 func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract.Response, error) {
@@ -37,7 +45,13 @@ func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract
 
 	v.ctx.Method = method
 	v.ctx.Args = args
-	err := v.instance.Exec()
+	err := v.execInstance(method)
+	v.reportResourceUsed(method)
+	// 统计快照需要拷贝每个系统调用的数据，只在输出debug日志时构造
+	if logs.Enabled(v.ctx.Logger, logs.LvlDebug) {
+		v.ctx.Logger.Debug("contract syscall stats", "contract", v.ctx.ContractName,
+			"method", method, "stats", v.ctx.SyscallStats.Snapshot())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +74,32 @@ func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract
 	}, nil
 }
 
+// execInstance 执行合约，合约代码或resolver中的运行时panic转换为交易执行失败，避免节点进程崩溃
+func (v *vmContextImpl) execInstance(method string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if v.ctx.Logger != nil {
+				v.ctx.Logger.Error("contract panic", "contract", v.ctx.ContractName, "method", method,
+					"panic", r, "stack", string(debug.Stack()))
+			}
+			err = fmt.Errorf("%w: %v", ErrContractPanic, r)
+		}
+	}()
+	return v.instance.Exec()
+}
+
+// reportResourceUsed 将本次调用消耗的cpu gas和线性内存峰值计入合约统计，
+// 只读取虚拟机已有的计量结果，不影响执行结果；native等不计量的虚拟机返回0，不计入统计
+func (v *vmContextImpl) reportResourceUsed(method string) {
+	used := v.instance.ResourceUsed()
+	if used.Cpu == 0 && used.Memory == 0 {
+		return
+	}
+	labels := []string{v.bcname, v.ctx.Module, v.ctx.ContractName, method}
+	metrics.ContractInvokeGasHistogram.WithLabelValues(labels...).Observe(float64(used.Cpu))
+	metrics.ContractInvokeMemoryHistogram.WithLabelValues(labels...).Observe(float64(used.Memory))
+}
+
 func (v *vmContextImpl) ResourceUsed() contract.Limits {
 	return v.ctx.ResourceUsed()
 }
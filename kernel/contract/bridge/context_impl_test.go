@@ -0,0 +1,117 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/lib/metrics"
+)
+
+type panicInstance struct {
+	exec func() error
+}
+
+func (p *panicInstance) Exec() error                   { return p.exec() }
+func (p *panicInstance) ResourceUsed() contract.Limits { return contract.Limits{} }
+func (p *panicInstance) Release()                      {}
+func (p *panicInstance) Abort(msg string)              {}
+
+func TestInvokeRecoverPanic(t *testing.T) {
+	newContext := func(exec func() error) *vmContextImpl {
+		instance := &panicInstance{exec: exec}
+		ctx := &Context{
+			Module:         string(TypeKernel),
+			ContractName:   "panic",
+			Instance:       instance,
+			SyscallStats:   NewSyscallStats(),
+			ResourceLimits: contract.MaxLimits,
+		}
+		return &vmContextImpl{ctx: ctx, instance: instance, release: func() {}}
+	}
+
+	cases := map[string]func() error{
+		"trap": func() error {
+			panic("unreachable executed")
+		},
+		"runtime": func() error {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		},
+	}
+	for name, exec := range cases {
+		_, err := newContext(exec).Invoke("run", nil)
+		if !errors.Is(err, ErrContractPanic) {
+			t.Fatalf("%s: expect contract panic error, got %v", name, err)
+		}
+	}
+
+	// 合约panic后后续调用不受影响
+	ctx := newContext(func() error { return nil })
+	ctx.ctx.Output = &pb.Response{Status: 200}
+	resp, err := ctx.Invoke("run", nil)
+	if err != nil || resp.Status != 200 {
+		t.Fatalf("expect normal invoke after panic, got %v %v", resp, err)
+	}
+}
+
+type usageInstance struct {
+	panicInstance
+	used contract.Limits
+}
+
+func (u *usageInstance) ResourceUsed() contract.Limits { return u.used }
+
+func TestInvokeReportResourceUsed(t *testing.T) {
+	instance := &usageInstance{
+		panicInstance: panicInstance{exec: func() error { return nil }},
+		used:          contract.Limits{Cpu: 12345, Memory: 1 << 20},
+	}
+	ctx := &Context{
+		Module:         string(TypeKernel),
+		ContractName:   "usage",
+		Instance:       instance,
+		SyscallStats:   NewSyscallStats(),
+		ResourceLimits: contract.MaxLimits,
+		Output:         &pb.Response{Status: 200},
+	}
+	vctx := &vmContextImpl{ctx: ctx, instance: instance, release: func() {}, bcname: "xuper"}
+
+	// 指标是全局的，只比较本次调用前后的变化
+	sample := func(h *prom.HistogramVec) (uint64, float64) {
+		var m dto.Metric
+		h.WithLabelValues("xuper", string(TypeKernel), "usage", "run").(prom.Metric).Write(&m)
+		return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+	}
+	gasCount, gasSum := sample(metrics.ContractInvokeGasHistogram)
+	memCount, memSum := sample(metrics.ContractInvokeMemoryHistogram)
+
+	resp, err := vctx.Invoke("run", nil)
+	if err != nil || resp.Status != 200 {
+		t.Fatalf("expect normal invoke, got %v %v", resp, err)
+	}
+
+	if count, sum := sample(metrics.ContractInvokeGasHistogram); count-gasCount != 1 || sum-gasSum != 12345 {
+		t.Fatalf("unexpected gas sample: %d %v", count-gasCount, sum-gasSum)
+	}
+	if count, sum := sample(metrics.ContractInvokeMemoryHistogram); count-memCount != 1 || sum-memSum != 1<<20 {
+		t.Fatalf("unexpected memory sample: %d %v", count-memCount, sum-memSum)
+	}
+
+	// 不计量的虚拟机不计入统计
+	instance.used = contract.Limits{}
+	if _, err := vctx.Invoke("run", nil); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := sample(metrics.ContractInvokeGasHistogram); count-gasCount != 1 {
+		t.Fatalf("unmetered invoke should not be observed, got %d samples", count-gasCount)
+	}
+	if count, _ := sample(metrics.ContractInvokeMemoryHistogram); count-memCount != 1 {
+		t.Fatalf("unmetered invoke should not be observed, got %d samples", count-memCount)
+	}
+}
@@ -51,6 +51,9 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 	if err != nil {
 		return nil, contract.Limits{}, err
 	}
+	if err := c.checkCodeSize(&desc, code); err != nil {
+		return nil, contract.Limits{}, err
+	}
 	desc.Digest = hash.DoubleSha256(code)
 	descbuf, _ = proto.Marshal(&desc)
 
@@ -148,6 +151,9 @@ func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Res
 	if code == nil {
 		return nil, contract.Limits{}, errors.New("missing contract code")
 	}
+	if err := c.checkCodeSize(desc, code); err != nil {
+		return nil, contract.Limits{}, err
+	}
 	desc.Digest = hash.DoubleSha256(code)
 	descbuf, _ := proto.Marshal(desc)
 
@@ -183,6 +189,38 @@ func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Res
 		}, nil
 }
 
+// RefreshContractCache 清除合约的本地编译缓存，warmup为true时立即重新编译加载
+// 用于缓存的合约产物损坏或者被人工替换后，无需重启节点即可恢复
+func (c *contractManager) RefreshContractCache(contractName string, warmup bool) error {
+	desc, err := c.codeProvider.GetContractCodeDesc(contractName)
+	if err != nil {
+		return fmt.Errorf("contract %s not exists", contractName)
+	}
+	contractType, err := getContractType(desc)
+	if err != nil {
+		return err
+	}
+	creator := c.xbridge.getCreator(contractType)
+	if creator == nil {
+		return fmt.Errorf("contract type %s not found", contractType)
+	}
+	creator.RemoveCache(contractName)
+	if !warmup {
+		return nil
+	}
+
+	instance, err := creator.CreateInstance(&Context{
+		ContractName:   contractName,
+		ResourceLimits: contract.MaxLimits,
+	}, newDescProvider(c.codeProvider, desc))
+	if err != nil {
+		creator.RemoveCache(contractName)
+		return fmt.Errorf("warmup contract %s error:%s", contractName, err)
+	}
+	instance.Release()
+	return nil
+}
+
 func modelCacheDiskUsed(store contract.KContext) int64 {
 	size := int64(0)
 	wset := store.RWSet().WSet
@@ -205,6 +243,19 @@ func contractAbiKey(contractName string) []byte {
 	return []byte(contractName + "." + "abi")
 }
 
+// checkCodeSize 按wasm虚拟机配置检查合约代码大小，与编译时的检查保持一致
+func (c *contractManager) checkCodeSize(desc *protos.WasmCodeDesc, code []byte) error {
+	contractType, err := getContractType(desc)
+	if err != nil || contractType != TypeWasm {
+		return nil
+	}
+	vmconfig, ok := c.xbridge.vmconfigs[TypeWasm].(*contract.WasmConfig)
+	if !ok || vmconfig == nil {
+		return nil
+	}
+	return vmconfig.XVM.CheckCodeSize(len(code))
+}
+
 func getContractType(desc *protos.WasmCodeDesc) (ContractType, error) {
 	switch desc.ContractType {
 	case "", "wasm":
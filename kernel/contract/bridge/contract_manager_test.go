@@ -0,0 +1,84 @@
+package bridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// deployContext 只提供部署参数，记录写入状态的次数
+type deployContext struct {
+	contract.KContext
+	args map[string][]byte
+	puts int
+}
+
+func (d *deployContext) Args() map[string][]byte { return d.args }
+
+func (d *deployContext) Put(bucket string, key, value []byte) error {
+	d.puts++
+	return nil
+}
+
+// descCodeProvider 按desc返回已部署的合约描述，desc为空时合约不存在
+type descCodeProvider struct {
+	ContractCodeProvider
+	desc *protos.WasmCodeDesc
+}
+
+func (p *descCodeProvider) GetContractCodeDesc(name string) (*protos.WasmCodeDesc, error) {
+	if p.desc == nil {
+		return nil, errors.New("not found")
+	}
+	return p.desc, nil
+}
+
+func TestCheckCodeSize(t *testing.T) {
+	code := make([]byte, 1025)
+	desc := &protos.WasmCodeDesc{Runtime: "c"}
+	descbuf, _ := proto.Marshal(desc)
+	args := map[string][]byte{
+		"contract_name": []byte("big"),
+		"contract_code": code,
+		"contract_desc": descbuf,
+		"init_args":     []byte("{}"),
+	}
+	provider := &descCodeProvider{}
+	c := &contractManager{
+		xbridge: &XBridge{
+			vmconfigs: map[ContractType]VMConfig{
+				TypeWasm: &contract.WasmConfig{XVM: contract.XVMConfig{MaxCodeSize: 1024}},
+			},
+			config: contract.ContractConfig{EnableUpgrade: true},
+		},
+		codeProvider: provider,
+	}
+
+	// 部署时超限的代码在写入状态之前被拒绝
+	kctx := &deployContext{args: args}
+	_, _, err := c.DeployContract(kctx)
+	if err == nil || !strings.Contains(err.Error(), "contract too large") || kctx.puts != 0 {
+		t.Fatalf("expect contract too large before any write, got %v, puts %d", err, kctx.puts)
+	}
+
+	// 升级时同样检查
+	provider.desc = desc
+	kctx = &deployContext{args: args}
+	_, _, err = c.UpgradeContract(kctx)
+	if err == nil || !strings.Contains(err.Error(), "contract too large") || kctx.puts != 0 {
+		t.Fatalf("expect contract too large on upgrade, got %v, puts %d", err, kctx.puts)
+	}
+
+	// 非wasm合约不受xvm的大小限制
+	if err := c.checkCodeSize(&protos.WasmCodeDesc{ContractType: string(TypeNative)}, code); err != nil {
+		t.Fatalf("native contract should not be limited, got %v", err)
+	}
+	if err := c.checkCodeSize(desc, code[:1024]); err != nil {
+		t.Fatalf("code within limit should pass, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package bridge
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// ErrReadOnlyCall 只读调用中合约尝试修改状态
+var ErrReadOnlyCall = errors.New("state write not allowed in read-only call")
+
+// writeSyscalls 修改链上状态的系统调用，只读调用中不允许执行
+var writeSyscalls = map[string]bool{
+	"PutObject":    true,
+	"DeleteObject": true,
+	"Transfer":     true,
+	"EmitEvent":    true,
+}
+
+// CheckWritable 只读调用中执行修改状态的系统调用时返回ErrReadOnlyCall
+func (c *Context) CheckWritable(method string) error {
+	if c.ReadOnly && writeSyscalls[method] {
+		return ErrReadOnlyCall
+	}
+	return nil
+}
+
+// readOnlyState 只读调用使用的沙盒，写操作直接返回错误，保证只读调用不会产生写集，
+// 同时覆盖不经过系统调用直接访问沙盒的kernel合约
+type readOnlyState struct {
+	contract.StateSandbox
+}
+
+func newReadOnlyState(state contract.StateSandbox) contract.StateSandbox {
+	if _, ok := state.(*readOnlyState); ok {
+		return state
+	}
+	return &readOnlyState{StateSandbox: state}
+}
+
+func (s *readOnlyState) Put(bucket string, key, value []byte) error {
+	return ErrReadOnlyCall
+}
+
+func (s *readOnlyState) Del(bucket string, key []byte) error {
+	return ErrReadOnlyCall
+}
+
+func (s *readOnlyState) Transfer(from string, to string, amount *big.Int) error {
+	return ErrReadOnlyCall
+}
+
+// AddEvent 只读调用的结果不会上链，kernel合约产生的事件直接丢弃
+func (s *readOnlyState) AddEvent(events ...*protos.ContractEvent) {}
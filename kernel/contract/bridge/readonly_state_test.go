@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestReadOnlyState(t *testing.T) {
+	state := sandbox.NewXModelCache(&contract.SandboxConfig{
+		XMReader: sandbox.NewMemXModel(),
+	})
+	view := newReadOnlyState(state)
+	if newReadOnlyState(view) != view {
+		t.Fatal("read-only state should not be wrapped twice")
+	}
+
+	if err := view.Put("counter", []byte("key"), []byte("value")); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on Put, got %v", err)
+	}
+	if err := view.Del("counter", []byte("key")); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on Del, got %v", err)
+	}
+	if err := view.Transfer("counter", "bob", big.NewInt(1)); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on Transfer, got %v", err)
+	}
+	view.AddEvent(&protos.ContractEvent{Contract: "counter", Name: "inc"})
+	if _, err := view.Get("counter", []byte("key")); err != nil && err != sandbox.ErrNotFound {
+		t.Fatalf("read in read-only call failed: %v", err)
+	}
+
+	if err := view.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if wset := state.RWSet().WSet; len(wset) != 0 {
+		t.Fatalf("read-only call should not write, got %d writes", len(wset))
+	}
+}
+
+func TestSyscallReadOnly(t *testing.T) {
+	ctxmgr := NewContextManager()
+	service := NewSyscallService(ctxmgr, nil)
+	ctx := ctxmgr.MakeContext()
+	defer ctxmgr.DestroyContext(ctx)
+	ctx.ContractName = "counter"
+	ctx.ReadOnly = true
+
+	header := &pb.SyscallHeader{Ctxid: ctx.ID}
+	if _, err := service.PutObject(context.TODO(), &pb.PutRequest{Header: header, Key: []byte("k"), Value: []byte("v")}); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on PutObject, got %v", err)
+	}
+	if _, err := service.DeleteObject(context.TODO(), &pb.DeleteRequest{Header: header, Key: []byte("k")}); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on DeleteObject, got %v", err)
+	}
+	if _, err := service.Transfer(context.TODO(), &pb.TransferRequest{Header: header, To: "bob", Amount: "1"}); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on Transfer, got %v", err)
+	}
+	if _, err := service.EmitEvent(context.TODO(), &pb.EmitEventRequest{Header: header, Name: "inc"}); err != ErrReadOnlyCall {
+		t.Fatalf("expect ErrReadOnlyCall on EmitEvent, got %v", err)
+	}
+	if len(ctx.Events) != 0 {
+		t.Fatal("read-only call should not emit events")
+	}
+}
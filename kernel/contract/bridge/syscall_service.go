@@ -113,6 +113,9 @@ func (c *SyscallService) Transfer(ctx context.Context, in *pb.TransferRequest) (
 	if !ok {
 		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
 	}
+	if err := nctx.CheckWritable("Transfer"); err != nil {
+		return nil, err
+	}
 	amount, ok := new(big.Int).SetString(in.GetAmount(), 10)
 	if !ok {
 		return nil, errors.New("parse amount error")
@@ -172,6 +175,9 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		Caller:         nctx.ContractName,
 		ResourceLimits: *limits,
 		ContractSet:    nctx.ContractSet,
+		ReadOnly:       nctx.ReadOnly,
+		// 被调用合约的系统调用记录到发起调用的跟踪中
+		Tracer: nctx.Tracer,
 	}
 	vctx, err := c.bridge.NewContext(cfg)
 	if err != nil {
@@ -239,6 +245,9 @@ func (c *SyscallService) PutObject(ctx context.Context, in *pb.PutRequest) (*pb.
 	if !ok {
 		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
 	}
+	if err := nctx.CheckWritable("PutObject"); err != nil {
+		return nil, err
+	}
 	if in.Value == nil {
 		return nil, errors.New("put nil value")
 	}
@@ -273,6 +282,9 @@ func (c *SyscallService) DeleteObject(ctx context.Context, in *pb.DeleteRequest)
 	if !ok {
 		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
 	}
+	if err := nctx.CheckWritable("DeleteObject"); err != nil {
+		return nil, err
+	}
 
 	err := nctx.State.Del(nctx.ContractName, in.Key)
 	if err != nil {
@@ -379,6 +391,9 @@ func (c *SyscallService) EmitEvent(ctx context.Context, in *pb.EmitEventRequest)
 	if !ok {
 		return nil, fmt.Errorf("bad ctx id:%d", in.GetHeader().GetCtxid())
 	}
+	if err := nctx.CheckWritable("EmitEvent"); err != nil {
+		return nil, err
+	}
 	event := &protos.ContractEvent{
 		Contract: nctx.ContractName,
 		Name:     in.GetName(),
@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"sync"
+)
+
+// SyscallStat 单个系统调用方法的累计统计
+type SyscallStat struct {
+	Count         int64
+	RequestBytes  int64
+	ResponseBytes int64
+}
+
+// SyscallStats 记录一次合约调用中各系统调用的次数和数据量，
+// 用于排查问题和识别频繁读取状态的合约，不参与gas计算
+type SyscallStats struct {
+	mutex sync.Mutex
+	stats map[string]*SyscallStat
+}
+
+// NewSyscallStats instances a new SyscallStats
+func NewSyscallStats() *SyscallStats {
+	return &SyscallStats{
+		stats: make(map[string]*SyscallStat),
+	}
+}
+
+// Record 累加一次系统调用，s为nil时忽略
+func (s *SyscallStats) Record(method string, requestBytes, responseBytes int) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stat, ok := s.stats[method]
+	if !ok {
+		stat = new(SyscallStat)
+		s.stats[method] = stat
+	}
+	stat.Count++
+	stat.RequestBytes += int64(requestBytes)
+	stat.ResponseBytes += int64(responseBytes)
+}
+
+// Snapshot 返回按方法名统计的副本
+func (s *SyscallStats) Snapshot() map[string]SyscallStat {
+	result := make(map[string]SyscallStat)
+	if s == nil {
+		return result
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for method, stat := range s.stats {
+		result[method] = *stat
+	}
+	return result
+}
@@ -21,6 +21,7 @@ type XBridge struct {
 	xmodel         ledger.XMReader
 	config         contract.ContractConfig
 	core           contract.ChainCore
+	bcname         string
 
 	debugLogger logs.Logger
 
@@ -28,6 +29,7 @@ type XBridge struct {
 }
 
 type XBridgeConfig struct {
+	BCName    string
 	Basedir   string
 	VMConfigs map[ContractType]VMConfig
 	XModel    ledger.XMReader
@@ -47,6 +49,7 @@ func New(cfg *XBridgeConfig) (*XBridge, error) {
 		xmodel:      cfg.XModel,
 		core:        cfg.Core,
 		config:      cfg.Config,
+		bcname:      cfg.BCName,
 		debugLogger: cfg.LogDriver,
 	}
 	xbridge.contractManager = &contractManager{
@@ -93,6 +96,11 @@ func (v *XBridge) getCreator(tp ContractType) InstanceCreator {
 	return v.creators[tp]
 }
 
+// TraceEnabled 合约配置是否开启执行跟踪
+func (v *XBridge) TraceEnabled() bool {
+	return v.config.EnableTrace
+}
+
 func (v *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context, error) {
 	var desc *protos.WasmCodeDesc
 	var err error
@@ -137,6 +145,14 @@ func (v *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 	ctx.CanInitialize = ctxCfg.CanInitialize
 	ctx.TransferAmount = ctxCfg.TransferAmount
 	ctx.ContractSet = ctxCfg.ContractSet
+	ctx.Capabilities = desc.GetCapabilities()
+	if ctxCfg.ReadOnly {
+		ctx.ReadOnly = true
+		ctx.State = newReadOnlyState(ctxCfg.State)
+	}
+	if v.config.EnableTrace {
+		ctx.Tracer = ctxCfg.Tracer
+	}
 	if ctx.ContractSet == nil {
 		ctx.ContractSet = make(map[string]bool)
 		ctx.ContractSet[ctx.ContractName] = true
@@ -167,5 +183,6 @@ func (v *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 		ctx:      ctx,
 		instance: instance,
 		release:  release,
+		bcname:   v.bcname,
 	}, nil
 }
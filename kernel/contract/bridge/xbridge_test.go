@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// contextCreator 记录创建实例时使用的Context
+type contextCreator struct {
+	ctx *Context
+}
+
+func (c *contextCreator) CreateInstance(ctx *Context, cp ContractCodeProvider) (Instance, error) {
+	c.ctx = ctx
+	return &panicInstance{exec: func() error { return nil }}, nil
+}
+
+func (c *contextCreator) RemoveCache(name string) {}
+
+func TestNewContextTracer(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "contract")
+	for _, enable := range []bool{false, true} {
+		creator := new(contextCreator)
+		v := &XBridge{
+			ctxmgr:          NewContextManager(),
+			creators:        map[ContractType]InstanceCreator{TypeKernel: creator},
+			config:          contract.ContractConfig{EnableTrace: enable},
+			debugLogger:     log,
+			contractManager: new(contractManager),
+		}
+		tracer := contract.NewTracer(0)
+		ctx, err := v.NewContext(&contract.ContextConfig{
+			Module:       string(TypeKernel),
+			ContractName: "$trace",
+			Tracer:       tracer,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 未开启EnableTrace时忽略调用方传入的Tracer
+		if enable && creator.ctx.Tracer != tracer {
+			t.Fatal("tracer should be passed to context when trace enabled")
+		}
+		if !enable && creator.ctx.Tracer != nil {
+			t.Fatal("tracer should be dropped when trace disabled")
+		}
+		ctx.Release()
+	}
+}
+
+// permitCore 允许所有合约调用
+type permitCore struct {
+	contract.ChainCore
+}
+
+func (c *permitCore) VerifyContractPermission(initiator string, authRequire []string, contractName, methodName string) (bool, error) {
+	return true, nil
+}
+
+func TestContractCallTracer(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "contract")
+	creator := new(contextCreator)
+	v := &XBridge{
+		ctxmgr:          NewContextManager(),
+		creators:        map[ContractType]InstanceCreator{TypeKernel: creator},
+		config:          contract.ContractConfig{EnableTrace: true},
+		core:            &permitCore{},
+		debugLogger:     log,
+		contractManager: new(contractManager),
+	}
+	v.syscallService = NewSyscallService(v.ctxmgr, v)
+
+	tracer := contract.NewTracer(0)
+	caller, err := v.NewContext(&contract.ContextConfig{
+		Module:         string(TypeKernel),
+		ContractName:   "$caller",
+		ResourceLimits: contract.MaxLimits,
+		Tracer:         tracer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caller.Release()
+	callerCtx := creator.ctx
+
+	// 被调用合约没有输出，调用本身失败，这里只关心跟踪是否传递
+	v.syscallService.ContractCall(context.TODO(), &pb.ContractCallRequest{
+		Header:   &pb.SyscallHeader{Ctxid: callerCtx.ID},
+		Module:   string(TypeKernel),
+		Contract: "$callee",
+		Method:   "get",
+	})
+	if creator.ctx == callerCtx || creator.ctx.ContractName != "$callee" {
+		t.Fatal("callee context not created")
+	}
+	if creator.ctx.Tracer != tracer {
+		t.Fatal("tracer should be propagated to nested contract call")
+	}
+}
@@ -1,6 +1,13 @@
 package contract
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/xuperchain/xupercore/lib/logs"
 )
 
@@ -39,6 +46,124 @@ type XVMConfig struct {
 	// The higher the number, the faster the program runs,
 	// but the compilation speed will be slower
 	OptLevel int `yaml:"optlevel"`
+	// GasSchedule 合约执行的gas计价表，全网节点必须保持一致
+	GasSchedule XVMGasSchedule `yaml:"gasSchedule"`
+	// MaxCodeSize 合约代码的最大字节数，0表示不限制
+	// 部署和升级时同样按此值检查，全网节点必须保持一致
+	MaxCodeSize int64 `yaml:"maxCodeSize"`
+	// WASI 提供给wasi合约的命令行参数和环境变量，会影响合约执行结果，全网节点必须保持一致
+	WASI WASIConfig `yaml:"wasi"`
+	// StrictDeterminism 严格确定性模式，时间和随机数相关的宿主函数调用即trap
+	// 会影响合约执行结果，全网节点必须保持一致
+	StrictDeterminism bool `yaml:"strictDeterminism"`
+}
+
+// WASIConfig wasi运行环境配置
+// 合约无法访问宿主机文件系统，因此不支持preopen目录
+type WASIConfig struct {
+	Args []string          `yaml:"args"`
+	Env  map[string]string `yaml:"env"`
+}
+
+// Validate 检查wasi配置是否合法
+func (w *WASIConfig) Validate() error {
+	for _, arg := range w.Args {
+		if strings.IndexByte(arg, 0) >= 0 {
+			return fmt.Errorf("bad wasi arg:%q", arg)
+		}
+	}
+	for k, v := range w.Env {
+		if k == "" || strings.ContainsAny(k, "=\x00") || strings.IndexByte(v, 0) >= 0 {
+			return fmt.Errorf("bad wasi env:%q", k)
+		}
+	}
+	return nil
+}
+
+// Environ 返回按key排序的"KEY=VALUE"列表，保证各节点顺序一致
+func (w *WASIConfig) Environ() []string {
+	keys := make([]string, 0, len(w.Env))
+	for k := range w.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	environ := make([]string, 0, len(keys))
+	for _, k := range keys {
+		environ = append(environ, k+"="+w.Env[k])
+	}
+	return environ
+}
+
+// CheckCodeSize 检查合约代码大小是否超过限制
+func (x *XVMConfig) CheckCodeSize(size int) error {
+	if x.MaxCodeSize > 0 && int64(size) > x.MaxCodeSize {
+		return fmt.Errorf("contract too large: code size %d exceeds limit %d", size, x.MaxCodeSize)
+	}
+	return nil
+}
+
+// XVMGasSchedule 描述xvm合约的gas计价方式
+// 指令级别的gas由xvm编译期的gas表决定，这里只能整体按比例调整
+type XVMGasSchedule struct {
+	// InstructionRatio 指令gas的千分比系数，0表示使用默认值1000
+	InstructionRatio int64 `yaml:"instructionRatio"`
+	// MemoryPageCost 每个内存页(64KiB)的gas消耗
+	MemoryPageCost int64 `yaml:"memoryPageCost"`
+	// SyscallCost 每次系统调用的gas消耗
+	SyscallCost int64 `yaml:"syscallCost"`
+}
+
+// DefaultInstructionRatio 默认的指令gas千分比系数
+const DefaultInstructionRatio = 1000
+
+// Ratio 返回生效的指令gas千分比系数
+func (g *XVMGasSchedule) Ratio() int64 {
+	if g.InstructionRatio == 0 {
+		return DefaultInstructionRatio
+	}
+	return g.InstructionRatio
+}
+
+// Validate 检查计价表参数是否合法
+func (g *XVMGasSchedule) Validate() error {
+	if g.InstructionRatio < 0 {
+		return fmt.Errorf("bad xvm gas instructionRatio:%d", g.InstructionRatio)
+	}
+	if g.MemoryPageCost < 0 {
+		return fmt.Errorf("bad xvm gas memoryPageCost:%d", g.MemoryPageCost)
+	}
+	if g.SyscallCost < 0 {
+		return fmt.Errorf("bad xvm gas syscallCost:%d", g.SyscallCost)
+	}
+	return nil
+}
+
+// Hash 返回生效计价表的摘要，用于节点间比对计价表是否一致
+func (g *XVMGasSchedule) Hash() []byte {
+	var buf [24]byte
+	binary.BigEndian.PutUint64(buf[0:], uint64(g.Ratio()))
+	binary.BigEndian.PutUint64(buf[8:], uint64(g.MemoryPageCost))
+	binary.BigEndian.PutUint64(buf[16:], uint64(g.SyscallCost))
+	sum := sha256.Sum256(buf[:])
+	return sum[:]
+}
+
+// GasScheduleCapabilityPrefix 节点能力中计价表摘要的前缀
+const GasScheduleCapabilityPrefix = "xvm-gas/"
+
+// Capability 返回在节点握手中宣告计价表摘要的能力标识
+func (g *XVMGasSchedule) Capability() string {
+	return GasScheduleCapabilityPrefix + hex.EncodeToString(g.Hash()[:8])
+}
+
+// GasScheduleCapability 从节点宣告的能力列表中取出计价表能力标识，未宣告时返回空
+func GasScheduleCapability(caps []string) string {
+	for _, c := range caps {
+		if strings.HasPrefix(c, GasScheduleCapabilityPrefix) {
+			return c
+		}
+	}
+	return ""
 }
 
 // WasmConfig wasm config
@@ -102,6 +227,8 @@ type ContractConfig struct {
 	EnableDebugLog bool
 	EnableUpgrade  bool
 	LogDriver      logs.Logger
+	// EnableTrace 是否允许合约调用携带Tracer，跟踪有额外开销，仅用于调试
+	EnableTrace bool
 
 	Native  NativeConfig
 	Wasm    WasmConfig
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXVMGasSchedule(t *testing.T) {
+	var schedule XVMGasSchedule
+	if err := schedule.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Ratio() != DefaultInstructionRatio {
+		t.Fatalf("expect default ratio, got %d", schedule.Ratio())
+	}
+
+	// 显式配置默认系数与不配置应当得到相同的摘要
+	explicit := XVMGasSchedule{InstructionRatio: DefaultInstructionRatio}
+	if !bytes.Equal(schedule.Hash(), explicit.Hash()) {
+		t.Fatal("expect same hash for default ratio")
+	}
+
+	changed := XVMGasSchedule{SyscallCost: 10}
+	if bytes.Equal(schedule.Hash(), changed.Hash()) {
+		t.Fatal("expect different hash for different schedule")
+	}
+
+	caps := []string{"other", changed.Capability()}
+	if GasScheduleCapability(caps) != changed.Capability() || GasScheduleCapability(caps[:1]) != "" {
+		t.Fatal("unexpected gas schedule capability lookup")
+	}
+	if schedule.Capability() == changed.Capability() {
+		t.Fatal("expect different capability for different schedule")
+	}
+
+	bad := XVMGasSchedule{MemoryPageCost: -1}
+	if bad.Validate() == nil {
+		t.Fatal("expect error for negative memoryPageCost")
+	}
+}
+
+func TestXVMCheckCodeSize(t *testing.T) {
+	var cfg XVMConfig
+	if err := cfg.CheckCodeSize(1 << 30); err != nil {
+		t.Fatalf("expect no limit by default, got %s", err)
+	}
+	cfg.MaxCodeSize = 1024
+	if err := cfg.CheckCodeSize(1024); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CheckCodeSize(1025) == nil {
+		t.Fatal("expect contract too large error")
+	}
+}
+
+func TestWASIConfig(t *testing.T) {
+	cfg := WASIConfig{
+		Args: []string{"contract"},
+		Env:  map[string]string{"B": "2", "A": "1"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	environ := cfg.Environ()
+	if len(environ) != 2 || environ[0] != "A=1" || environ[1] != "B=2" {
+		t.Fatalf("unexpected environ:%v", environ)
+	}
+
+	cfg.Env["C=D"] = "3"
+	if cfg.Validate() == nil {
+		t.Fatal("expect error for env key containing '='")
+	}
+}
@@ -52,4 +52,10 @@ type ContextConfig struct {
 
 	// ContractCodeFromCache control whether fetch contract code from XMCache
 	ContractCodeFromCache bool
+
+	// Tracer 记录本次调用的系统调用和gas消耗，仅在开启EnableTrace时生效
+	Tracer *Tracer
+
+	// ReadOnly 只读调用，合约调用修改状态的系统调用时执行失败，用于不生成交易的查询
+	ReadOnly bool
 }
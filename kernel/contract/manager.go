@@ -20,6 +20,10 @@ type Manager interface {
 	NewContext(cfg *ContextConfig) (Context, error)
 	NewStateSandbox(cfg *SandboxConfig) (StateSandbox, error)
 	GetKernRegistry() KernRegistry
+	// RefreshContractCache 清除合约编译缓存，warmup为true时立即重新编译
+	RefreshContractCache(contractName string, warmup bool) error
+	// TraceEnabled 是否开启合约执行跟踪，未开启时调用携带的Tracer不会记录
+	TraceEnabled() bool
 }
 
 type ManagerConfig struct {
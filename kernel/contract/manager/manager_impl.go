@@ -53,6 +53,7 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 		logDriver = cfg.Config.LogDriver
 	}
 	xbridge, err := bridge.New(&bridge.XBridgeConfig{
+		BCName:  cfg.BCName,
 		Basedir: cfg.Basedir,
 		VMConfigs: map[bridge.ContractType]bridge.VMConfig{
 			bridge.TypeWasm:   &xcfg.Wasm,
@@ -93,6 +94,14 @@ func (m *managerImpl) GetKernRegistry() contract.KernRegistry {
 	return &m.kregistry
 }
 
+func (m *managerImpl) RefreshContractCache(contractName string, warmup bool) error {
+	return m.xbridge.RefreshContractCache(contractName, warmup)
+}
+
+func (m *managerImpl) TraceEnabled() bool {
+	return m.xbridge.TraceEnabled()
+}
+
 func (m *managerImpl) deployContract(ctx contract.KContext) (*contract.Response, error) {
 	// check if account exist
 	accountName := ctx.Args()["account_name"]
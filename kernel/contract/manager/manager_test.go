@@ -67,3 +67,14 @@ func (h *helloContract) Hi(ctx contract.KContext) (*contract.Response, error) {
 		Body: []byte("hello " + string(name)),
 	}, nil
 }
+
+func TestRefreshContractCacheNotExist(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	m := th.Manager()
+
+	err := m.RefreshContractCache("notexist", true)
+	if err == nil {
+		t.Fatal("expect error for non-existent contract")
+	}
+}
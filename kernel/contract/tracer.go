@@ -0,0 +1,98 @@
+package contract
+
+import (
+	"sync"
+)
+
+// DefaultTracePayloadSize 每条跟踪记录中请求和响应保留的最大字节数
+const DefaultTracePayloadSize = 256
+
+// TraceEntry 合约执行过程中一次系统调用的跟踪记录
+type TraceEntry struct {
+	// 发起系统调用的合约，跨合约调用时为被调用的合约
+	Contract string `json:"contract"`
+	Method   string `json:"method"`
+	Request  []byte `json:"request,omitempty"`
+	Response []byte `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// 发起调用的合约截止到本次系统调用的累计gas，与计费使用的gas一致
+	GasUsed int64 `json:"gasUsed"`
+	// 请求或响应超过长度限制被截断
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// InvokeTrace 一次合约调用的跟踪结果，包含其中跨合约调用的系统调用
+type InvokeTrace struct {
+	ModuleName   string       `json:"moduleName"`
+	ContractName string       `json:"contractName"`
+	MethodName   string       `json:"methodName"`
+	Entries      []TraceEntry `json:"entries"`
+}
+
+// Tracer 记录单次合约调用的系统调用序列和gas消耗，用于调试合约，
+// 只读取执行过程中的数据，不会影响执行结果
+type Tracer struct {
+	mutex       sync.Mutex
+	payloadSize int
+	entries     []TraceEntry
+}
+
+// NewTracer instances a new Tracer, payloadSize<=0时使用DefaultTracePayloadSize
+func NewTracer(payloadSize int) *Tracer {
+	if payloadSize <= 0 {
+		payloadSize = DefaultTracePayloadSize
+	}
+	return &Tracer{
+		payloadSize: payloadSize,
+	}
+}
+
+// Record 记录一次系统调用，t为nil时忽略
+func (t *Tracer) Record(contractName, method string, request, response []byte, err error, gasUsed int64) {
+	if t == nil {
+		return
+	}
+	entry := TraceEntry{
+		Contract: contractName,
+		Method:   method,
+		GasUsed:  gasUsed,
+	}
+	var truncated bool
+	entry.Request, truncated = t.redact(request)
+	entry.Truncated = truncated
+	entry.Response, truncated = t.redact(response)
+	entry.Truncated = entry.Truncated || truncated
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries = append(t.entries, entry)
+}
+
+// Entries 返回已记录的跟踪信息副本
+func (t *Tracer) Entries() []TraceEntry {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	entries := make([]TraceEntry, len(t.entries))
+	copy(entries, t.entries)
+	return entries
+}
+
+// redact 拷贝数据并截断到payloadSize，避免跟踪信息持有虚拟机内存或占用过多空间
+func (t *Tracer) redact(buf []byte) ([]byte, bool) {
+	if len(buf) == 0 {
+		return nil, false
+	}
+	n := len(buf)
+	if n > t.payloadSize {
+		n = t.payloadSize
+	}
+	result := make([]byte, n)
+	copy(result, buf)
+	return result, n < len(buf)
+}
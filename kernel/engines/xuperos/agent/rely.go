@@ -150,12 +150,13 @@ func (t *ChainRelyAgentImpl) CreateConsensus() (consensus.PluggableConsensusInte
 	ctx := t.chain.Context()
 	legAgent := NewLedgerAgent(ctx)
 	consCtx := cctx.ConsensusCtx{
-		BcName:   ctx.BCName,
-		Address:  (*cctx.Address)(ctx.Address),
-		Crypto:   ctx.Crypto,
-		Contract: ctx.Contract,
-		Ledger:   legAgent,
-		Network:  ctx.EngCtx.Net,
+		BcName:            ctx.BCName,
+		Address:           (*cctx.Address)(ctx.Address),
+		Crypto:            ctx.Crypto,
+		Contract:          ctx.Contract,
+		Ledger:            legAgent,
+		Network:           ctx.EngCtx.Net,
+		JustifySampleSize: ctx.EngCtx.EngCfg.JustifySampleSize,
 	}
 
 	log, err := logs.NewLogger("", cdef.SubModName)
@@ -18,6 +18,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/miner"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/parachain"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/lib/timer"
@@ -77,8 +78,20 @@ func LoadChain(engCtx *common.EngineCtx, bcName string) (*Chain, error) {
 		return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
 	}
 
+	// 启动前检查状态库与账本是否一致，尽早发现状态库损坏
+	if depth := engCtx.EngCfg.StartupVerifyDepth; depth > 0 {
+		if err := ctx.State.VerifyRecentBlocks(depth); err != nil {
+			log.Error("verify state integrity failed", "bcName", bcName, "depth", depth, "err", err)
+			return nil, common.ErrStateIntegrity.More("err:%v", err)
+		}
+	}
+
 	// 创建矿工
-	chainObj.miner = miner.NewMiner(ctx)
+	chainObj.miner, err = miner.NewMiner(ctx)
+	if err != nil {
+		log.Error("new miner failed", "bcName", bcName, "err", err)
+		return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
+	}
 	chainObj.txIdCache = cache.New(TxIdCacheExpired, TxIdCacheGCInterval)
 
 	return chainObj, nil
@@ -100,6 +113,46 @@ func (t *Chain) Start() {
 	t.miner.Start()
 }
 
+func (t *Chain) PauseMining() {
+	t.miner.PauseMining()
+}
+
+func (t *Chain) ResumeMining() {
+	t.miner.ResumeMining()
+}
+
+func (t *Chain) IsMiningPaused() bool {
+	return t.miner.IsMiningPaused()
+}
+
+func (t *Chain) IsPartitioned() bool {
+	return t.miner.IsPartitioned()
+}
+
+func (t *Chain) LatestCheckpoint() (*xpb.Checkpoint, error) {
+	return t.miner.LatestCheckpoint()
+}
+
+func (t *Chain) ForceResync() (int64, error) {
+	return t.miner.ForceResync()
+}
+
+func (t *Chain) RecentRoundTimings() []common.RoundTiming {
+	return t.miner.RecentRoundTimings()
+}
+
+func (t *Chain) RefreshContractCache(contractName string, warmup bool) error {
+	if contractName == "" {
+		return common.ErrParameter
+	}
+	if err := t.ctx.Contract.RefreshContractCache(contractName, warmup); err != nil {
+		t.log.Warn("refresh contract cache failed", "contractName", contractName, "warmup", warmup, "err", err)
+		return common.ErrContractRefreshCacheFailed.More("%v", err)
+	}
+	t.log.Info("refresh contract cache succ", "contractName", contractName, "warmup", warmup)
+	return nil
+}
+
 func (t *Chain) Stop() {
 	// 停止矿工等其余组件
 	t.miner.Stop()
@@ -116,24 +169,41 @@ func (t *Chain) Context() *common.ChainCtx {
 
 // 交易预执行
 func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiator string, authRequires []string) (*protos.InvokeResponse, error) {
+	resp, _, err := t.preExec(ctx, reqs, initiator, authRequires, false)
+	return resp, err
+}
+
+// 合约预执行并返回每个合约调用的系统调用和gas跟踪，合约配置未开启enableTrace时返回错误
+func (t *Chain) TracePreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiator string,
+	authRequires []string) (*protos.InvokeResponse, []*contract.InvokeTrace, error) {
+	if !t.ctx.Contract.TraceEnabled() {
+		return nil, nil, common.ErrContractTraceDisabled
+	}
+	return t.preExec(ctx, reqs, initiator, authRequires, true)
+}
+
+// preExec traced为true时为每个合约调用创建Tracer，跨合约调用记录在发起调用的跟踪中，
+// 合约执行失败时同样返回已记录的跟踪，便于定位失败原因
+func (t *Chain) preExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiator string,
+	authRequires []string, traced bool) (*protos.InvokeResponse, []*contract.InvokeTrace, error) {
 	if ctx == nil || ctx.GetLog() == nil {
-		return nil, common.ErrParameter
+		return nil, nil, common.ErrParameter
 	}
 
 	reservedRequests, err := t.ctx.State.GetReservedContractRequests(reqs, true)
 	if err != nil {
 		t.log.Error("PreExec get reserved contract request error", "error", err)
-		return nil, common.ErrParameter.More("%v", err)
+		return nil, nil, common.ErrParameter.More("%v", err)
 	}
 
 	transContractName, transAmount, err := tx.ParseContractTransferRequest(reqs)
 	if err != nil {
-		return nil, common.ErrParameter.More("%v", err)
+		return nil, nil, common.ErrParameter.More("%v", err)
 	}
 
 	reqs = append(reservedRequests, reqs...)
 	if len(reqs) <= 0 {
-		return &protos.InvokeResponse{}, nil
+		return &protos.InvokeResponse{}, nil, nil
 	}
 
 	stateConfig := &contract.SandboxConfig{
@@ -143,7 +213,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 	sandbox, err := t.ctx.Contract.NewStateSandbox(stateConfig)
 	if err != nil {
 		t.log.Error("PreExec new state sandbox error", "error", err)
-		return nil, common.ErrContractNewSandboxFailed
+		return nil, nil, common.ErrContractNewSandboxFailed
 	}
 
 	contextConfig := &contract.ContextConfig{
@@ -158,6 +228,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 	responseBodes := make([][]byte, 0, len(reqs))
 	requests := make([]*protos.InvokeRequest, 0, len(reqs))
 	responses := make([]*protos.ContractResponse, 0, len(reqs))
+	var traces []*contract.InvokeTrace
 	for i, req := range reqs {
 		if req == nil {
 			continue
@@ -176,6 +247,11 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		} else {
 			contextConfig.TransferAmount = ""
 		}
+		var tracer *contract.Tracer
+		if traced {
+			tracer = contract.NewTracer(0)
+		}
+		contextConfig.Tracer = tracer
 
 		context, err := t.ctx.Contract.NewContext(contextConfig)
 		if err != nil {
@@ -184,22 +260,30 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 				requests = append(requests, req)
 				continue
 			}
-			return nil, common.ErrContractNewCtxFailed.More("%v", err)
+			return nil, nil, common.ErrContractNewCtxFailed.More("%v", err)
 		}
 
 		resp, err := context.Invoke(req.MethodName, req.Args)
+		if tracer != nil {
+			traces = append(traces, &contract.InvokeTrace{
+				ModuleName:   req.ModuleName,
+				ContractName: req.ContractName,
+				MethodName:   req.MethodName,
+				Entries:      tracer.Entries(),
+			})
+		}
 		if err != nil {
 			context.Release()
 			ctx.GetLog().Error("PreExec Invoke error", "error", err, "contractName", req.ContractName)
 			metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "InvokeError").Inc()
-			return nil, common.ErrContractInvokeFailed.More("%v", err)
+			return nil, traces, common.ErrContractInvokeFailed.More("%v", err)
 		}
 
 		if resp.Status >= 400 && i < len(reservedRequests) {
 			context.Release()
 			ctx.GetLog().Error("PreExec Invoke error", "status", resp.Status, "contractName", req.ContractName)
 			metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "InvokeError").Inc()
-			return nil, common.ErrContractInvokeFailed.More("%v", resp.Message)
+			return nil, traces, common.ErrContractInvokeFailed.More("%v", resp.Message)
 		}
 
 		metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "OK").Inc()
@@ -228,7 +312,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 
 	err = sandbox.Flush()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	rwSet := sandbox.RWSet()
 	utxoRWSet := sandbox.UTXORWSet()
@@ -244,7 +328,50 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		UtxoOutputs: utxoRWSet.WSet,
 	}
 
-	return invokeResponse, nil
+	return invokeResponse, traces, nil
+}
+
+// 只读调用合约，合约修改状态时调用失败，不生成读写集也不计算gas，用于不提交交易的查询
+func (t *Chain) ViewCall(ctx xctx.XContext, req *protos.InvokeRequest, initiator string) (*protos.ContractResponse, error) {
+	if ctx == nil || ctx.GetLog() == nil || req == nil || req.ContractName == "" || req.MethodName == "" {
+		return nil, common.ErrParameter
+	}
+
+	stateConfig := &contract.SandboxConfig{
+		XMReader:   t.ctx.State.CreateXMReader(),
+		UTXOReader: t.ctx.State.CreateUtxoReader(),
+	}
+	sandbox, err := t.ctx.Contract.NewStateSandbox(stateConfig)
+	if err != nil {
+		t.log.Error("ViewCall new state sandbox error", "error", err)
+		return nil, common.ErrContractNewSandboxFailed
+	}
+
+	context, err := t.ctx.Contract.NewContext(&contract.ContextConfig{
+		State:          sandbox,
+		Initiator:      initiator,
+		Module:         req.ModuleName,
+		ContractName:   req.ContractName,
+		ResourceLimits: contract.MaxLimits,
+		ReadOnly:       true,
+	})
+	if err != nil {
+		ctx.GetLog().Error("ViewCall NewContext error", "error", err, "contractName", req.ContractName)
+		return nil, common.ErrContractNewCtxFailed.More("%v", err)
+	}
+	defer context.Release()
+
+	resp, err := context.Invoke(req.MethodName, req.Args)
+	if err != nil {
+		ctx.GetLog().Error("ViewCall Invoke error", "error", err, "contractName", req.ContractName)
+		return nil, common.ErrContractInvokeFailed.More("%v", err)
+	}
+
+	return &protos.ContractResponse{
+		Status:  int32(resp.Status),
+		Message: resp.Message,
+		Body:    resp.Body,
+	}, nil
 }
 
 // 提交交易到交易池(xuperos引擎同时更新到状态机和交易池)
@@ -2,6 +2,7 @@ package xuperos
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -10,7 +11,10 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/utils"
 	"github.com/xuperchain/xupercore/protos"
 )
@@ -223,3 +227,82 @@ func TestChain_PreExec(t *testing.T) {
 		return
 	}
 }
+
+func TestChain_TracePreExec(t *testing.T) {
+	engine, err := MockEngine("p2pv2/node1/conf/env.yaml")
+	if err != nil {
+		t.Logf("%v", err)
+		return
+	}
+
+	chain, err := engine.Get("xuper")
+	if err != nil {
+		t.Errorf("get chain error: %v", err)
+		return
+	}
+	reqs := []*protos.InvokeRequest{
+		{
+			ModuleName:   "xkernel",
+			ContractName: "$acl",
+			MethodName:   "QueryAccountACL",
+			Args: map[string][]byte{
+				"account_name": []byte("XC1111111111111111@xuper"),
+			},
+		},
+	}
+
+	_, traces, err := chain.TracePreExec(chain.Context(), reqs, "", nil)
+	if !chain.Context().Contract.TraceEnabled() {
+		if err != common.ErrContractTraceDisabled {
+			t.Errorf("expect trace disabled error, got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("trace pre exec error: %v", err)
+		return
+	}
+	if len(traces) != 1 || traces[0].ContractName != "$acl" || traces[0].MethodName != "QueryAccountACL" {
+		t.Errorf("unexpected traces %v", traces)
+	}
+}
+
+// refreshManager 记录清除合约缓存的请求
+type refreshManager struct {
+	contract.Manager
+	refreshed []string
+	warmup    bool
+	err       error
+}
+
+func (m *refreshManager) RefreshContractCache(contractName string, warmup bool) error {
+	m.refreshed = append(m.refreshed, contractName)
+	m.warmup = warmup
+	return m.err
+}
+
+func TestChain_RefreshContractCache(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "chain")
+	mgr := &refreshManager{}
+	chain := &Chain{
+		ctx: &common.ChainCtx{Contract: mgr},
+		log: log,
+	}
+
+	if err := chain.RefreshContractCache("counter", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(mgr.refreshed) != 1 || mgr.refreshed[0] != "counter" || !mgr.warmup {
+		t.Fatalf("unexpected refresh %v warmup %v", mgr.refreshed, mgr.warmup)
+	}
+
+	if err := chain.RefreshContractCache("", false); err != common.ErrParameter {
+		t.Fatalf("expect param error, got %v", err)
+	}
+	mgr.err = errors.New("contract counter not exists")
+	err := chain.RefreshContractCache("counter", false)
+	if common.CastError(err).Code != common.ErrContractRefreshCacheFailed.Code {
+		t.Fatalf("expect refresh cache error, got %v", err)
+	}
+}
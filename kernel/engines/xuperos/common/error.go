@@ -82,11 +82,13 @@ var (
 	ErrChainExist        = &Error{ErrStatusInternalErr, 50204, "chain already exists"}
 	ErrChainNotExist     = &Error{ErrStatusInternalErr, 50205, "chain not exist"}
 	ErrChainAlreadyExist = &Error{ErrStatusInternalErr, 50206, "chain already exist"}
+	ErrStateIntegrity    = &Error{ErrStatusInternalErr, 50207, "state integrity check failed"}
 
 	// block
-	ErrBlockNotExist    = &Error{ErrStatusInternalErr, 50300, "block not exist"}
-	ErrProcBlockFailed  = &Error{ErrStatusInternalErr, 50301, "process block failed"}
-	ErrGenesisBlockDiff = &Error{ErrStatusInternalErr, 50302, "genesis block diff"}
+	ErrBlockNotExist      = &Error{ErrStatusInternalErr, 50300, "block not exist"}
+	ErrProcBlockFailed    = &Error{ErrStatusInternalErr, 50301, "process block failed"}
+	ErrGenesisBlockDiff   = &Error{ErrStatusInternalErr, 50302, "genesis block diff"}
+	ErrCheckpointNotExist = &Error{ErrStatusInternalErr, 50303, "checkpoint not exist"}
 
 	// tx
 	ErrTxVerifyFailed        = &Error{ErrStatusInternalErr, 50400, "verify tx failed"}
@@ -95,11 +97,14 @@ var (
 	ErrTxNotEnough           = &Error{ErrStatusInternalErr, 50403, "tx not enough"}
 	ErrSubmitTxFailed        = &Error{ErrStatusInternalErr, 50404, "submit tx failed"}
 	ErrGenerateTimerTxFailed = &Error{ErrStatusInternalErr, 50405, "generate timer tx failed"}
+	ErrNoTimerTx             = &Error{ErrStatusInternalErr, 50406, "no timer tx at height"}
 
 	// contract
-	ErrContractNewCtxFailed     = &Error{ErrStatusInternalErr, 50500, "contract new context failed"}
-	ErrContractInvokeFailed     = &Error{ErrStatusInternalErr, 50501, "contract invoke failed"}
-	ErrContractNewSandboxFailed = &Error{ErrStatusInternalErr, 50502, "contract new sandbox failed"}
+	ErrContractNewCtxFailed       = &Error{ErrStatusInternalErr, 50500, "contract new context failed"}
+	ErrContractInvokeFailed       = &Error{ErrStatusInternalErr, 50501, "contract invoke failed"}
+	ErrContractNewSandboxFailed   = &Error{ErrStatusInternalErr, 50502, "contract new sandbox failed"}
+	ErrContractTraceDisabled      = &Error{ErrStatusInternalErr, 50503, "contract trace disabled"}
+	ErrContractRefreshCacheFailed = &Error{ErrStatusInternalErr, 50504, "contract refresh cache failed"}
 
 	// net
 	ErrNewNetEventFailed = &Error{ErrStatusInternalErr, 50600, "new net event failed"}
@@ -12,6 +12,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/propose"
 	timerTask "github.com/xuperchain/xupercore/kernel/contract/proposal/timer"
 	"github.com/xuperchain/xupercore/kernel/engines"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/network"
 	aclBase "github.com/xuperchain/xupercore/kernel/permission/acl/base"
@@ -28,12 +29,32 @@ type Chain interface {
 	Stop()
 	// 合约预执行
 	PreExec(xctx.XContext, []*protos.InvokeRequest, string, []string) (*protos.InvokeResponse, error)
+	// 合约预执行并返回执行跟踪，需要合约配置开启enableTrace
+	TracePreExec(xctx.XContext, []*protos.InvokeRequest, string, []string) (*protos.InvokeResponse, []*contract.InvokeTrace, error)
+	// 合约只读调用
+	ViewCall(xctx.XContext, *protos.InvokeRequest, string) (*protos.ContractResponse, error)
 	// 提交交易
 	SubmitTx(xctx.XContext, *lpb.Transaction) error
 	// 处理新区块
 	ProcBlock(xctx.XContext, *lpb.InternalBlock) error
 	// 设置依赖实例化代理
 	SetRelyAgent(ChainRelyAgent) error
+	// 暂停出块，节点继续同步区块
+	PauseMining()
+	// 恢复出块
+	ResumeMining()
+	// 是否暂停出块
+	IsMiningPaused() bool
+	// 是否检测到网络分区
+	IsPartitioned() bool
+	// 本节点最新签名的检查点
+	LatestCheckpoint() (*xpb.Checkpoint, error)
+	// 立即向最长链同步，返回同步后的账本高度
+	ForceResync() (int64, error)
+	// 最近若干轮出块的各阶段耗时，由旧到新排列
+	RecentRoundTimings() []RoundTiming
+	// 清除合约的本地编译缓存，warmup为true时立即重新编译
+	RefreshContractCache(contractName string, warmup bool) error
 }
 
 // 定义xuperos引擎对外暴露接口
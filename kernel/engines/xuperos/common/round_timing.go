@@ -0,0 +1,21 @@
+package common
+
+import (
+	"time"
+
+	"github.com/xuperchain/xupercore/lib/timer"
+)
+
+// RoundTiming 一轮出块各阶段的耗时，阶段来自矿工循环上下文中timer的标记点
+type RoundTiming struct {
+	// Height 产生的区块高度
+	Height int64
+	// BlockId 产生的区块id
+	BlockId []byte
+	// FinishTime 本轮出块完成的时间
+	FinishTime time.Time
+	// Stages 按执行顺序排列的阶段耗时，如ProcessBeforeMiner、PackBlock、ConfirmBlock等
+	Stages []timer.Stage
+	// Total 本轮矿工循环的总耗时
+	Total time.Duration
+}
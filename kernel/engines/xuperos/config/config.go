@@ -29,6 +29,61 @@ type EngineConf struct {
 	SyncBlockFilterMode int `yaml:"syncBlockFilterMode,omitempty"`
 	// SyncFactorForFactorBucketMode only use for SyncWithFactorBucket mode of SyncBlockFilterMode configuration item
 	SyncFactorForFactorBucketMode float64 `yaml:"SyncFactorForFactorBucketMode,omitempty"`
+	// SyncStatusFanout is the number of random validators queried for chain status, 0 means all validators
+	SyncStatusFanout int `yaml:"syncStatusFanout,omitempty"`
+	// SyncStatusEscalate queries the remaining validators when fewer than a quorum of the fan-out respond
+	SyncStatusEscalate bool `yaml:"syncStatusEscalate,omitempty"`
+	// SyncStatusTimeout is the deadline of one chain status query, 0 means the network timeout
+	SyncStatusTimeout time.Duration `yaml:"syncStatusTimeout,omitempty"`
+	// StartupVerifyDepth is the number of recent blocks checked against state at startup, 0 means skip
+	StartupVerifyDepth int64 `yaml:"startupVerifyDepth,omitempty"`
+	// TxRebroadcastInterval is the interval for re-broadcasting unconfirmed txs, 0 means disabled
+	TxRebroadcastInterval time.Duration `yaml:"txRebroadcastInterval,omitempty"`
+	// TxRebroadcastBatchSize is the max number of txs re-broadcast in one round
+	TxRebroadcastBatchSize int `yaml:"txRebroadcastBatchSize,omitempty"`
+	// PartitionTimeout is how long the node may have no peers before it is considered partitioned, 0 means disabled
+	PartitionTimeout time.Duration `yaml:"partitionTimeout,omitempty"`
+	// PartitionReadOnly stops producing blocks while the node is partitioned
+	PartitionReadOnly bool `yaml:"partitionReadOnly,omitempty"`
+	// MaxConsensusDataSize is the max bytes of consensus data packed into a block, 0 means no limit
+	MaxConsensusDataSize int `yaml:"maxConsensusDataSize,omitempty"`
+	// WalkFailureThreshold is the number of consecutive state walk failures before trying to recover, 0 means disabled
+	WalkFailureThreshold int `yaml:"walkFailureThreshold,omitempty"`
+	// WalkRecoveryDepth is how many blocks the state is rolled back below the ledger tip when recovering, at most 1000, 0 means only replaying
+	WalkRecoveryDepth int64 `yaml:"walkRecoveryDepth,omitempty"`
+	// MaxTxCountPerBlock is the max number of txs packed into a block, including award and timer txs, 0 means no limit
+	MaxTxCountPerBlock int `yaml:"maxTxCountPerBlock,omitempty"`
+	// CheckpointInterval is the block interval for signing a checkpoint, 0 means disabled
+	CheckpointInterval int64 `yaml:"checkpointInterval,omitempty"`
+	// CheckpointAuthorities are the addresses trusted to sign checkpoints
+	CheckpointAuthorities []string `yaml:"checkpointAuthorities,omitempty"`
+	// GetBlockCacheSize is the number of recently requested blocks cached for serving GET_BLOCK, 0 means no cache
+	GetBlockCacheSize int `yaml:"getBlockCacheSize,omitempty"`
+	// RewardAuditLog logs a hash chained record of the award of every block mined by this node
+	RewardAuditLog bool `yaml:"rewardAuditLog,omitempty"`
+	// JustifySampleSize is the number of justify signatures verified for a received block, 0 means verify all.
+	// It is raised to the quorum size, only verified signatures count as votes, and the sample is derived from the proposal id
+	JustifySampleSize int `yaml:"justifySampleSize,omitempty"`
+	// MinerWarnThrottle is the interval in which identical miner loop warnings are logged once with a suppressed count, 0 means disabled
+	MinerWarnThrottle time.Duration `yaml:"minerWarnThrottle,omitempty"`
+	// ClockSkewCheck estimates local clock skew from timestamps of recently received blocks before mining
+	ClockSkewCheck bool `yaml:"clockSkewCheck,omitempty"`
+	// ClockSkewThreshold is the max tolerated skew between local clock and network time
+	ClockSkewThreshold time.Duration `yaml:"clockSkewThreshold,omitempty"`
+	// ClockSkewRefuseMining stops producing blocks while the skew exceeds the threshold, otherwise only warns
+	ClockSkewRefuseMining bool `yaml:"clockSkewRefuseMining,omitempty"`
+	// VerifiedBlockCacheSize is the number of recently verified blocks whose verification is skipped when re-applied after a reorg, 0 means no cache
+	VerifiedBlockCacheSize int `yaml:"verifiedBlockCacheSize,omitempty"`
+	// MaxTxCountPerSender is the max number of txs from one initiator packed into a block, 0 means no limit
+	MaxTxCountPerSender int `yaml:"maxTxCountPerSender,omitempty"`
+	// MaxTxBytesPerSender is the max total size in bytes of txs from one initiator packed into a block, 0 means no limit
+	MaxTxBytesPerSender int `yaml:"maxTxBytesPerSender,omitempty"`
+	// MaxFutureBlockDrift is how far a received block's timestamp may be ahead of local time, 0 means no check
+	MaxFutureBlockDrift time.Duration `yaml:"maxFutureBlockDrift,omitempty"`
+	// FutureBlockHold is the longest a batch of blocks beyond MaxFutureBlockDrift is held in total until they become valid, 0 means reject at once
+	FutureBlockHold time.Duration `yaml:"futureBlockHold,omitempty"`
+	// MiningTimingHistory is the number of recent mining rounds whose stage timings are kept, 0 means not kept
+	MiningTimingHistory int `yaml:"miningTimingHistory,omitempty"`
 }
 
 func LoadEngineConf(cfgFile string) (*EngineConf, error) {
@@ -50,6 +105,33 @@ func GetDefEngineConf() *EngineConf {
 		MaxBlockQueueSize:             100,
 		SyncBlockFilterMode:           0,
 		SyncFactorForFactorBucketMode: 0.5,
+		SyncStatusFanout:              0,
+		SyncStatusEscalate:            true,
+		SyncStatusTimeout:             5 * time.Second,
+		StartupVerifyDepth:            0,
+		TxRebroadcastInterval:         0,
+		TxRebroadcastBatchSize:        100,
+		PartitionTimeout:              0,
+		PartitionReadOnly:             false,
+		MaxConsensusDataSize:          1 << 20,
+		WalkFailureThreshold:          0,
+		WalkRecoveryDepth:             20,
+		MaxTxCountPerBlock:            0,
+		CheckpointInterval:            0,
+		CheckpointAuthorities:         []string{},
+		GetBlockCacheSize:             64,
+		RewardAuditLog:                false,
+		JustifySampleSize:             0,
+		MinerWarnThrottle:             0,
+		ClockSkewCheck:                false,
+		ClockSkewThreshold:            3 * time.Second,
+		ClockSkewRefuseMining:         false,
+		VerifiedBlockCacheSize:        256,
+		MaxTxCountPerSender:           0,
+		MaxTxBytesPerSender:           0,
+		MaxFutureBlockDrift:           0,
+		FutureBlockHold:               0,
+		MiningTimingHistory:           32,
 	}
 }
 
@@ -0,0 +1,150 @@
+package miner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 区块归档格式：依次写入每个区块，每个区块为4字节大端序长度加上InternalBlock的protobuf编码
+
+const (
+	// maxArchiveBlockSize 归档中单个区块的最大字节数，防止损坏的长度前缀导致申请过大内存
+	maxArchiveBlockSize = 256 << 20
+	// importBatchSize 导入时每批确认的区块数
+	importBatchSize = 100
+)
+
+var (
+	ErrArchiveTruncated = errors.New("block archive truncated")
+)
+
+// writeArchiveBlock 写入一个带长度前缀的区块
+func writeArchiveBlock(w io.Writer, block *lpb.InternalBlock) error {
+	buf, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+	var head [4]byte
+	binary.BigEndian.PutUint32(head[:], uint32(len(buf)))
+	if _, err := w.Write(head[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// readArchiveBlock 读取一个带长度前缀的区块，在区块边界上读到结尾时返回io.EOF，
+// 区块中途结束时返回ErrArchiveTruncated
+func readArchiveBlock(r io.Reader) (*lpb.InternalBlock, error) {
+	var head [4]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, ErrArchiveTruncated
+		}
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(head[:])
+	if size > maxArchiveBlockSize {
+		return nil, fmt.Errorf("archive block too large: %d", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrArchiveTruncated
+		}
+		return nil, err
+	}
+	block := new(lpb.InternalBlock)
+	if err := proto.Unmarshal(buf, block); err != nil {
+		return nil, fmt.Errorf("unmarshal archive block error: %v", err)
+	}
+	return block, nil
+}
+
+// ExportBlocks 把主干上[from, to]高度的完整区块写入w，用于离线备份
+func (t *Miner) ExportBlocks(from, to int64, w io.Writer) error {
+	if from < 0 || from > to {
+		return fmt.Errorf("bad export range [%d, %d]", from, to)
+	}
+	if tipHeight := t.ctx.Ledger.GetMeta().TrunkHeight; to > tipHeight {
+		return fmt.Errorf("export height %d exceeds trunk height %d", to, tipHeight)
+	}
+
+	bw := bufio.NewWriter(w)
+	for height := from; height <= to; height++ {
+		block, err := t.ctx.Ledger.QueryBlockByHeight(height)
+		if err != nil {
+			return fmt.Errorf("query block at height %d error: %v", height, err)
+		}
+		if err := writeArchiveBlock(bw, block); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportBlocks 从归档中读取区块并追加到账本，返回导入的区块数
+// 默认与同步区块走相同的校验流程，trusted为true时跳过区块校验，仅用于运维可控的归档；
+// 账本中已存在的区块直接跳过。每批区块与矿工循环互斥写入，不会与出块、同步或裁剪交错
+func (t *Miner) ImportBlocks(ctx xctx.XContext, r io.Reader, trusted bool) (int, error) {
+	br := bufio.NewReader(r)
+	imported := 0
+	batch := make([]*lpb.InternalBlock, 0, importBatchSize)
+	flush := func() error {
+		err := t.withChainLock(func() error {
+			return t.confirmBlocks(ctx, batch, trusted)
+		})
+		if err != nil {
+			return err
+		}
+		imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		block, err := readArchiveBlock(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			ctx.GetLog().Warn("read block archive error", "imported", imported+len(batch), "err", err)
+			// 已完整读取的区块仍然导入，再返回错误
+			if ferr := flush(); ferr != nil {
+				return imported, ferr
+			}
+			return imported, err
+		}
+
+		if block.GetHeight() <= t.ctx.Ledger.GetMeta().TrunkHeight && len(batch) == 0 {
+			exist, qerr := t.ctx.Ledger.QueryBlockHeaderByHeight(block.GetHeight())
+			if qerr == nil && bytes.Equal(exist.GetBlockid(), block.GetBlockid()) {
+				continue
+			}
+			return imported, fmt.Errorf("archive block %s at height %d conflicts with ledger",
+				utils.F(block.GetBlockid()), block.GetHeight())
+		}
+
+		batch = append(batch, block)
+		if len(batch) >= importBatchSize {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return imported, err
+	}
+	ctx.GetLog().Info("import block archive finish", "imported", imported, "trusted", trusted)
+	return imported, nil
+}
@@ -0,0 +1,323 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+)
+
+func TestArchiveBlock(t *testing.T) {
+	var buf bytes.Buffer
+	for i := int64(0); i < 3; i++ {
+		block := &lpb.InternalBlock{Height: i, Blockid: []byte{byte(i)}}
+		if err := writeArchiveBlock(&buf, block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := buf.Bytes()
+
+	r := bytes.NewReader(data)
+	for i := int64(0); i < 3; i++ {
+		block, err := readArchiveBlock(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if block.Height != i || !bytes.Equal(block.Blockid, []byte{byte(i)}) {
+			t.Fatalf("unexpected block %v at %d", block, i)
+		}
+	}
+	if _, err := readArchiveBlock(r); err != io.EOF {
+		t.Fatalf("expect EOF, got %v", err)
+	}
+
+	// 截断在长度前缀中间或区块数据中间
+	for _, cut := range []int{2, len(data) - 1} {
+		r = bytes.NewReader(data[:cut])
+		var err error
+		for err == nil {
+			_, err = readArchiveBlock(r)
+		}
+		if err != ErrArchiveTruncated {
+			t.Fatalf("expect truncated at %d, got %v", cut, err)
+		}
+	}
+
+	// 损坏的长度前缀不会申请过大的内存
+	if _, err := readArchiveBlock(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); err == nil {
+		t.Fatal("expect oversized block error")
+	}
+}
+
+func TestWithChainLock(t *testing.T) {
+	m := &Miner{}
+	// 模拟step中正在裁剪账本
+	m.stepMutex.Lock()
+
+	done := make(chan struct{})
+	var truncating int32 = 1
+	go func() {
+		m.withChainLock(func() error {
+			if atomic.LoadInt32(&truncating) != 0 {
+				t.Error("import ran during truncate")
+			}
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("import should wait for the miner step")
+	case <-time.After(50 * time.Millisecond):
+	}
+	atomic.StoreInt32(&truncating, 0)
+	m.stepMutex.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("import not resumed after the miner step")
+	}
+}
+
+// importConsensus 按accept决定是否接受区块的矿工身份，确认区块时不做处理
+type importConsensus struct {
+	consensus.PluggableConsensusInterface
+	accept  bool
+	checked int
+}
+
+func (c *importConsensus) CheckMinerMatch(ctx xctx.XContext, block cctx.BlockInterface) (bool, error) {
+	c.checked++
+	return c.accept, nil
+}
+
+func (c *importConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error { return nil }
+func (c *importConsensus) SwitchConsensus(height int64) error                  { return nil }
+
+func TestImportBlocks(t *testing.T) {
+	l := newTestLedger(t)
+	_, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := []*lpb.InternalBlock{root}
+	for i := int64(1); i <= 6; i++ {
+		blocks = append(blocks, newTestBlock(t, l, blocks[i-1], addr, i))
+	}
+	// 第1个区块签名损坏，只有跳过区块校验时才能导入
+	blocks[1].Sign = []byte("forged")
+	archive := func(blocks ...*lpb.InternalBlock) *bytes.Buffer {
+		var buf bytes.Buffer
+		for _, block := range blocks {
+			if err := writeArchiveBlock(&buf, block); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return &buf
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	cfg := xconf.GetDefEngineConf()
+	cons := &importConsensus{accept: true}
+	m := &Miner{
+		log:            log,
+		clock:          realClock{},
+		verifiedBlocks: newVerifiedBlockCache("xuper", cfg.VerifiedBlockCacheSize),
+		clockSkew:      newClockSkewDetector(),
+		ctx: &common.ChainCtx{
+			BCName:    "xuper",
+			Ledger:    l,
+			State:     newTestState(t, l),
+			Consensus: cons,
+			EngCtx:    &common.EngineCtx{EngCfg: cfg},
+		},
+	}
+	ctx := &xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()}
+	tipHeight := func() int64 { return l.GetMeta().GetTrunkHeight() }
+
+	// 默认校验区块，签名损坏的区块被拒绝
+	if n, err := m.ImportBlocks(ctx, archive(blocks[1:4]...), false); !errors.Is(err, ErrValidation) || n != 0 {
+		t.Fatalf("expect validation error, got %d %v", n, err)
+	}
+	// trusted时跳过区块校验和共识的矿工身份检查
+	cons.accept = false
+	if n, err := m.ImportBlocks(ctx, archive(blocks[1:4]...), true); err != nil || n != 3 {
+		t.Fatalf("unexpected trusted import result: %d %v", n, err)
+	}
+	if cons.checked != 0 || tipHeight() != 3 {
+		t.Fatalf("trusted import should bypass consensus, checked %d, height %d", cons.checked, tipHeight())
+	}
+
+	// 账本中已存在的区块直接跳过
+	if n, err := m.ImportBlocks(ctx, archive(blocks[:5]...), true); err != nil || n != 1 || tipHeight() != 4 {
+		t.Fatalf("unexpected import result: %d %v, height %d", n, err, tipHeight())
+	}
+	// 与账本中已存在区块冲突
+	fork := newTestBlock(t, l, blocks[1], addr, 100)
+	n, err := m.ImportBlocks(ctx, archive(fork), true)
+	if err == nil || !strings.Contains(err.Error(), "conflicts with ledger") || n != 0 {
+		t.Fatalf("expect conflict error, got %d %v", n, err)
+	}
+
+	// 归档截断时已完整读取的区块仍然导入
+	data := archive(blocks[5:]...).Bytes()
+	data = append(data, archive(blocks[6]).Bytes()[:8]...)
+	if n, err := m.ImportBlocks(ctx, bytes.NewReader(data), true); !errors.Is(err, ErrArchiveTruncated) || n != 2 {
+		t.Fatalf("expect truncated after 2 blocks, got %d %v", n, err)
+	}
+	if tipHeight() != 6 {
+		t.Fatalf("complete blocks should be imported before truncation, height %d", tipHeight())
+	}
+}
+
+// stepConsensus 让矿工循环停在ProcessBeforeMiner，CalculateBlock时记录账本高度后结束本轮出块
+type stepConsensus struct {
+	*importConsensus
+	ledger      func() int64
+	entered     chan struct{}
+	release     chan struct{}
+	packHeight  int64
+	ledgerAtEnd int64
+}
+
+func (c *stepConsensus) CompeteMaster(height int64) (bool, bool, error) { return true, false, nil }
+
+func (c *stepConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []byte, error) {
+	close(c.entered)
+	<-c.release
+	return nil, nil, nil
+}
+
+func (c *stepConsensus) CalculateBlock(block cctx.BlockInterface) error {
+	c.packHeight = block.GetHeight()
+	c.ledgerAtEnd = c.ledger()
+	return errors.New("stop after pack")
+}
+
+func TestStepExcludesImportAndValidate(t *testing.T) {
+	l := newTestLedger(t)
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := []*lpb.InternalBlock{root}
+	for i := int64(1); i <= 2; i++ {
+		blocks = append(blocks, newTestBlock(t, l, blocks[i-1], addr, i))
+	}
+	var archive bytes.Buffer
+	for _, block := range blocks[1:] {
+		if err := writeArchiveBlock(&archive, block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	cfg := xconf.GetDefEngineConf()
+	tipHeight := func() int64 { return l.GetMeta().GetTrunkHeight() }
+	cons := &stepConsensus{
+		importConsensus: &importConsensus{accept: true},
+		ledger:          tipHeight,
+		entered:         make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	m := &Miner{
+		log:            log,
+		clock:          realClock{},
+		stats:          newMinerStats(),
+		status:         statusMining,
+		partition:      newPartitionDetector(cfg.PartitionTimeout),
+		verifiedBlocks: newVerifiedBlockCache("xuper", cfg.VerifiedBlockCacheSize),
+		clockSkew:      newClockSkewDetector(),
+		ctx: &common.ChainCtx{
+			BCName:    "xuper",
+			Ledger:    l,
+			State:     newTestState(t, l),
+			Consensus: cons,
+			Address:   newTestAddress(t, crypto),
+			EngCtx:    &common.EngineCtx{EngCfg: cfg, Net: &peerCountNet{count: 1}},
+		},
+	}
+
+	stepDone := make(chan error, 1)
+	go func() { stepDone <- m.step() }()
+	select {
+	case <-cons.entered:
+	case <-time.After(time.Second):
+		t.Fatal("miner step not started")
+	}
+
+	// 出块过程中同时导入归档区块和校验区块
+	importDone := make(chan error, 1)
+	go func() {
+		ctx := &xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()}
+		_, err := m.ImportBlocks(ctx, &archive, true)
+		importDone <- err
+	}()
+	validateDone := make(chan struct{})
+	go func() {
+		m.ValidateBlock(blocks[1])
+		close(validateDone)
+	}()
+
+	select {
+	case <-importDone:
+		t.Fatal("import should wait for the miner step")
+	case <-validateDone:
+		t.Fatal("validate should wait for the miner step")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if tipHeight() != 0 {
+		t.Fatalf("ledger changed during the miner step, height %d", tipHeight())
+	}
+
+	close(cons.release)
+	if err := <-stepDone; !errors.Is(err, ErrConsensus) {
+		t.Fatalf("expect step to stop at CalculateBlock, got %v", err)
+	}
+	if cons.packHeight != 1 || cons.ledgerAtEnd != 0 {
+		t.Fatalf("step packed height %d on ledger height %d, expect 1 on 0", cons.packHeight, cons.ledgerAtEnd)
+	}
+
+	select {
+	case <-validateDone:
+	case <-time.After(time.Second):
+		t.Fatal("validate not resumed after the miner step")
+	}
+	select {
+	case err := <-importDone:
+		if err != nil {
+			t.Fatalf("import after the miner step failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("import not resumed after the miner step")
+	}
+	if tipHeight() != 2 {
+		t.Fatalf("expect archive imported after the miner step, height %d", tipHeight())
+	}
+}
@@ -0,0 +1,36 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// ErrBlockIdMismatch 区块id与区块内容或已确认的区块id不一致
+var ErrBlockIdMismatch = errors.New("block id mismatch")
+
+// checkCalculatedBlock 共识CalculateBlock可能修改区块并重新计算区块id(如pow更新nonce)，
+// 确认前检查区块id与区块内容一致，保证账本、状态机和共识确认的是同一个区块
+func checkCalculatedBlock(block *lpb.InternalBlock) error {
+	id, err := ledger.MakeBlockID(block)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, block.GetBlockid()) {
+		return fmt.Errorf("%w: block carries %s, content hashes to %s", ErrBlockIdMismatch,
+			utils.F(block.GetBlockid()), utils.F(id))
+	}
+	return nil
+}
+
+// checkConfirmedId 检查确认过程中使用的区块id与确认开始时一致
+func checkConfirmedId(stage string, expect, got []byte) error {
+	if !bytes.Equal(expect, got) {
+		return fmt.Errorf("%w: %s got %s, expect %s", ErrBlockIdMismatch, stage, utils.F(got), utils.F(expect))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// rewriteConsensus 在CalculateBlock中改写区块，模拟pow更新nonce后重新计算区块id
+type rewriteConsensus struct {
+	consensus.PluggableConsensusInterface
+	rewrite func(block cctx.BlockInterface) error
+}
+
+func (c *rewriteConsensus) CalculateBlock(block cctx.BlockInterface) error {
+	return c.rewrite(block)
+}
+
+func TestCheckCalculatedBlock(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	newBlock := func() *lpb.InternalBlock {
+		block := &lpb.InternalBlock{
+			Version:    1,
+			Height:     10,
+			PreHash:    []byte("prehash"),
+			Proposer:   []byte("miner"),
+			Timestamp:  1600000000,
+			MerkleRoot: []byte("root"),
+		}
+		block.Blockid, _ = ledger.MakeBlockID(block)
+		return block
+	}
+	cases := []struct {
+		name    string
+		rewrite func(block cctx.BlockInterface) error
+		ok      bool
+	}{
+		{"unchanged", func(block cctx.BlockInterface) error { return nil }, true},
+		{"recompute", func(block cctx.BlockInterface) error {
+			agent := block.(*state.BlockAgent)
+			if err := agent.SetItem("nonce", int32(7)); err != nil {
+				return err
+			}
+			_, err := agent.MakeBlockId()
+			return err
+		}, true},
+		{"stale id", func(block cctx.BlockInterface) error {
+			return block.(*state.BlockAgent).SetItem("nonce", int32(7))
+		}, false},
+		{"forged id", func(block cctx.BlockInterface) error {
+			return block.(*state.BlockAgent).SetItem("blockid", []byte("forged"))
+		}, false},
+	}
+	for _, c := range cases {
+		m := &Miner{
+			ctx: &common.ChainCtx{Consensus: &rewriteConsensus{rewrite: c.rewrite}},
+			log: log,
+		}
+		block := newBlock()
+		if err := m.calculateBlock(state.NewBlockAgent(block)); err != nil {
+			t.Fatalf("%s: calculate block error %v", c.name, err)
+		}
+		err := checkCalculatedBlock(block)
+		if c.ok && err != nil {
+			t.Fatalf("%s: expect consistent block id, got %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, ErrBlockIdMismatch) {
+			t.Fatalf("%s: expect ErrBlockIdMismatch, got %v", c.name, err)
+		}
+	}
+
+	if err := checkConfirmedId("state tip", []byte("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkConfirmedId("state tip", []byte("a"), []byte("b")); !errors.Is(err, ErrBlockIdMismatch) {
+		t.Fatalf("expect ErrBlockIdMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package miner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/hash"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 签名检查点
+// 开启后节点每隔CheckpointInterval个区块，对该高度的主干区块签名生成检查点，本地和GET_CHECKPOINT消息均可查询。
+// 其他节点取得检查点后需用VerifyCheckpoint确认签名节点属于配置的CheckpointAuthorities，才可在快速同步时信任该区块
+
+var (
+	ErrCheckpointUnauthorized = errors.New("checkpoint signer not authorized")
+	ErrCheckpointSignInvalid  = errors.New("checkpoint sign invalid")
+)
+
+// checkpointStore 保存本节点最新生成的检查点
+type checkpointStore struct {
+	mutex  sync.RWMutex
+	latest *xpb.Checkpoint
+}
+
+func (s *checkpointStore) get() *xpb.Checkpoint {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.latest
+}
+
+func (s *checkpointStore) set(cp *xpb.Checkpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.latest = cp
+}
+
+// checkpointHeight 返回tipHeight及以下最近的检查点高度，interval<=0或还没有到第一个检查点时返回0
+func checkpointHeight(tipHeight, interval int64) int64 {
+	if interval <= 0 || tipHeight < interval {
+		return 0
+	}
+	return tipHeight / interval * interval
+}
+
+// checkpointDigest 检查点的签名摘要，覆盖链名、高度和区块id
+func checkpointDigest(cp *xpb.Checkpoint) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int32(len(cp.Bcname)))
+	buf.WriteString(cp.Bcname)
+	binary.Write(&buf, binary.BigEndian, cp.Height)
+	buf.Write(cp.Blockid)
+	return hash.DoubleSha256(buf.Bytes())
+}
+
+// signCheckpoint 使用节点密钥对检查点签名
+func signCheckpoint(crypto cryptoBase.CryptoClient, addr *xaddress.Address,
+	bcName string, height int64, blockid []byte) (*xpb.Checkpoint, error) {
+	cp := &xpb.Checkpoint{
+		Bcname:    bcName,
+		Height:    height,
+		Blockid:   blockid,
+		Address:   addr.Address,
+		PublicKey: addr.PublicKeyStr,
+	}
+	sign, err := crypto.SignECDSA(addr.PrivateKey, checkpointDigest(cp))
+	if err != nil {
+		return nil, err
+	}
+	cp.Sign = sign
+	return cp, nil
+}
+
+// VerifyCheckpoint 校验检查点由授权节点签名且签名有效
+func VerifyCheckpoint(crypto cryptoBase.CryptoClient, cp *xpb.Checkpoint, authorities []string) error {
+	if cp == nil || len(cp.Blockid) == 0 || len(cp.Sign) == 0 {
+		return ErrCheckpointSignInvalid
+	}
+	authorized := false
+	for _, addr := range authorities {
+		if addr == cp.Address {
+			authorized = true
+			break
+		}
+	}
+	if !authorized {
+		return ErrCheckpointUnauthorized
+	}
+
+	pub, err := crypto.GetEcdsaPublicKeyFromJsonStr(cp.PublicKey)
+	if err != nil {
+		return ErrCheckpointSignInvalid
+	}
+	if ok, _ := crypto.VerifyAddressUsingPublicKey(cp.Address, pub); !ok {
+		return ErrCheckpointSignInvalid
+	}
+	if ok, err := crypto.VerifyECDSA(pub, cp.Sign, checkpointDigest(cp)); err != nil || !ok {
+		return ErrCheckpointSignInvalid
+	}
+	return nil
+}
+
+// updateCheckpoint 账本越过新的检查点高度时对该高度的区块签名
+// 已签名的检查点区块因分叉切换不再位于主干时，按当前主干重新签名
+func (t *Miner) updateCheckpoint() {
+	height := checkpointHeight(t.ctx.Ledger.GetMeta().TrunkHeight, t.ctx.EngCtx.EngCfg.CheckpointInterval)
+	if height == 0 {
+		return
+	}
+	if latest := t.checkpoint.get(); latest != nil && latest.Height >= height {
+		if t.onTrunk(latest) {
+			return
+		}
+		t.log.Warn("checkpoint block left trunk, resign", "height", latest.Height, "blockId", utils.F(latest.Blockid))
+		t.checkpoint.set(nil)
+	}
+
+	block, err := t.ctx.Ledger.QueryBlockByHeight(height)
+	if err != nil {
+		t.log.Warn("query checkpoint block failed", "height", height, "err", err)
+		return
+	}
+	cp, err := signCheckpoint(t.ctx.Crypto, t.ctx.Address, t.ctx.BCName, height, block.Blockid)
+	if err != nil {
+		t.log.Warn("sign checkpoint failed", "height", height, "err", err)
+		return
+	}
+	t.checkpoint.set(cp)
+	t.log.Info("checkpoint signed", "height", height, "blockId", utils.F(block.Blockid))
+}
+
+// onTrunk 检查点区块是否仍是主干上对应高度的区块
+func (t *Miner) onTrunk(cp *xpb.Checkpoint) bool {
+	block, err := t.ctx.Ledger.QueryBlockHeaderByHeight(cp.Height)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(block.GetBlockid(), cp.Blockid)
+}
+
+// LatestCheckpoint 返回本节点最新签名的检查点
+func (t *Miner) LatestCheckpoint() (*xpb.Checkpoint, error) {
+	cp := t.checkpoint.get()
+	if cp == nil {
+		return nil, common.ErrCheckpointNotExist
+	}
+	return cp, nil
+}
+
+// FetchCheckpoint 向邻居节点查询检查点，返回通过校验的最高检查点
+func (t *Miner) FetchCheckpoint() (*xpb.Checkpoint, error) {
+	authorities := t.ctx.EngCtx.EngCfg.CheckpointAuthorities
+	msg := p2p.NewMessage(protos.XuperMessage_GET_CHECKPOINT, nil, p2p.WithBCName(t.ctx.BCName))
+	responses, err := t.ctx.EngCtx.Net.SendMessageWithResponse(t.ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *xpb.Checkpoint
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			continue
+		}
+		var cp xpb.Checkpoint
+		if err := p2p.Unmarshal(response, &cp); err != nil {
+			t.log.Warn("unmarshal checkpoint error", "from", response.GetHeader().GetFrom(), "err", err)
+			continue
+		}
+		if cp.Bcname != t.ctx.BCName {
+			continue
+		}
+		if err := VerifyCheckpoint(t.ctx.Crypto, &cp, authorities); err != nil {
+			t.log.Warn("verify checkpoint failed", "from", response.GetHeader().GetFrom(),
+				"address", cp.Address, "height", cp.Height, "err", err)
+			continue
+		}
+		if best == nil || cp.Height > best.Height {
+			best = &cp
+		}
+	}
+	if best == nil {
+		return nil, common.ErrCheckpointNotExist
+	}
+	return best, nil
+}
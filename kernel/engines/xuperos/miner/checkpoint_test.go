@@ -0,0 +1,114 @@
+package miner
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestCheckpointHeight(t *testing.T) {
+	cases := []struct {
+		tip, interval, expect int64
+	}{
+		{100, 0, 0},
+		{5, 10, 0},
+		{10, 10, 10},
+		{29, 10, 20},
+	}
+	for _, c := range cases {
+		if h := checkpointHeight(c.tip, c.interval); h != c.expect {
+			t.Fatalf("tip %d interval %d expect %d, got %d", c.tip, c.interval, c.expect, h)
+		}
+	}
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	cp, err := signCheckpoint(crypto, addr, "xuper", 100, []byte("blockid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyCheckpoint(crypto, cp, []string{addr.Address}); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyCheckpoint(crypto, cp, []string{"other"}); err != ErrCheckpointUnauthorized {
+		t.Fatalf("expect unauthorized, got %v", err)
+	}
+
+	cp.Height = 200
+	if err := VerifyCheckpoint(crypto, cp, []string{addr.Address}); err != ErrCheckpointSignInvalid {
+		t.Fatalf("expect sign invalid, got %v", err)
+	}
+	if err := VerifyCheckpoint(crypto, nil, []string{addr.Address}); err != ErrCheckpointSignInvalid {
+		t.Fatalf("expect sign invalid, got %v", err)
+	}
+}
+
+func TestUpdateCheckpointReorg(t *testing.T) {
+	l := newTestLedger(t)
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	log, _ := logs.NewLogger("", "miner")
+	m := &Miner{
+		log:        log,
+		checkpoint: &checkpointStore{},
+		ctx: &common.ChainCtx{
+			BCName:  "xuper",
+			Ledger:  l,
+			Crypto:  crypto,
+			Address: addr,
+			EngCtx:  &common.EngineCtx{EngCfg: &engconf.EngineConf{CheckpointInterval: 2}},
+		},
+	}
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirm := func(prev *lpb.InternalBlock, n int, timestamp int64) []*lpb.InternalBlock {
+		var blocks []*lpb.InternalBlock
+		for i := 0; i < n; i++ {
+			block := newTestBlock(t, l, prev, addr, timestamp+int64(i))
+			if status := l.ConfirmBlock(block, false); !status.Succ {
+				t.Fatalf("confirm block at height %d failed", block.Height)
+			}
+			blocks = append(blocks, block)
+			prev = block
+		}
+		return blocks
+	}
+
+	trunk := confirm(root, 2, 1)
+	m.updateCheckpoint()
+	cp, err := m.LatestCheckpoint()
+	if err != nil || cp.Height != 2 || !bytes.Equal(cp.Blockid, trunk[1].Blockid) {
+		t.Fatalf("expect checkpoint on height 2, got %v %v", cp, err)
+	}
+
+	// 更长的分叉成为主干后，原检查点区块不再位于主干，按新主干重新签名
+	fork := confirm(root, 3, 100)
+	if tip := l.GetMeta().GetTipBlockid(); !bytes.Equal(tip, fork[2].Blockid) {
+		t.Fatal("fork should become trunk")
+	}
+	m.updateCheckpoint()
+	cp, err = m.LatestCheckpoint()
+	if err != nil || cp.Height != 2 || !bytes.Equal(cp.Blockid, fork[1].Blockid) {
+		t.Fatalf("expect checkpoint resigned on fork, got %v %v", cp, err)
+	}
+	if err := VerifyCheckpoint(crypto, cp, []string{addr.Address}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,26 @@
+package miner
+
+import (
+	"time"
+)
+
+// Clock 矿工使用的时间源，出块时间戳和共识挖矿前处理都从这里取时间，
+// 测试时可替换为可控的时间以复现周期切换、空块间隔等与时间相关的行为
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock 默认时间源，直接使用系统时间
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock 替换矿工的时间源，需在Start之前调用，传入nil时恢复为系统时间
+func (t *Miner) SetClock(clock Clock) {
+	if clock == nil {
+		clock = realClock{}
+	}
+	t.clock = clock
+}
@@ -0,0 +1,105 @@
+package miner
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrClockSkew 本地时钟与网络时间偏差过大
+var ErrClockSkew = errors.New("local clock skewed from network time")
+
+const (
+	// clockSkewWindow 参与估算时钟偏差的最近区块数
+	clockSkewWindow = 16
+	// clockSkewMinProposers 样本来自的出块节点数不足时不做判断，避免个别节点的时钟造成误判
+	clockSkewMinProposers = 3
+	// clockSkewSampleTTL 样本有效期，长时间没有收到区块时旧样本不再可信
+	clockSkewSampleTTL = 10 * time.Minute
+)
+
+type clockSample struct {
+	// 出块节点地址
+	proposer string
+	// 区块时间戳与本地收到时间之差
+	offset time.Duration
+	recvAt time.Time
+}
+
+// clockSkewDetector 根据最近收到的有效区块时间戳估算本地时钟与网络时间的偏差。
+// 区块时间戳总是早于本地收到的时间，差值包含传播耗时，追块时旧区块的差值更大，
+// 因此与NTP选取最小延迟样本类似，对每个出块节点取差值最大(最新鲜)的样本作为该节点的偏差估计，
+// 再取各出块节点估计的中位数，单个时钟错误的出块节点不会影响结果：
+// 结果为正表示本地时钟快于网络，为负表示本地时钟慢于网络
+type clockSkewDetector struct {
+	mutex   sync.Mutex
+	samples []clockSample
+	next    int
+}
+
+func newClockSkewDetector() *clockSkewDetector {
+	return &clockSkewDetector{
+		samples: make([]clockSample, 0, clockSkewWindow),
+	}
+}
+
+// observe 记录一个区块时间戳样本
+func (d *clockSkewDetector) observe(proposer string, blockTime, now time.Time) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	sample := clockSample{proposer: proposer, offset: blockTime.Sub(now), recvAt: now}
+	if len(d.samples) < clockSkewWindow {
+		d.samples = append(d.samples, sample)
+		return
+	}
+	d.samples[d.next] = sample
+	d.next = (d.next + 1) % clockSkewWindow
+}
+
+// skew 返回估算的本地时钟偏差，有效样本来自的出块节点不足时ok为false
+func (d *clockSkewDetector) skew(now time.Time) (skew time.Duration, ok bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	freshest := make(map[string]time.Duration)
+	for _, sample := range d.samples {
+		if now.Sub(sample.recvAt) > clockSkewSampleTTL {
+			continue
+		}
+		if offset, has := freshest[sample.proposer]; !has || sample.offset > offset {
+			freshest[sample.proposer] = sample.offset
+		}
+	}
+	if len(freshest) < clockSkewMinProposers {
+		return 0, false
+	}
+	offsets := make([]time.Duration, 0, len(freshest))
+	for _, offset := range freshest {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	mid := len(offsets) / 2
+	median := offsets[mid]
+	if len(offsets)%2 == 0 {
+		median = (offsets[mid-1] + offsets[mid]) / 2
+	}
+	return -median, true
+}
+
+// checkClockSkew 出块前检查本地时钟偏差，超过阈值时告警，按配置返回是否需要停止出块
+func (t *Miner) checkClockSkew(height int64) bool {
+	cfg := t.ctx.EngCtx.EngCfg
+	if !cfg.ClockSkewCheck || cfg.ClockSkewThreshold <= 0 {
+		return false
+	}
+
+	skew, ok := t.clockSkew.skew(t.clock.Now())
+	if !ok || (skew <= cfg.ClockSkewThreshold && skew >= -cfg.ClockSkewThreshold) {
+		return false
+	}
+	t.warnThrottled(t.log, "local clock skew exceeds threshold, block timestamps may be rejected by peers, please check system time",
+		ErrClockSkew, "height", height, "skew", skew, "threshold", cfg.ClockSkewThreshold, "refuseMining", cfg.ClockSkewRefuseMining)
+	return cfg.ClockSkewRefuseMining
+}
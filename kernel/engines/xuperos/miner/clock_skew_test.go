@@ -0,0 +1,92 @@
+package miner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestClockSkewDetector(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	d := newClockSkewDetector()
+
+	// 本地时钟快10秒，追块时的旧区块不影响估计
+	d.observe("a", now.Add(-time.Hour), now)
+	d.observe("a", now.Add(-10*time.Second-200*time.Millisecond), now)
+	d.observe("b", now.Add(-10*time.Second-100*time.Millisecond), now)
+	if _, ok := d.skew(now); ok {
+		t.Fatal("expect no estimate with too few proposers")
+	}
+	d.observe("c", now.Add(-10*time.Second-300*time.Millisecond), now)
+	skew, ok := d.skew(now)
+	if !ok || skew != 10*time.Second+200*time.Millisecond {
+		t.Fatalf("unexpected skew %v %v", skew, ok)
+	}
+
+	// 本地时钟慢5秒，区块时间戳领先本地
+	for i := 0; i < clockSkewWindow; i++ {
+		d.observe(fmt.Sprint(i%3), now.Add(5*time.Second-time.Duration(i)*time.Millisecond), now)
+	}
+	if skew, ok = d.skew(now); !ok || skew != -5*time.Second+time.Millisecond {
+		t.Fatalf("unexpected skew %v %v", skew, ok)
+	}
+
+	// 样本过期后不再判断
+	if _, ok = d.skew(now.Add(clockSkewSampleTTL + time.Second)); ok {
+		t.Fatal("expect stale samples ignored")
+	}
+}
+
+func TestClockSkewOutlier(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	d := newClockSkewDetector()
+
+	// 单个出块节点时钟快1小时，不影响估计
+	d.observe("a", now.Add(-100*time.Millisecond), now)
+	d.observe("b", now.Add(-200*time.Millisecond), now)
+	d.observe("c", now.Add(-300*time.Millisecond), now)
+	d.observe("bad", now.Add(time.Hour), now)
+	d.observe("bad", now.Add(time.Hour+time.Second), now)
+	skew, ok := d.skew(now)
+	if !ok || skew > 300*time.Millisecond || skew < 0 {
+		t.Fatalf("outlier proposer should not affect skew, got %v %v", skew, ok)
+	}
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	now := time.Unix(1600000000, 0)
+	cfg := &engconf.EngineConf{ClockSkewThreshold: 3 * time.Second}
+	m := &Miner{
+		ctx:          &common.ChainCtx{EngCtx: &common.EngineCtx{EngCfg: cfg}},
+		log:          log,
+		clock:        &fixedClock{now: now},
+		warnThrottle: newWarnThrottle(0),
+		clockSkew:    newClockSkewDetector(),
+	}
+	for i := 0; i < clockSkewMinProposers; i++ {
+		m.clockSkew.observe(fmt.Sprint(i), now.Add(-5*time.Second), now)
+	}
+
+	if m.checkClockSkew(10) {
+		t.Fatal("check disabled, should not refuse mining")
+	}
+	cfg.ClockSkewCheck = true
+	if m.checkClockSkew(10) {
+		t.Fatal("only warn without refuseMining")
+	}
+	cfg.ClockSkewRefuseMining = true
+	if !m.checkClockSkew(10) {
+		t.Fatal("expect refuse mining when skew exceeds threshold")
+	}
+	cfg.ClockSkewThreshold = 6 * time.Second
+	if m.checkClockSkew(10) {
+		t.Fatal("skew within threshold should not refuse mining")
+	}
+}
@@ -0,0 +1,93 @@
+package miner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestSetClock(t *testing.T) {
+	m := &Miner{clock: realClock{}}
+	fixed := &fixedClock{now: time.Unix(1600000000, 0)}
+	m.SetClock(fixed)
+	if !m.clock.Now().Equal(fixed.now) {
+		t.Fatalf("expect %v, got %v", fixed.now, m.clock.Now())
+	}
+	fixed.now = fixed.now.Add(3 * time.Second)
+	if m.clock.Now().Unix() != 1600000003 {
+		t.Fatalf("clock not advanced, got %v", m.clock.Now())
+	}
+
+	m.SetClock(nil)
+	if _, ok := m.clock.(realClock); !ok {
+		t.Fatal("expect real clock after reset")
+	}
+}
+
+// clockConsensus 记录矿工循环传给共识的时间戳，CalculateBlock返回错误以便在打包后结束本轮出块
+type clockConsensus struct {
+	consensus.PluggableConsensusInterface
+	beforeMinerTimestamp int64
+	blockTimestamp       int64
+}
+
+func (c *clockConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []byte, error) {
+	c.beforeMinerTimestamp = timestamp
+	return nil, nil, nil
+}
+
+func (c *clockConsensus) CalculateBlock(block cctx.BlockInterface) error {
+	c.blockTimestamp = block.GetTimestamp()
+	return errors.New("stop after pack")
+}
+
+func TestMiningUsesClock(t *testing.T) {
+	l := newTestLedger(t)
+	s := newTestState(t, l)
+	crypto, _, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, _ := logs.NewLogger("", "miner")
+	cons := &clockConsensus{}
+	fixed := &fixedClock{now: time.Unix(1600000000, 0)}
+	m := &Miner{
+		log:   log,
+		clock: fixed,
+		stats: newMinerStats(),
+		ctx: &common.ChainCtx{
+			BCName:    "xuper",
+			Ledger:    l,
+			State:     s,
+			Consensus: cons,
+			Address:   newTestAddress(t, crypto),
+			EngCtx:    &common.EngineCtx{EngCfg: xconf.GetDefEngineConf()},
+		},
+	}
+
+	err = m.mining(m.newRoundContext())
+	if !errors.Is(err, ErrConsensus) {
+		t.Fatalf("expect consensus error from CalculateBlock, got %v", err)
+	}
+	if cons.beforeMinerTimestamp != fixed.now.UnixNano() {
+		t.Fatalf("ProcessBeforeMiner got timestamp %d, expect %d", cons.beforeMinerTimestamp, fixed.now.UnixNano())
+	}
+	if cons.blockTimestamp != fixed.now.UnixNano() {
+		t.Fatalf("formatted block got timestamp %d, expect %d", cons.blockTimestamp, fixed.now.UnixNano())
+	}
+}
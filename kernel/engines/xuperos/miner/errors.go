@@ -0,0 +1,53 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 矿工错误的分类，调用方通过errors.Is判断错误属于哪一类，
+// 具体原因仍然可以通过errors.Is判断原有的错误，如ErrHashMissMatch、common.ErrGenesisBlockDiff
+var (
+	// ErrSync 向其他节点同步区块失败
+	ErrSync = errors.New("miner sync failed")
+	// ErrValidation 区块未通过校验被拒绝
+	ErrValidation = errors.New("block validation rejected")
+	// ErrConsensus 共识处理失败或共识拒绝了区块
+	ErrConsensus = errors.New("consensus failed")
+	// ErrStateWalk 状态机回滚或前进失败
+	ErrStateWalk = errors.New("state walk failed")
+	// ErrLedgerConfirm 账本写入区块失败
+	ErrLedgerConfirm = errors.New("ledger confirm block failed")
+)
+
+// Error 矿工返回的分类错误，Kind为上面的错误分类，Op为出错的步骤，Err为具体原因
+type Error struct {
+	Kind error
+	Op   string
+	Err  error
+}
+
+// newError 按分类包装错误，err为nil时使用分类本身作为原因
+func newError(kind error, op string, err error) *Error {
+	if err == nil {
+		err = kind
+	}
+	return &Error{Kind: kind, Op: op, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == e.Kind {
+		return fmt.Sprintf("%s: %v", e.Op, e.Kind)
+	}
+	return fmt.Sprintf("%s: %v: %v", e.Op, e.Kind, e.Err)
+}
+
+// Unwrap 返回具体原因，errors.Is可以继续匹配原有的错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 匹配错误分类
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
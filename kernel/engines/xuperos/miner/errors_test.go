@@ -0,0 +1,40 @@
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+)
+
+func TestMinerErrorKind(t *testing.T) {
+	rejected := newError(ErrValidation, "confirmBlocks", ErrHashMissMatch)
+	err := newError(ErrSync, "syncBlockWithHeight", rejected)
+
+	for _, target := range []error{ErrSync, ErrValidation, ErrHashMissMatch} {
+		if !errors.Is(err, target) {
+			t.Fatalf("%v should match %v", err, target)
+		}
+	}
+	for _, target := range []error{ErrConsensus, ErrStateWalk, ErrLedgerConfirm} {
+		if errors.Is(err, target) {
+			t.Fatalf("%v should not match %v", err, target)
+		}
+	}
+
+	var merr *Error
+	if !errors.As(err, &merr) || merr.Op != "syncBlockWithHeight" {
+		t.Fatalf("unexpected miner error %v", merr)
+	}
+	if err.Error() != "syncBlockWithHeight: miner sync failed: confirmBlocks: block validation rejected: hash miss match" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+
+	// 包装原有的sentinel后仍然可以按原错误判断
+	if !errors.Is(newError(ErrSync, "syncBlockWithHeight", common.ErrGenesisBlockDiff), common.ErrGenesisBlockDiff) {
+		t.Fatal("wrapped genesis diff should still match")
+	}
+	if got := newError(ErrConsensus, "CheckMinerMatch", nil); got.Error() != "CheckMinerMatch: consensus failed" || !errors.Is(got, ErrConsensus) {
+		t.Fatalf("unexpected error without cause %q", got.Error())
+	}
+}
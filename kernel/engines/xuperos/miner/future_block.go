@@ -0,0 +1,75 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// ErrFutureBlock 区块时间超过本地时间加上允许的漂移
+var ErrFutureBlock = errors.New("block timestamp too far in the future")
+
+// futureBlockAhead 返回区块时间超出本地时间加drift的部分，未超出时返回0
+func futureBlockAhead(block *lpb.InternalBlock, now time.Time, drift time.Duration) time.Duration {
+	ahead := time.Unix(0, block.GetTimestamp()).Sub(now) - drift
+	if ahead < 0 {
+		return 0
+	}
+	return ahead
+}
+
+// checkFutureBlock 拒绝时间戳超前本地时间过多的区块，避免时钟偏快的矿工提前出块。
+// 超出部分不大于FutureBlockHold的区块由holdFutureBlocks在确认前等待，这里不再等待
+func (t *Miner) checkFutureBlock(block *lpb.InternalBlock) error {
+	cfg := t.ctx.EngCtx.EngCfg
+	if cfg.MaxFutureBlockDrift <= 0 {
+		return nil
+	}
+
+	ahead := futureBlockAhead(block, t.clock.Now(), cfg.MaxFutureBlockDrift)
+	if ahead > cfg.FutureBlockHold {
+		return fmt.Errorf("%w: block %s at height %d is %s beyond max drift %s", ErrFutureBlock,
+			utils.F(block.GetBlockid()), block.GetHeight(), ahead, cfg.MaxFutureBlockDrift)
+	}
+	return nil
+}
+
+// holdFutureBlocks 等待一批区块中可以等待的区块进入允许范围，整批只等待一次，
+// 总时长不超过FutureBlockHold。超出hold的区块不等待，由checkFutureBlock拒绝
+func (t *Miner) holdFutureBlocks(ctx xctx.XContext, blocks []*lpb.InternalBlock) error {
+	cfg := t.ctx.EngCtx.EngCfg
+	if cfg.MaxFutureBlockDrift <= 0 || cfg.FutureBlockHold <= 0 {
+		return nil
+	}
+
+	var hold time.Duration
+	var held *lpb.InternalBlock
+	now := t.clock.Now()
+	for _, block := range blocks {
+		ahead := futureBlockAhead(block, now, cfg.MaxFutureBlockDrift)
+		if ahead > cfg.FutureBlockHold {
+			break
+		}
+		if ahead > hold {
+			hold, held = ahead, block
+		}
+	}
+	if hold == 0 {
+		return nil
+	}
+
+	ctx.GetLog().Info("hold future block until its timestamp is within max drift", "blockId", utils.F(held.GetBlockid()),
+		"height", held.GetHeight(), "hold", hold)
+	timer := time.NewTimer(hold)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ErrSyncRedirected
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestCheckFutureBlock(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	cfg := &engconf.EngineConf{}
+	m := &Miner{
+		ctx:   &common.ChainCtx{EngCtx: &common.EngineCtx{EngCfg: cfg}},
+		clock: &fixedClock{now: now},
+	}
+	blockAt := func(d time.Duration) *lpb.InternalBlock {
+		return &lpb.InternalBlock{Blockid: []byte("future"), Height: 10, Timestamp: now.Add(d).UnixNano()}
+	}
+
+	// 未配置时不检查
+	if err := m.checkFutureBlock(blockAt(time.Hour)); err != nil {
+		t.Fatalf("check disabled, got %v", err)
+	}
+
+	cfg.MaxFutureBlockDrift = 5 * time.Second
+	for _, d := range []time.Duration{-time.Minute, 0, 5 * time.Second} {
+		if err := m.checkFutureBlock(blockAt(d)); err != nil {
+			t.Fatalf("block %s ahead is within drift, got %v", d, err)
+		}
+	}
+	if err := m.checkFutureBlock(blockAt(5*time.Second + time.Millisecond)); !errors.Is(err, ErrFutureBlock) {
+		t.Fatalf("expect ErrFutureBlock just beyond drift, got %v", err)
+	}
+
+	// 超出部分不大于hold时由holdFutureBlocks等待，检查本身不等待
+	cfg.FutureBlockHold = 50 * time.Millisecond
+	begin := time.Now()
+	if err := m.checkFutureBlock(blockAt(5*time.Second + 20*time.Millisecond)); err != nil {
+		t.Fatalf("block within hold should be accepted, got %v", err)
+	}
+	if waited := time.Since(begin); waited >= 20*time.Millisecond {
+		t.Fatalf("check should not wait, waited %s", waited)
+	}
+	if err := m.checkFutureBlock(blockAt(5*time.Second + time.Second)); !errors.Is(err, ErrFutureBlock) {
+		t.Fatalf("expect ErrFutureBlock beyond hold, got %v", err)
+	}
+}
+
+func TestHoldFutureBlocks(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	ctx := &xctx.BaseCtx{XLog: log}
+	now := time.Unix(1600000000, 0)
+	cfg := &engconf.EngineConf{MaxFutureBlockDrift: 5 * time.Second, FutureBlockHold: 100 * time.Millisecond}
+	m := &Miner{
+		ctx:   &common.ChainCtx{EngCtx: &common.EngineCtx{EngCfg: cfg}},
+		clock: &fixedClock{now: now},
+	}
+	blockAt := func(d time.Duration) *lpb.InternalBlock {
+		return &lpb.InternalBlock{Blockid: []byte("future"), Height: 10, Timestamp: now.Add(cfg.MaxFutureBlockDrift + d).UnixNano()}
+	}
+
+	// 整批只等待一次，等待时长为可等待区块中超出最多的部分
+	blocks := []*lpb.InternalBlock{blockAt(30 * time.Millisecond), blockAt(40 * time.Millisecond), blockAt(60 * time.Millisecond)}
+	begin := time.Now()
+	if err := m.holdFutureBlocks(ctx, blocks); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(begin); waited < 60*time.Millisecond || waited >= 130*time.Millisecond {
+		t.Fatalf("batch should be held once for 60ms, waited %s", waited)
+	}
+
+	// 超出hold的区块不等待，之后的区块也不再计入
+	begin = time.Now()
+	if err := m.holdFutureBlocks(ctx, []*lpb.InternalBlock{blockAt(time.Second), blockAt(time.Second + 50*time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(begin); waited >= 50*time.Millisecond {
+		t.Fatalf("block beyond hold should not be waited for, waited %s", waited)
+	}
+
+	// 同步被取消时停止等待
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg.FutureBlockHold = time.Hour
+	if err := m.holdFutureBlocks(xctx.WithNewContext(ctx, cctx), []*lpb.InternalBlock{blockAt(time.Minute)}); err != ErrSyncRedirected {
+		t.Fatalf("expect ErrSyncRedirected after cancel, got %v", err)
+	}
+}
+
+func TestValidateBlockHoldOutsideLock(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	now := time.Now()
+	cfg := &engconf.EngineConf{MaxFutureBlockDrift: time.Second, FutureBlockHold: time.Second}
+	m := &Miner{
+		log:   log,
+		ctx:   &common.ChainCtx{Ledger: newTestLedger(t), EngCtx: &common.EngineCtx{EngCfg: cfg}},
+		clock: realClock{},
+	}
+	// 区块高度不连续，等待结束后在锁内被拒绝
+	block := &lpb.InternalBlock{Blockid: []byte("future"), Height: 10, TxCount: 1, MerkleTree: [][]byte{[]byte("tx")},
+		Timestamp: now.Add(cfg.MaxFutureBlockDrift + 300*time.Millisecond).UnixNano()}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.ValidateBlock(block)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	begin := time.Now()
+	m.withChainLock(func() error { return nil })
+	if waited := time.Since(begin); waited >= 100*time.Millisecond {
+		t.Fatalf("holding a future block should not block the chain lock, waited %s", waited)
+	}
+	if err := <-done; !errors.Is(err, ErrValidation) {
+		t.Fatalf("expect discontinuous block rejected, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+)
+
+// ErrGasScheduleMismatch 对端节点的xvm计价表与本节点不一致
+var ErrGasScheduleMismatch = errors.New("xvm gas schedule mismatch")
+
+// checkGasSchedule 比较对端节点宣告的xvm计价表摘要与本节点是否一致，不一致时告警并返回true。
+// 计价表决定合约消耗的gas，不一致的节点执行同一交易会得到不同的结果，最终在校验区块时分叉；
+// 任一方未宣告（未开启xvm或老版本节点）时无法判断，按一致处理
+func (t *Miner) checkGasSchedule(ctx xctx.XContext, peer string) bool {
+	if t.ctx.EngCtx.Net == nil {
+		return false
+	}
+	local := contract.GasScheduleCapability(p2p.LocalCapabilities())
+	remote := contract.GasScheduleCapability(t.ctx.EngCtx.Net.PeerCapabilities(peer))
+	if local == "" || remote == "" || local == remote {
+		return false
+	}
+	t.warnThrottled(ctx.GetLog(), "peer runs a different xvm gas schedule, contract execution may diverge",
+		fmt.Errorf("%w: peer %s", ErrGasScheduleMismatch, peer), "local", local, "remote", remote)
+	return true
+}
@@ -0,0 +1,37 @@
+package miner
+
+import (
+	"testing"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+)
+
+func TestCheckGasSchedule(t *testing.T) {
+	mock := &chainStatusNet{}
+	m := newResyncMiner(t, mock)
+	ctx := &xctx.BaseCtx{XLog: m.log}
+
+	local := &contract.XVMGasSchedule{SyscallCost: 10}
+	other := &contract.XVMGasSchedule{SyscallCost: 20}
+
+	// 对端未宣告计价表时无法判断
+	if m.checkGasSchedule(ctx, "peer") {
+		t.Fatal("peer without gas schedule should not be reported")
+	}
+	// 本节点未宣告计价表时无法判断
+	mock.caps = []string{"other", other.Capability()}
+	if contract.GasScheduleCapability(p2p.LocalCapabilities()) == "" && m.checkGasSchedule(ctx, "peer") {
+		t.Fatal("local node without gas schedule should not report mismatch")
+	}
+
+	p2p.RegisterCapability(local.Capability())
+	if !m.checkGasSchedule(ctx, "peer") {
+		t.Fatal("expect mismatch reported")
+	}
+	mock.caps = []string{local.Capability()}
+	if m.checkGasSchedule(ctx, "peer") {
+		t.Fatal("same gas schedule should not be reported")
+	}
+}
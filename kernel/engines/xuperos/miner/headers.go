@@ -0,0 +1,104 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// ErrMerkleRootMismatch 区块头中的merkle树与merkle root不一致
+var ErrMerkleRootMismatch = errors.New("merkle tree not match merkle root")
+
+// checkHeaderLinks 检查区块头链与本地区块prev相连且前后相连，并检查每个区块头自身的一致性，
+// 区块头不含交易内容，由merkle树的叶子节点重新计算整棵树，需与区块头中的merkle树和merkle root一致，
+// 之后按需获取的交易内容通过merkle root校验
+func checkHeaderLinks(prev *lpb.InternalBlock, headers []*lpb.InternalBlock) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	if prev == nil || !bytes.Equal(headers[0].GetPreHash(), prev.GetBlockid()) {
+		return fmt.Errorf("%w: header %s at height %d not link to local block %s",
+			ErrBlockChainGap, utils.F(headers[0].GetBlockid()), headers[0].GetHeight(), utils.F(prev.GetBlockid()))
+	}
+	if err := checkPeerBlocks(headers, prev.GetHeight()+1); err != nil {
+		return err
+	}
+	for _, header := range headers {
+		if err := checkBlockHeader(header); err != nil {
+			return err
+		}
+		if err := checkMerkleTree(header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMerkleTree 由区块头merkle树的叶子节点(交易id)重新计算merkle树并逐个节点比对
+func checkMerkleTree(header *lpb.InternalBlock) error {
+	tree := header.GetMerkleTree()
+	if len(tree) == 0 {
+		return nil
+	}
+	txCount := int(header.GetTxCount())
+	if txCount > len(tree) {
+		return fmt.Errorf("%w: height %d", ErrMerkleRootMismatch, header.GetHeight())
+	}
+	leaves := make([]*lpb.Transaction, 0, txCount)
+	for _, txid := range tree[:txCount] {
+		leaves = append(leaves, &lpb.Transaction{Txid: txid})
+	}
+	rebuilt := ledger.MakeMerkleTree(leaves)
+	if len(rebuilt) != len(tree) || !bytes.Equal(rebuilt[len(rebuilt)-1], header.GetMerkleRoot()) {
+		return fmt.Errorf("%w: height %d", ErrMerkleRootMismatch, header.GetHeight())
+	}
+	for i := range rebuilt {
+		if !bytes.Equal(rebuilt[i], tree[i]) {
+			return fmt.Errorf("%w: height %d node %d", ErrMerkleRootMismatch, header.GetHeight(), i)
+		}
+	}
+	return nil
+}
+
+// VerifyHeaderChain 校验从本地账本之后开始的区块头链，不回放交易，供只跟随区块头的轻节点使用。
+// 校验区块头的相连关系、merkle树、区块id和矿工签名。tdpos/xpoa等共识校验出块人资格时需从账本
+// 读取前一个区块，因此只对前一个区块已在本地账本中的区块头(即第一个)调用CheckMinerMatch，
+// 后续区块头的出块人资格需在前一个区块确认后再校验，调用方应逐个确认并校验
+func (t *Miner) VerifyHeaderChain(ctx xctx.XContext, headers []*lpb.InternalBlock) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	if headers[0].GetHeight() < 1 {
+		return errors.New("bad header height")
+	}
+	prev, err := t.ctx.Ledger.QueryBlockHeaderByHeight(headers[0].GetHeight() - 1)
+	if err != nil {
+		return fmt.Errorf("%w: local block at height %d not found", ErrBlockChainGap, headers[0].GetHeight()-1)
+	}
+	if err := checkHeaderLinks(prev, headers); err != nil {
+		ctx.GetLog().Warn("verify header chain failed", "err", err)
+		return err
+	}
+	for _, header := range headers {
+		if err := t.ctx.Ledger.VerifyBlockHeader(header, ctx.GetLog().GetLogId()); err != nil {
+			return fmt.Errorf("verify header at height %d failed: %w", header.GetHeight(), err)
+		}
+	}
+	for _, header := range headers {
+		if !t.ctx.Ledger.ExistBlock(header.GetPreHash()) {
+			break
+		}
+		if isMatch, err := t.ctx.Consensus.CheckMinerMatch(ctx, state.NewBlockAgent(header)); !isMatch {
+			ctx.GetLog().Warn("consensus check miner match failed", "height", header.GetHeight(),
+				"blockId", utils.F(header.GetBlockid()), "err", err)
+			return fmt.Errorf("consensus check miner match failed at height %d", header.GetHeight())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+)
+
+func TestCheckHeaderLinks(t *testing.T) {
+	header := func(id, prev string, height int64) *lpb.InternalBlock {
+		tree := ledger.MakeMerkleTree([]*lpb.Transaction{{Txid: []byte("tx-" + id)}})
+		return &lpb.InternalBlock{
+			Blockid:    []byte(id),
+			PreHash:    []byte(prev),
+			Height:     height,
+			TxCount:    1,
+			MerkleTree: tree,
+			MerkleRoot: tree[len(tree)-1],
+		}
+	}
+	local := header("b1", "b0", 1)
+	b2, b3 := header("b2", "b1", 2), header("b3", "b2", 3)
+
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{b2, b3}); err != nil {
+		t.Fatal(err)
+	}
+	// 不与本地账本相连
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{b3}); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect chain gap, got %v", err)
+	}
+	if err := checkHeaderLinks(nil, []*lpb.InternalBlock{b2}); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect chain gap without local block, got %v", err)
+	}
+	// 区块头之间不相连
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{b2, header("x3", "x2", 3)}); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect chain gap, got %v", err)
+	}
+	// merkle树与merkle root不一致
+	bad := header("b2", "b1", 2)
+	bad.MerkleRoot = []byte("forged")
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{bad}); !errors.Is(err, ErrMerkleRootMismatch) {
+		t.Fatalf("expect merkle root mismatch, got %v", err)
+	}
+
+	// merkle root正确但中间节点被篡改
+	txs := []*lpb.Transaction{{Txid: []byte("tx1")}, {Txid: []byte("tx2")}, {Txid: []byte("tx3")}}
+	tree := ledger.MakeMerkleTree(txs)
+	multi := header("b2", "b1", 2)
+	multi.TxCount, multi.MerkleTree, multi.MerkleRoot = 3, tree, tree[len(tree)-1]
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{multi}); err != nil {
+		t.Fatal(err)
+	}
+	forged := append([][]byte{}, tree...)
+	forged[len(forged)-2] = []byte("forged")
+	multi.MerkleTree = forged
+	if err := checkHeaderLinks(local, []*lpb.InternalBlock{multi}); !errors.Is(err, ErrMerkleRootMismatch) {
+		t.Fatalf("expect merkle tree mismatch, got %v", err)
+	}
+}
+
+// minerMatchConsensus 只有指定矿工签名的区块通过出块人资格校验，
+// 与tdpos/xpoa一样从账本读取前一个区块，前一个区块不在账本中时panic
+type minerMatchConsensus struct {
+	consensus.PluggableConsensusInterface
+	ledger  *ledger.Ledger
+	miner   string
+	checked int
+}
+
+func (c *minerMatchConsensus) CheckMinerMatch(ctx xctx.XContext, block cctx.BlockInterface) (bool, error) {
+	c.checked++
+	if _, err := c.ledger.QueryBlockHeader(block.GetPreHash()); err != nil {
+		panic("pre block not in ledger")
+	}
+	return string(block.GetProposer()) == c.miner, nil
+}
+
+func TestVerifyHeaderChain(t *testing.T) {
+	l := newTestLedger(t)
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner := (*xaddress.Address)(cAddr)
+	other := newTestAddress(t, crypto)
+
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1 := newTestBlock(t, l, root, miner, 1)
+	if status := l.ConfirmBlock(b1, false); !status.Succ {
+		t.Fatal("confirm block failed")
+	}
+	h2 := newTestBlock(t, l, b1, miner, 2)
+	h3 := newTestBlock(t, l, h2, miner, 3)
+
+	log, _ := logs.NewLogger("", "miner")
+	cons := &minerMatchConsensus{ledger: l, miner: miner.Address}
+	m := &Miner{
+		log: log,
+		ctx: &common.ChainCtx{Ledger: l, Consensus: cons},
+	}
+	ctx := &xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()}
+	if err := m.VerifyHeaderChain(ctx, []*lpb.InternalBlock{h2, h3}); err != nil {
+		t.Fatal(err)
+	}
+	// 只校验前一个区块在本地的区块头，后续区块头不会让共识读取不存在的区块
+	if cons.checked != 1 {
+		t.Fatalf("only the header linked to local ledger should be checked, got %d", cons.checked)
+	}
+
+	// 区块头由无出块资格的节点自签名，签名本身合法但共识校验不通过
+	forgedH2 := newTestBlock(t, l, b1, other, 2)
+	if err := l.VerifyBlockHeader(forgedH2, "forged"); err != nil {
+		t.Fatalf("forged header should be self-consistent: %v", err)
+	}
+	if err := m.VerifyHeaderChain(ctx, []*lpb.InternalBlock{forgedH2, h3}); err == nil {
+		t.Fatal("header chain with forged first header should be rejected")
+	}
+
+	// 确认h2后，伪造的h3成为第一个区块头并被共识拒绝
+	if status := l.ConfirmBlock(h2, false); !status.Succ {
+		t.Fatal("confirm block failed")
+	}
+	forged := newTestBlock(t, l, h2, other, 3)
+	if err := m.VerifyHeaderChain(ctx, []*lpb.InternalBlock{forged}); err == nil {
+		t.Fatal("forged header should be rejected once its parent is local")
+	}
+}
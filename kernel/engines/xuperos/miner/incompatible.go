@@ -0,0 +1,57 @@
+package miner
+
+import (
+	"bytes"
+	"sync"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// incompatiblePeers 记录创世块与本地不同的节点，这类节点的链永远无法同步，
+// 记录后不再参与最长链选择，避免同步反复失败后又选中同一节点，节点重启后清空
+type incompatiblePeers struct {
+	mutex sync.RWMutex
+	peers map[string][]byte // key:peerId, val:对端创世块id
+}
+
+func newIncompatiblePeers() *incompatiblePeers {
+	return &incompatiblePeers{
+		peers: make(map[string][]byte),
+	}
+}
+
+// add 记录不兼容的节点，返回是否为新记录
+func (p *incompatiblePeers) add(peer string, genesis []byte) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.peers[peer]; ok {
+		return false
+	}
+	p.peers[peer] = genesis
+	return true
+}
+
+func (p *incompatiblePeers) has(peer string) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	_, ok := p.peers[peer]
+	return ok
+}
+
+// compatible 判断节点是否可以同步，已记录的节点和宣告的创世块与本地不同的节点返回false，
+// 未宣告创世块的节点无法判断，按兼容处理
+func (p *incompatiblePeers) compatible(peer string, localGenesis, peerGenesis []byte) bool {
+	if p.has(peer) {
+		return false
+	}
+	return len(peerGenesis) == 0 || bytes.Equal(localGenesis, peerGenesis)
+}
+
+// markIncompatible 记录创世块不同的节点
+func (t *Miner) markIncompatible(ctx xctx.XContext, peer string, genesis []byte) {
+	if t.incompatible.add(peer, genesis) {
+		ctx.GetLog().Error("peer has different genesis block, excluded from sync", "peer", peer,
+			"peerGenesis", utils.F(genesis))
+	}
+}
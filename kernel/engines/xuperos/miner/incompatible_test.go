@@ -0,0 +1,64 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/patrickmn/go-cache"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestPickLongestChainForeignGenesis(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	ctx := &xctx.BaseCtx{XLog: log}
+	m := &Miner{
+		faultPeerIdCache:  cache.New(faultPeerIdCacheExpired, faultCacheGCInterval),
+		faultBlockIdCache: cache.New(faultBlockIdCacheExpired, faultCacheGCInterval),
+		incompatible:      newIncompatiblePeers(),
+		ctx:               &common.ChainCtx{EngCtx: &common.EngineCtx{}},
+	}
+	status := func(from string, genesis, tip string, height int64) *protos.XuperMessage {
+		meta := &lpb.LedgerMeta{RootBlockid: []byte(genesis), TipBlockid: []byte(tip), TrunkHeight: height}
+		msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCKCHAINSTATUS_RES,
+			&xpb.ChainStatus{LedgerMeta: meta}, p2p.WithErrorType(protos.XuperMessage_SUCCESS))
+		msg.Header.From = from
+		return msg
+	}
+
+	// 宣告了不同创世块的节点链最长，也不会被选中
+	responses := []*protos.XuperMessage{
+		status("peerA", "g0", "a10", 10),
+		status("foreign", "g1", "f99", 99),
+		status("legacy", "", "l8", 8),
+	}
+	peer, height, tip := m.pickLongestChain(ctx, responses, []byte("g0"))
+	if peer != "peerA" || height != 10 || string(tip) != "a10" {
+		t.Fatalf("unexpected longest chain %s %d %s", peer, height, tip)
+	}
+	if !m.incompatible.has("foreign") || m.incompatible.has("legacy") {
+		t.Fatal("expect only peer with foreign genesis marked incompatible")
+	}
+
+	// 已记录的节点之后即使不再宣告创世块也不参与选择
+	responses = []*protos.XuperMessage{
+		status("peerA", "g0", "a10", 10),
+		status("foreign", "", "f99", 99),
+	}
+	if peer, _, _ = m.pickLongestChain(ctx, responses, []byte("g0")); peer != "peerA" {
+		t.Fatalf("expect incompatible peer skipped, got %s", peer)
+	}
+
+	// 同步时追溯到不同创世块的节点同样被记录
+	m.markIncompatible(ctx, "peerB", nil)
+	if m.incompatible.compatible("peerB", []byte("g0"), []byte("g0")) {
+		t.Fatal("expect peer marked during sync incompatible")
+	}
+}
@@ -0,0 +1,136 @@
+package miner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	sctx "github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var testGenesisConf = []byte(`{
+    "version": "1",
+    "predistribution": [
+        {
+            "address": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "quota": "100000000000000000000"
+        }
+    ],
+    "maxblocksize": "16",
+    "award": "1000000",
+    "decimals": "8",
+    "award_decay": {
+        "height_gap": 31536000,
+        "ratio": 1
+    },
+    "genesis_consensus": {
+        "name": "single",
+        "config": {
+            "miner": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "period": 3000
+        }
+    }
+}`)
+
+// newTestLedger 创建临时目录下的账本并确认创世块，测试结束时关闭并清理
+func newTestLedger(t *testing.T) *ledger.Ledger {
+	mock.InitLogForTest()
+	workspace, err := ioutil.TempDir("/tmp", "miner-ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = workspace
+	l, err := ledger.CreateLedger(lctx, testGenesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		l.Close()
+		os.RemoveAll(workspace)
+		os.RemoveAll(econf.GenDataAbsPath(workspace))
+	})
+
+	root, err := l.FormatRootBlock([]*lpb.Transaction{newTestCoinbase(t, "genesis", testGenesisConf)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := l.ConfirmBlock(root, true); !status.Succ {
+		t.Fatal("confirm root block failed")
+	}
+	return l
+}
+
+// newTestState 创建执行到账本创世块的状态机，状态库使用独立的临时目录
+func newTestState(t *testing.T, l *ledger.Ledger) *state.State {
+	workspace, err := ioutil.TempDir("/tmp", "miner-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypt, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateCtx, err := sctx.NewStateCtx(econf, "xuper", l, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateCtx.EnvCfg.ChainDir = workspace
+	s, err := state.NewState(stateCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(workspace)
+		os.RemoveAll(econf.GenDataAbsPath(workspace))
+	})
+	if err := s.Play(l.GetMeta().GetRootBlockid()); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestCoinbase(t *testing.T, to string, desc []byte) *lpb.Transaction {
+	tx := &lpb.Transaction{Coinbase: true, Desc: desc}
+	tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{Amount: big.NewInt(1000000).Bytes(), ToAddr: []byte(to)})
+	var err error
+	if tx.Txid, err = txhash.MakeTransactionID(tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// newTestBlock 生成由addr签名、链接到prev之后的区块，区块未写入账本
+func newTestBlock(t *testing.T, l *ledger.Ledger, prev *lpb.InternalBlock, addr *xaddress.Address, timestamp int64) *lpb.InternalBlock {
+	award := newTestCoinbase(t, addr.Address, []byte(fmt.Sprintf("award at %d", timestamp)))
+	block, err := l.FormatBlock([]*lpb.Transaction{award}, []byte(addr.Address), addr.PrivateKey,
+		timestamp, 0, 0, prev.GetBlockid(), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Height = prev.GetHeight() + 1
+	return block
+}
@@ -0,0 +1,83 @@
+package miner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// maxThrottleEntries 限流记录的上限，错误信息中带有高度等变化的内容时避免记录无限增长
+const maxThrottleEntries = 256
+
+type throttleEntry struct {
+	last       time.Time
+	suppressed int
+}
+
+// warnThrottle 对矿工循环中反复出现的相同告警限流，interval内相同告警只输出第一次，
+// 之后再次出现时输出一次并附带期间被抑制的次数，interval不大于0时不限流
+type warnThrottle struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	entries  map[string]*throttleEntry
+}
+
+func newWarnThrottle(interval time.Duration) *warnThrottle {
+	return &warnThrottle{
+		interval: interval,
+		entries:  make(map[string]*throttleEntry),
+	}
+}
+
+// allow 判断告警是否需要输出，返回上次输出后被抑制的次数
+func (w *warnThrottle) allow(key string, now time.Time) (bool, int) {
+	if w.interval <= 0 {
+		return true, 0
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	entry, ok := w.entries[key]
+	if !ok {
+		if len(w.entries) >= maxThrottleEntries {
+			w.prune(now)
+		}
+		w.entries[key] = &throttleEntry{last: now}
+		return true, 0
+	}
+	if now.Sub(entry.last) < w.interval {
+		entry.suppressed++
+		return false, 0
+	}
+	suppressed := entry.suppressed
+	entry.last, entry.suppressed = now, 0
+	return true, suppressed
+}
+
+// prune 清理超过interval未出现的告警，仍然过多时全部清空
+func (w *warnThrottle) prune(now time.Time) {
+	for key, entry := range w.entries {
+		if now.Sub(entry.last) >= w.interval {
+			delete(w.entries, key)
+		}
+	}
+	if len(w.entries) >= maxThrottleEntries {
+		w.entries = make(map[string]*throttleEntry)
+	}
+}
+
+// warnThrottled 按配置限流输出告警，相同的msg和err视为同一告警
+func (t *Miner) warnThrottled(log logs.Logger, msg string, err error, ctx ...interface{}) {
+	ok, suppressed := t.warnThrottle.allow(msg+"|"+fmt.Sprint(err), t.clock.Now())
+	if !ok {
+		return
+	}
+	ctx = append(ctx, "err", err)
+	if suppressed > 0 {
+		ctx = append(ctx, "suppressed", suppressed)
+	}
+	log.Warn(msg, ctx...)
+}
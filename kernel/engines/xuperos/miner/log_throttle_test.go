@@ -0,0 +1,35 @@
+package miner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarnThrottle(t *testing.T) {
+	now := time.Now()
+	w := newWarnThrottle(10 * time.Second)
+
+	if ok, _ := w.allow("a", now); !ok {
+		t.Fatal("first warning should be logged")
+	}
+	for i := 1; i <= 3; i++ {
+		if ok, _ := w.allow("a", now.Add(time.Duration(i)*time.Second)); ok {
+			t.Fatal("repeated warning should be suppressed")
+		}
+	}
+	if ok, _ := w.allow("b", now.Add(time.Second)); !ok {
+		t.Fatal("different warning should be logged")
+	}
+	ok, suppressed := w.allow("a", now.Add(11*time.Second))
+	if !ok || suppressed != 3 {
+		t.Fatalf("expect summary with 3 suppressed, got %v %d", ok, suppressed)
+	}
+
+	// 未开启时不限流
+	off := newWarnThrottle(0)
+	for i := 0; i < 3; i++ {
+		if ok, _ := off.allow("a", now); !ok {
+			t.Fatal("disabled throttle should log every warning")
+		}
+	}
+}
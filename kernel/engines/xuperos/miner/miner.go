@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/patrickmn/go-cache"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
@@ -63,27 +66,120 @@ type Miner struct {
 	faultPeerIdCache  *cache.Cache // key:peerId, val:count(累计出现错误次数)
 	faultBlockIdCache *cache.Cache // key:blockId, val:peerId
 
+	// 标记是否暂停出块，暂停期间仍然同步区块
+	paused int32
+	// 网络分区检测
+	partition *partitionDetector
+	// 状态机同步失败后的自动恢复
+	walkHealer *walkHealer
+	// 出块统计
+	stats *minerStats
+	// 时间源，出块时间戳取自这里
+	clock Clock
+	// 出块奖励审计
+	rewardAuditor *rewardAuditor
+	// 本节点签名的检查点
+	checkpoint *checkpointStore
+	// 矿工循环中重复告警的限流
+	warnThrottle *warnThrottle
+	// 进行中的同步目标
+	syncTarget syncTarget
+	// 本地时钟偏差检测
+	clockSkew *clockSkewDetector
+	// 最近校验通过的区块
+	verifiedBlocks *verifiedBlockCache
+	// 创世块与本地不同的节点
+	incompatible *incompatiblePeers
+	// 合并并发的强制同步请求
+	resyncGroup singleflight.Group
+	// 最近若干轮出块的阶段耗时
+	roundTimings *roundTimings
+	// 串行化矿工循环与外部对账本、状态机的修改，出块、同步、裁剪和区块导入不会交错执行，
+	// 同时保证单轮step执行期间矿工密钥不被替换
+	stepMutex sync.Mutex
+
 	// 标记是否退出运行
 	isExit bool
 	// 用户等待退出
 	exitWG sync.WaitGroup
 }
 
-func NewMiner(ctx *common.ChainCtx) *Miner {
+// checkChainCtx 检查矿工依赖的链上下文组件，避免组件初始化失败时矿工协程在运行中途panic
+func checkChainCtx(ctx *common.ChainCtx) error {
+	if ctx == nil || ctx.EngCtx == nil || ctx.EngCtx.EngCfg == nil {
+		return errors.New("miner requires chain context and engine config")
+	}
+	missing := make([]string, 0)
+	if ctx.EngCtx.Net == nil {
+		missing = append(missing, "Net")
+	}
+	if ctx.Ledger == nil {
+		missing = append(missing, "Ledger")
+	}
+	if ctx.State == nil {
+		missing = append(missing, "State")
+	}
+	if ctx.Consensus == nil {
+		missing = append(missing, "Consensus")
+	}
+	if ctx.Crypto == nil {
+		missing = append(missing, "Crypto")
+	}
+	if ctx.Address == nil {
+		missing = append(missing, "Address")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("miner requires %s in chain context", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func NewMiner(ctx *common.ChainCtx) (*Miner, error) {
+	if err := checkChainCtx(ctx); err != nil {
+		return nil, err
+	}
+
 	obj := &Miner{
-		ctx: ctx,
-		log: ctx.GetLog(),
+		ctx:   ctx,
+		log:   ctx.GetLog(),
+		clock: realClock{},
 	}
 
 	obj.faultPeerIdCache = cache.New(faultPeerIdCacheExpired, faultCacheGCInterval)
 	obj.faultBlockIdCache = cache.New(faultBlockIdCacheExpired, faultCacheGCInterval)
-
-	return obj
+	obj.partition = newPartitionDetector(ctx.EngCtx.EngCfg.PartitionTimeout)
+	obj.checkpoint = &checkpointStore{}
+	obj.warnThrottle = newWarnThrottle(ctx.EngCtx.EngCfg.MinerWarnThrottle)
+	obj.clockSkew = newClockSkewDetector()
+	obj.verifiedBlocks = newVerifiedBlockCache(ctx.BCName, ctx.EngCtx.EngCfg.VerifiedBlockCacheSize)
+	obj.roundTimings = newRoundTimings(ctx.EngCtx.EngCfg.MiningTimingHistory)
+	obj.incompatible = newIncompatiblePeers()
+	obj.rewardAuditor = &rewardAuditor{}
+	obj.walkHealer = newWalkHealer(ctx.EngCtx.EngCfg.WalkFailureThreshold)
+	obj.stats = newMinerStats()
+
+	return obj, nil
 }
 
 // Deprecated: 使用新的同步方案，这个函数仅用来兼容
-// 处理P2P网络中接收到的区块
+// 处理P2P网络中接收到的区块，区块由验证人产生且高于进行中的同步目标时，取消该同步并重新选取最长链；
+// 时间戳超前过多的区块同步时会被拒绝，不作为同步目标
 func (t *Miner) ProcBlock(ctx xctx.XContext, block *lpb.InternalBlock) error {
+	height, _ := t.syncTarget.current()
+	if height == 0 || block.GetHeight() <= height {
+		return nil
+	}
+	if err := t.checkFutureBlock(block); err != nil {
+		ctx.GetLog().Debug("ignore future block for sync target", "err", err)
+		return nil
+	}
+	if !t.proposedByValidator(ctx, block) {
+		return nil
+	}
+	if t.syncTarget.raise(block.GetHeight(), block.GetBlockid()) {
+		ctx.GetLog().Info("sync target raised, redirect in-flight sync", "oldHeight", height,
+			"height", block.GetHeight(), "blockId", utils.F(block.GetBlockid()))
+	}
 	return nil
 }
 
@@ -102,11 +198,13 @@ func (t *Miner) Start() {
 	t.status = statusFollowing
 
 	// 开启挖矿前先同步区块
-	ctx := &xctx.BaseCtx{
-		XLog:  t.log,
-		Timer: timer.NewXTimer(),
+	_ = t.syncWithNeighbors(t.newRoundContext())
+
+	// 按配置周期性重新广播未确认交易
+	if t.ctx.EngCtx.EngCfg.TxRebroadcastInterval > 0 {
+		t.exitWG.Add(1)
+		go t.rebroadcastUnconfirmedTx()
 	}
-	_ = t.syncWithNeighbors(ctx)
 
 	// 启动矿工循环
 	for !t.IsExit() {
@@ -114,7 +212,7 @@ func (t *Miner) Start() {
 
 		// 如果出错，休眠1s后重试，防止cpu被打满
 		if err != nil {
-			t.log.Warn("miner run occurred error,sleep 1s try", "err", err)
+			t.warnThrottled(t.log, "miner run occurred error,sleep 1s try", err)
 			time.Sleep(time.Second)
 		}
 	}
@@ -130,36 +228,73 @@ func (t *Miner) IsExit() bool {
 	return t.isExit
 }
 
-func traceMiner() func(string) {
-	last := time.Now()
+// PauseMining 暂停出块，当前正在进行的出块会完整执行，下一轮开始不再出块
+func (t *Miner) PauseMining() {
+	atomic.StoreInt32(&t.paused, 1)
+}
+
+// ResumeMining 恢复出块
+func (t *Miner) ResumeMining() {
+	atomic.StoreInt32(&t.paused, 0)
+}
+
+func (t *Miner) IsMiningPaused() bool {
+	return atomic.LoadInt32(&t.paused) == 1
+}
+
+// traceMiner 按注入的时钟统计矿工循环各阶段耗时
+func (t *Miner) traceMiner() func(string) {
+	last := t.clock.Now()
 	return func(action string) {
-		metrics.CallMethodHistogram.WithLabelValues("miner", action).Observe(time.Since(last).Seconds())
-		last = time.Now()
+		now := t.clock.Now()
+		metrics.CallMethodHistogram.WithLabelValues("miner", action).Observe(now.Sub(last).Seconds())
+		last = now
 	}
 }
 
+// newRoundContext 为一轮矿工循环创建上下文，同一轮的同步、出块日志及p2p消息共用一个logid
+func (t *Miner) newRoundContext() *xctx.BaseCtx {
+	var log logs.Logger = t.log
+	if roundLog, err := logs.NewLogger(utils.GenLogId(), "miner"); err == nil {
+		log = roundLog
+	}
+	return &xctx.BaseCtx{
+		XLog:  log,
+		Timer: timer.NewXTimer(),
+	}
+}
+
+// withChainLock 在矿工循环之外修改账本和状态机时使用，等待当前一轮step结束后独占执行
+func (t *Miner) withChainLock(f func() error) error {
+	t.stepMutex.Lock()
+	defer t.stepMutex.Unlock()
+	return f()
+}
+
 // step 用于推动节点循环进行一次动作，可以是一次出块动作(矿工角色)，也可以是一次区块同步（非矿工）
 // 在此期间可能会发生节点角色变更。
 func (t *Miner) step() error {
+	t.stepMutex.Lock()
+	defer t.stepMutex.Unlock()
+
 	ledgerTipId := t.ctx.Ledger.GetMeta().TipBlockid
 	ledgerTipHeight := t.ctx.Ledger.GetMeta().TrunkHeight
 	stateTipId := t.ctx.State.GetLatestBlockid()
 
-	log, _ := logs.NewLogger("", "miner")
-	ctx := &xctx.BaseCtx{
-		XLog:  log,
-		Timer: timer.NewXTimer(),
-	}
+	ctx := t.newRoundContext()
+	t.checkPartition()
 
 	// 账本和状态机最新区块id不一致，需要进行一次同步
 	if !bytes.Equal(ledgerTipId, stateTipId) {
-		err := t.ctx.State.Walk(ledgerTipId, false)
+		err := t.walkState(ctx, ledgerTipId)
 		if err != nil {
 			return err
 		}
 	}
 
-	trace := traceMiner()
+	t.updateCheckpoint()
+
+	trace := t.traceMiner()
 
 	ctx.GetLog().Trace("miner step", "ledgerTipHeight", ledgerTipHeight, "ledgerTipId",
 		utils.F(ledgerTipId), "stateTipId", utils.F(stateTipId))
@@ -181,6 +316,24 @@ func (t *Miner) step() error {
 		return err
 	}
 
+	// 暂停出块时按非矿工处理，继续同步区块
+	if isMiner && t.IsMiningPaused() {
+		ctx.GetLog().Trace("mining paused, skip mining", "height", ledgerTipHeight+1)
+		isMiner = false
+	}
+
+	// 网络分区期间出块无人接收，只会形成注定被丢弃的分叉，按配置停止出块
+	if isMiner && t.ctx.EngCtx.EngCfg.PartitionReadOnly && t.IsPartitioned() {
+		ctx.GetLog().Trace("network partitioned, skip mining", "height", ledgerTipHeight+1)
+		isMiner = false
+	}
+
+	// 本地时钟偏差过大时出块时间戳会被其他节点拒绝，按配置停止出块
+	if isMiner && t.checkClockSkew(ledgerTipHeight+1) {
+		ctx.GetLog().Trace("local clock skewed, skip mining", "height", ledgerTipHeight+1)
+		isMiner = false
+	}
+
 	// 如果是矿工，出块
 	if isMiner {
 		if t.status == statusFollowing || isSync {
@@ -240,12 +393,12 @@ func (t *Miner) mining(ctx xctx.XContext) error {
 
 	// 1.共识挖矿前处理
 	height := t.ctx.Ledger.GetMeta().TrunkHeight + 1
-	now := time.Now()
+	now := t.clock.Now()
 	truncateTarget, extData, err := t.ctx.Consensus.ProcessBeforeMiner(height, now.UnixNano())
 	ctx.GetTimer().Mark("ProcessBeforeMiner")
 	if err != nil {
 		ctx.GetLog().Warn("consensus process before miner failed", "err", err)
-		return fmt.Errorf("consensus process before miner failed")
+		return newError(ErrConsensus, "ProcessBeforeMiner", err)
 	}
 	ctx.GetLog().Debug("consensus before miner succ", "truncateTarget", truncateTarget, "extData", string(extData))
 	if truncateTarget != nil {
@@ -259,14 +412,16 @@ func (t *Miner) mining(ctx xctx.XContext) error {
 	}
 
 	// 2.打包区块
-	beginTime := time.Now()
+	beginTime := t.clock.Now()
 	block, err := t.packBlock(ctx, height, now, extData)
 	ctx.GetTimer().Mark("PackBlock")
-	metrics.CallMethodHistogram.WithLabelValues("miner", "PackBlock").Observe(time.Since(beginTime).Seconds())
+	packCost := t.clock.Now().Sub(beginTime)
+	metrics.CallMethodHistogram.WithLabelValues("miner", "PackBlock").Observe(packCost.Seconds())
 	if err != nil {
 		ctx.GetLog().Warn("pack block error", "err", err)
 		return err
 	}
+	t.stats.observePack(packCost)
 	ctx.GetLog().Debug("pack block succ", "height", height, "blockId", utils.F(block.GetBlockid()))
 
 	// 3. 针对一些需要patch区块的共识
@@ -280,7 +435,13 @@ func (t *Miner) mining(ctx xctx.XContext) error {
 	if err != nil {
 		ctx.GetLog().Warn("consensus calculate block failed", "err", err,
 			"blockId", utils.F(block.Blockid))
-		return fmt.Errorf("consensus calculate block failed")
+		return newError(ErrConsensus, "CalculateBlock", err)
+	}
+	// 共识修改区块后区块id必须与区块内容一致，否则账本确认后其他节点校验失败
+	if err := checkCalculatedBlock(block); err != nil {
+		ctx.GetLog().Error("consensus calculate block produced inconsistent block id", "err", err,
+			"originalBlockId", utils.F(origBlkId))
+		return newError(ErrConsensus, "CalculateBlock", err)
 	}
 	ctx.GetLog().Trace("start confirm block for miner", "originalBlockId", utils.F(origBlkId),
 		"newBlockId", utils.F(block.Blockid))
@@ -301,12 +462,21 @@ func (t *Miner) mining(ctx xctx.XContext) error {
 		// todo 这里暂时不返回错误
 	}
 
+	// 区块id单独拷贝，记录不引用区块内容，避免随区块一直驻留内存
+	t.roundTimings.add(common.RoundTiming{
+		Height:     block.GetHeight(),
+		BlockId:    append([]byte(nil), block.GetBlockid()...),
+		FinishTime: t.clock.Now(),
+		Stages:     ctx.GetTimer().Stages(),
+		Total:      ctx.GetTimer().Elapsed(),
+	})
 	ctx.GetLog().Info("finish new block generation", "blockId", utils.F(block.GetBlockid()),
 		"height", height, "txCount", block.TxCount, "size", proto.Size(block), "costs", ctx.GetTimer().Print())
 	return nil
 }
 
 // 裁剪掉账本最新的区块
+// 只在step中调用，持有stepMutex，裁剪期间不会有同步或导入的区块写入账本
 func (t *Miner) truncateForMiner(ctx xctx.XContext, target []byte) error {
 	_, err := t.ctx.Ledger.QueryBlockHeader(target)
 	if err != nil {
@@ -319,7 +489,7 @@ func (t *Miner) truncateForMiner(ctx xctx.XContext, target []byte) error {
 	if err != nil {
 		ctx.GetLog().Warn("truncate failed because state walk error", "ledgerTipId", utils.F(t.ctx.Ledger.GetMeta().TipBlockid),
 			"walkTargetBlockId", utils.F(target))
-		return err
+		return newError(ErrStateWalk, "truncateForMiner", err)
 	}
 
 	// 账本裁剪到这个区块
@@ -341,19 +511,27 @@ func (t *Miner) packBlock(ctx xctx.XContext, height int64,
 	}
 	ctx.GetLog().Debug("pack block get max size succ", "sizeLimit", sizeLimit)
 
+	// 共识数据会写入区块头，异常的共识输出会让每个区块都膨胀，需要在打包前拦截
+	if err := checkConsDataSize(consData, t.ctx.EngCtx.EngCfg.MaxConsensusDataSize); err != nil {
+		ctx.GetLog().Warn("check consensus data failed", "err", err)
+		return nil, err
+	}
+
 	// 1.生成timer交易
 	autoTx, err := t.getTimerTx(height)
 	if err != nil {
 		return nil, err
 	}
-	if len(autoTx.TxOutputsExt) > 0 {
+	// 状态机未初始化合约时没有timer交易，autoTx为nil
+	if len(autoTx.GetTxOutputsExt()) > 0 {
 		sizeLimit -= proto.Size(autoTx)
 	}
 
 	ctx.GetLog().Debug("pack block get timer tx succ", "auto tx", autoTx)
 
-	// 2.选择本次要打包的tx
-	generalTxList, err := t.getUnconfirmedTx(sizeLimit)
+	// 2.选择本次要打包的tx，奖励交易和timer交易同样计入交易数限制
+	countLimit := generalTxCountLimit(t.ctx.EngCtx.EngCfg.MaxTxCountPerBlock, len(autoTx.GetTxOutputsExt()) > 0)
+	generalTxList, err := t.getUnconfirmedTx(sizeLimit, countLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -370,19 +548,24 @@ func (t *Miner) packBlock(ctx xctx.XContext, height int64,
 	// 先coinbase tx
 	txList = append(txList, awardTx)
 	// 再autotx
-	if len(autoTx.TxOutputsExt) > 0 {
+	if len(autoTx.GetTxOutputsExt()) > 0 {
 		txList = append(txList, autoTx)
 	}
 	// 最后普通tx
 	if len(generalTxList) > 0 {
 		txList = append(txList, generalTxList...)
 	}
+	// 同一个txid在区块中出现两次会被其他节点拒绝，出现重复说明上游有bug，需要记录下来
+	txList, dupTxs := dedupTxList(txList)
+	for _, dup := range dupTxs {
+		ctx.GetLog().Error("pack block found duplicate tx, dropped", "txid", utils.F(dup.GetTxid()))
+	}
 
 	// 4.打包区块
 	consInfo, err := t.convertConsData(consData)
 	if err != nil {
 		ctx.GetLog().Warn("convert consensus data failed", "err", err, "consData", string(consData))
-		return nil, fmt.Errorf("convert consensus data failed")
+		return nil, fmt.Errorf("convert consensus data failed: %v", err)
 	}
 	block, err := t.ctx.Ledger.FormatMinerBlock(txList, []byte(t.ctx.Address.Address),
 		t.ctx.Address.PrivateKey, now.UnixNano(), consInfo.CurTerm, consInfo.CurBlockNum,
@@ -396,6 +579,24 @@ func (t *Miner) packBlock(ctx xctx.XContext, height int64,
 	return block, nil
 }
 
+// dedupTxList 按txid去重，保留第一次出现的交易，返回去重后的列表和被丢弃的交易
+// 奖励交易和timer交易排在最前面，因此总会被保留
+func dedupTxList(txList []*lpb.Transaction) ([]*lpb.Transaction, []*lpb.Transaction) {
+	seen := make(map[string]struct{}, len(txList))
+	result := make([]*lpb.Transaction, 0, len(txList))
+	var dups []*lpb.Transaction
+	for _, tx := range txList {
+		txid := string(tx.GetTxid())
+		if _, ok := seen[txid]; ok {
+			dups = append(dups, tx)
+			continue
+		}
+		seen[txid] = struct{}{}
+		result = append(result, tx)
+	}
+	return result, dups
+}
+
 func (t *Miner) convertConsData(data []byte) (*state.ConsensusStorage, error) {
 	var consInfo state.ConsensusStorage
 	if len(data) < 1 {
@@ -406,10 +607,22 @@ func (t *Miner) convertConsData(data []byte) (*state.ConsensusStorage, error) {
 	if err != nil {
 		return nil, err
 	}
+	if consInfo.CurTerm < 0 || consInfo.CurBlockNum < 0 || consInfo.TargetBits < 0 {
+		return nil, fmt.Errorf("bad consensus data, curTerm:%d curBlockNum:%d targetBits:%d",
+			consInfo.CurTerm, consInfo.CurBlockNum, consInfo.TargetBits)
+	}
 
 	return &consInfo, nil
 }
 
+// checkConsDataSize 检查共识数据大小，limit为0时不限制
+func checkConsDataSize(data []byte, limit int) error {
+	if limit > 0 && len(data) > limit {
+		return fmt.Errorf("consensus data too large: size %d exceeds limit %d", len(data), limit)
+	}
+	return nil
+}
+
 func (t *Miner) getTimerTx(height int64) (*lpb.Transaction, error) {
 	autoTx, err := t.ctx.State.GetTimerTx(height)
 	if err != nil {
@@ -420,12 +633,37 @@ func (t *Miner) getTimerTx(height int64) (*lpb.Transaction, error) {
 	return autoTx, nil
 }
 
-func (t *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
-	unconfirmedTxs, err := t.ctx.State.GetUnconfirmedTx(false, sizeLimit)
+// PeekTimerTx 查询指定高度将要执行的定时交易，只在沙盒中预执行，不修改状态
+// 该高度没有定时任务时返回ErrNoTimerTx
+func (t *Miner) PeekTimerTx(height int64) (*lpb.Transaction, error) {
+	autoTx, err := t.getTimerTx(height)
+	if err != nil {
+		return nil, err
+	}
+	if autoTx == nil || len(autoTx.TxOutputsExt) == 0 {
+		return nil, common.ErrNoTimerTx
+	}
+	return autoTx, nil
+}
+
+// getUnconfirmedTx 按区块大小和交易数选择待打包的交易，countLimit小于0表示不限制交易数
+func (t *Miner) getUnconfirmedTx(sizeLimit, countLimit int) ([]*lpb.Transaction, error) {
+	maxCount, maxBytes := t.ctx.EngCtx.EngCfg.MaxTxCountPerSender, t.ctx.EngCtx.EngCfg.MaxTxBytesPerSender
+	if maxCount <= 0 && maxBytes <= 0 {
+		unconfirmedTxs, err := t.ctx.State.GetUnconfirmedTx(false, sizeLimit)
+		if err != nil {
+			return nil, err
+		}
+		return limitTxCount(unconfirmedTxs, countLimit), nil
+	}
+
+	// 按发起人限制后空出的区块空间由其他交易填充，因此先取出全部未确认交易再按大小截取
+	unconfirmedTxs, err := t.ctx.State.GetUnconfirmedTx(false, 0)
 	if err != nil {
 		return nil, err
 	}
-	return unconfirmedTxs, nil
+	txList := limitTxPerSender(unconfirmedTxs, maxCount, maxBytes)
+	return limitTxCount(limitTxSize(txList, sizeLimit), countLimit), nil
 	// txList := make([]*lpb.Transaction, 0)
 	// for _, tx := range unconfirmedTxs {
 	// 	size := proto.Size(tx)
@@ -439,6 +677,91 @@ func (t *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
 	// return txList, nil
 }
 
+// generalTxCountLimit 扣除奖励交易和timer交易后，区块中还能打包的普通交易数，-1表示不限制
+func generalTxCountLimit(maxCount int, hasAutoTx bool) int {
+	if maxCount <= 0 {
+		return -1
+	}
+	limit := maxCount - 1
+	if hasAutoTx {
+		limit--
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	return limit
+}
+
+// limitTxCount 截取前limit个交易，limit小于0表示不限制
+// 未确认交易按执行顺序返回，父交易总在子交易之前，截取前缀不会破坏依赖关系
+func limitTxCount(txList []*lpb.Transaction, limit int) []*lpb.Transaction {
+	if limit < 0 || len(txList) <= limit {
+		return txList
+	}
+	return txList[:limit]
+}
+
+// limitTxSize 截取总大小不超过sizeLimit的前缀，与状态机按大小获取未确认交易的方式一致，sizeLimit不大于0表示不限制
+func limitTxSize(txList []*lpb.Transaction, sizeLimit int) []*lpb.Transaction {
+	if sizeLimit <= 0 {
+		return txList
+	}
+	total := 0
+	for i, tx := range txList {
+		total += proto.Size(tx)
+		if total > sizeLimit {
+			return txList[:i]
+		}
+	}
+	return txList
+}
+
+// limitTxPerSender 限制单个发起人在一个区块中的交易数和交易总大小，避免单个账户占满区块，
+// 不大于0的限制不生效。超出限制的交易被跳过，引用了被跳过交易输出或读集的交易同样跳过，保证父交易总在子交易之前
+func limitTxPerSender(txList []*lpb.Transaction, maxCount, maxBytes int) []*lpb.Transaction {
+	if maxCount <= 0 && maxBytes <= 0 {
+		return txList
+	}
+	counts := make(map[string]int)
+	sizes := make(map[string]int)
+	skipped := make(map[string]bool)
+	result := make([]*lpb.Transaction, 0, len(txList))
+	for _, tx := range txList {
+		if dependsOnTxs(tx, skipped) {
+			skipped[string(tx.GetTxid())] = true
+			continue
+		}
+		sender := tx.GetInitiator()
+		size := proto.Size(tx)
+		if (maxCount > 0 && counts[sender] >= maxCount) || (maxBytes > 0 && sizes[sender]+size > maxBytes) {
+			skipped[string(tx.GetTxid())] = true
+			continue
+		}
+		counts[sender]++
+		sizes[sender] += size
+		result = append(result, tx)
+	}
+	return result
+}
+
+// dependsOnTxs 判断交易是否引用了txids中交易的输出或读集
+func dependsOnTxs(tx *lpb.Transaction, txids map[string]bool) bool {
+	if len(txids) == 0 {
+		return false
+	}
+	for _, input := range tx.GetTxInputs() {
+		if txids[string(input.GetRefTxid())] {
+			return true
+		}
+	}
+	for _, input := range tx.GetTxInputsExt() {
+		if txids[string(input.GetRefTxid())] {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Miner) getAwardTx(height int64) (*lpb.Transaction, error) {
 	amount := t.ctx.Ledger.GenesisBlock.CalcAward(height)
 	if amount.Cmp(big.NewInt(0)) < 0 {
@@ -499,8 +822,10 @@ func (t *Miner) confirmBlockForMiner(ctx xctx.XContext, block *lpb.InternalBlock
 	if !bytes.Equal(block.PreHash, tip) {
 		ctx.GetLog().Warn("confirmBlockForMiner error", "tip", utils.F(tip),
 			"prehash", utils.F(block.PreHash))
-		return errors.New("confirm block prehash mismatch")
+		return newError(ErrValidation, "confirmBlockForMiner", ErrHashMissMatch)
 	}
+	// 账本、状态机和共识都使用确认开始时的区块id，任一环节不一致时立即报错
+	blockId := append([]byte(nil), block.Blockid...)
 
 	// 账本确认区块
 	confirmStatus := t.ctx.Ledger.ConfirmBlock(block, false)
@@ -513,29 +838,42 @@ func (t *Miner) confirmBlockForMiner(ctx xctx.XContext, block *lpb.InternalBlock
 		}
 		ctx.GetLog().Trace("ledger confirm block success", "height", block.Height,
 			"blockId", utils.F(block.Blockid))
+		if err := checkConfirmedId("ledger tip", blockId, t.ctx.Ledger.GetMeta().TipBlockid); err != nil {
+			ctx.GetLog().Error("ledger confirmed a different block", "err", err)
+			return newError(ErrLedgerConfirm, "confirmBlockForMiner", err)
+		}
 	} else {
 		ctx.GetLog().Warn("ledger confirm block failed", "err", confirmStatus.Error,
 			"blockId", utils.F(block.Blockid))
-		return errors.New("ledger confirm block error")
+		return newError(ErrLedgerConfirm, "confirmBlockForMiner", confirmStatus.Error)
 	}
 
 	// 状态机确认区块
-	err := t.ctx.State.PlayForMiner(block.Blockid)
+	err := t.ctx.State.PlayForMiner(blockId)
 	ctx.GetTimer().Mark("PlayForMiner")
 	if err != nil {
-		ctx.GetLog().Warn("state play error ", "error", err, "blockId", utils.F(block.Blockid))
+		ctx.GetLog().Warn("state play error ", "error", err, "blockId", utils.F(blockId))
+	} else if err := checkConfirmedId("state tip", blockId, t.ctx.State.GetLatestBlockid()); err != nil {
+		ctx.GetLog().Error("state played a different block", "err", err)
+		return newError(ErrStateWalk, "confirmBlockForMiner", err)
 	}
 
 	// 共识确认区块
+	if err := checkConfirmedId("consensus block", blockId, block.Blockid); err != nil {
+		ctx.GetLog().Error("block id changed during confirm", "err", err)
+		return newError(ErrConsensus, "confirmBlockForMiner", err)
+	}
 	blkAgent := state.NewBlockAgent(block)
 	err = t.ctx.Consensus.ProcessConfirmBlock(blkAgent)
 	ctx.GetTimer().Mark("ProcessConfirmBlock")
 	if err != nil {
 		ctx.GetLog().Warn("consensus confirm block error", "err", err,
 			"blockId", utils.F(block.Blockid))
-		return err
+		return newError(ErrConsensus, "ProcessConfirmBlock", err)
 	}
 
+	t.stats.observeBlock(block)
+	t.auditReward(block)
 	ctx.GetLog().Trace("confirm block for miner succ", "blockId", utils.F(block.Blockid))
 	return nil
 }
@@ -0,0 +1,238 @@
+package miner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestDedupTxList(t *testing.T) {
+	newTx := func(txid string, desc string) *lpb.Transaction {
+		return &lpb.Transaction{Txid: []byte(txid), Desc: []byte(desc)}
+	}
+	award := newTx("award", "award")
+	auto := newTx("auto", "auto")
+	general := []*lpb.Transaction{
+		newTx("tx1", "general"),
+		newTx("award", "general"),
+		newTx("tx2", "general"),
+		newTx("tx1", "general"),
+		newTx("auto", "general"),
+	}
+	txList := append([]*lpb.Transaction{award, auto}, general...)
+
+	result, dups := dedupTxList(txList)
+	expect := []string{"award", "auto", "tx1", "tx2"}
+	if len(result) != len(expect) {
+		t.Fatalf("expect %d txs, got %d", len(expect), len(result))
+	}
+	for i, tx := range result {
+		if string(tx.Txid) != expect[i] {
+			t.Fatalf("expect tx %s at %d, got %s", expect[i], i, tx.Txid)
+		}
+	}
+	// 强制打包的奖励交易和timer交易不能被mempool中的同名交易替换
+	if result[0] != award || result[1] != auto {
+		t.Fatal("mandatory tx replaced")
+	}
+	if len(dups) != 3 {
+		t.Fatalf("expect 3 duplicate txs, got %d", len(dups))
+	}
+
+	result, dups = dedupTxList(general[:1])
+	if len(result) != 1 || len(dups) != 0 {
+		t.Fatalf("unexpected dedup result %d %d", len(result), len(dups))
+	}
+}
+
+func TestConvertConsData(t *testing.T) {
+	m := &Miner{}
+	info, err := m.convertConsData(nil)
+	if err != nil || info.CurTerm != 0 {
+		t.Fatalf("empty consensus data should be accepted, got %v", err)
+	}
+	info, err = m.convertConsData([]byte(`{"curTerm":3,"curBlockNum":2}`))
+	if err != nil || info.CurTerm != 3 || info.CurBlockNum != 2 {
+		t.Fatalf("unexpected consensus data %+v, err %v", info, err)
+	}
+	for _, data := range []string{`{"curTerm":-1}`, `{"curBlockNum":-1}`, `{"targetBits":-1}`, `{`} {
+		if _, err := m.convertConsData([]byte(data)); err == nil {
+			t.Fatalf("expect error for %s", data)
+		}
+	}
+}
+
+func TestCheckConsDataSize(t *testing.T) {
+	data := make([]byte, 1024)
+	if err := checkConsDataSize(data, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkConsDataSize(data, 1023); err == nil {
+		t.Fatal("expect size error")
+	}
+	if err := checkConsDataSize(data, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimitTxCount(t *testing.T) {
+	txList := make([]*lpb.Transaction, 0, 100)
+	size := 0
+	for i := 0; i < 100; i++ {
+		tx := &lpb.Transaction{Txid: []byte{byte(i)}}
+		size += proto.Size(tx)
+		txList = append(txList, tx)
+	}
+	// 交易都很小，全部在大小限制内，但交易数先达到上限
+	if size >= 1<<20 {
+		t.Fatalf("txs too large for test, size %d", size)
+	}
+
+	limit := generalTxCountLimit(10, true)
+	if limit != 8 {
+		t.Fatalf("expect 8 general txs, got %d", limit)
+	}
+	result := limitTxCount(txList, limit)
+	if len(result) != 8 {
+		t.Fatalf("expect 8 txs, got %d", len(result))
+	}
+	for i, tx := range result {
+		if tx != txList[i] {
+			t.Fatalf("tx order changed at %d", i)
+		}
+	}
+
+	if limit := generalTxCountLimit(10, false); limit != 9 {
+		t.Fatalf("expect 9 general txs, got %d", limit)
+	}
+	if limit := generalTxCountLimit(1, true); limit != 0 {
+		t.Fatalf("expect 0 general txs, got %d", limit)
+	}
+	if limit := generalTxCountLimit(0, true); limit != -1 {
+		t.Fatalf("expect no limit, got %d", limit)
+	}
+	if result := limitTxCount(txList, -1); len(result) != len(txList) {
+		t.Fatalf("expect %d txs, got %d", len(txList), len(result))
+	}
+}
+
+func TestPackBlockMaxTxCount(t *testing.T) {
+	l := newTestLedger(t)
+	s := newTestState(t, l)
+	crypto, _, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		tx := &lpb.Transaction{Version: 1, Desc: []byte(fmt.Sprintf("tx-%d", i)), Timestamp: time.Now().UnixNano()}
+		if tx.Txid, err = txhash.MakeTransactionID(tx); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DoTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := engconf.GetDefEngineConf()
+	cfg.MaxTxCountPerBlock = 5
+	log, _ := logs.NewLogger("", "miner")
+	m := &Miner{
+		log:   log,
+		clock: realClock{},
+		ctx: &common.ChainCtx{
+			BCName:  "xuper",
+			Ledger:  l,
+			State:   s,
+			Address: newTestAddress(t, crypto),
+			EngCtx:  &common.EngineCtx{EngCfg: cfg},
+		},
+	}
+
+	// 未确认交易数超过上限，区块交易数(含奖励交易)正好等于配置的上限
+	ctx := m.newRoundContext()
+	block, err := m.packBlock(ctx, 1, time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.Transactions) != 5 {
+		t.Fatalf("expect 5 txs in block, got %d", len(block.Transactions))
+	}
+
+	// 开启按发起人限制时走另一条选择路径，交易数上限同样生效
+	cfg.MaxTxCountPerSender = 100
+	block, err = m.packBlock(ctx, 1, time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.Transactions) != 5 {
+		t.Fatalf("expect 5 txs in block with sender limit, got %d", len(block.Transactions))
+	}
+}
+
+func TestLimitTxPerSender(t *testing.T) {
+	newTx := func(txid, initiator string, parents ...string) *lpb.Transaction {
+		tx := &lpb.Transaction{Txid: []byte(txid), Initiator: initiator}
+		for _, parent := range parents {
+			tx.TxInputs = append(tx.TxInputs, &protos.TxInput{RefTxid: []byte(parent)})
+		}
+		return tx
+	}
+	// 大户的交易排在最前面，a3花费了a2的输出
+	txList := []*lpb.Transaction{
+		newTx("a1", "whale"), newTx("a2", "whale"), newTx("a3", "whale", "a2"),
+		newTx("a4", "whale"), newTx("b1", "bobby"), newTx("c1", "carol", "a4"), newTx("b2", "bobby"),
+	}
+	txids := func(txs []*lpb.Transaction) string {
+		ids := make([]string, 0, len(txs))
+		for _, tx := range txs {
+			ids = append(ids, string(tx.Txid))
+		}
+		return strings.Join(ids, ",")
+	}
+
+	if got := txids(limitTxPerSender(txList, 0, 0)); got != "a1,a2,a3,a4,b1,c1,b2" {
+		t.Fatalf("expect no limit by default, got %s", got)
+	}
+	// 每个发起人最多2笔，被跳过交易的子交易也要跳过
+	if got := txids(limitTxPerSender(txList, 2, 0)); got != "a1,a2,b1,b2" {
+		t.Fatalf("unexpected txs with count limit: %s", got)
+	}
+	// 按大小限制，每个发起人最多两笔普通大小的交易
+	size := proto.Size(txList[0])
+	if got := txids(limitTxPerSender(txList, 0, size*2)); got != "a1,a2,b1,b2" {
+		t.Fatalf("unexpected txs with size limit: %s", got)
+	}
+
+	// 区块大小限制相同时，大户空出的空间由其他发起人的交易填充
+	if got := txids(limitTxSize(txList, size*2)); got != "a1,a2" {
+		t.Fatalf("unexpected txs without sender limit: %s", got)
+	}
+	if got := txids(limitTxSize(limitTxPerSender(txList, 1, 0), size*2)); got != "a1,b1" {
+		t.Fatalf("unexpected txs with sender limit: %s", got)
+	}
+}
+
+func TestNewMinerMissingDependency(t *testing.T) {
+	if _, err := NewMiner(nil); err == nil {
+		t.Fatal("expect error for nil chain context")
+	}
+
+	ctx := &common.ChainCtx{
+		EngCtx: &common.EngineCtx{EngCfg: &engconf.EngineConf{}},
+	}
+	_, err := NewMiner(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Consensus") {
+		t.Fatalf("expect missing consensus error, got %v", err)
+	}
+}
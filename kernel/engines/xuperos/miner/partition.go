@@ -0,0 +1,73 @@
+package miner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xupercore/lib/metrics"
+)
+
+// partitionDetector 根据邻居节点数判断节点是否与网络分区
+// 连续timeout时长没有任何邻居节点时认为已分区，邻居恢复后立即解除
+type partitionDetector struct {
+	mutex       sync.RWMutex
+	timeout     time.Duration
+	lastSeen    time.Time
+	partitioned bool
+}
+
+func newPartitionDetector(timeout time.Duration) *partitionDetector {
+	return &partitionDetector{
+		timeout:  timeout,
+		lastSeen: time.Now(),
+	}
+}
+
+// update 更新当前邻居数，返回分区状态是否发生变化
+func (p *partitionDetector) update(peerCount int, now time.Time) bool {
+	if p.timeout <= 0 {
+		return false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	partitioned := false
+	if peerCount > 0 {
+		p.lastSeen = now
+	} else {
+		partitioned = now.Sub(p.lastSeen) > p.timeout
+	}
+	changed := partitioned != p.partitioned
+	p.partitioned = partitioned
+	return changed
+}
+
+func (p *partitionDetector) isPartitioned() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.partitioned
+}
+
+// checkPartition 检查节点是否与网络分区，并更新监控
+// 每轮出块都会调用，只取已连接的邻居数，不查询邻居的账户等详细信息
+func (t *Miner) checkPartition() {
+	peerCount := t.ctx.EngCtx.Net.PeerCount()
+	if !t.partition.update(peerCount, t.clock.Now()) {
+		return
+	}
+
+	if t.partition.isPartitioned() {
+		t.log.Warn("network partition detected, no peers available",
+			"timeout", t.ctx.EngCtx.EngCfg.PartitionTimeout, "readOnly", t.ctx.EngCtx.EngCfg.PartitionReadOnly)
+		metrics.NetworkPartitionGauge.WithLabelValues(t.ctx.BCName).Set(1)
+	} else {
+		t.log.Info("network partition recovered", "peerCount", peerCount)
+		metrics.NetworkPartitionGauge.WithLabelValues(t.ctx.BCName).Set(0)
+	}
+}
+
+// IsPartitioned 节点是否处于网络分区状态
+func (t *Miner) IsPartitioned() bool {
+	return t.partition.isPartitioned()
+}
@@ -0,0 +1,79 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/lib/logs"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func TestPartitionDetector(t *testing.T) {
+	now := time.Now()
+	p := newPartitionDetector(10 * time.Second)
+	p.lastSeen = now
+
+	if p.update(0, now.Add(5*time.Second)) || p.isPartitioned() {
+		t.Fatal("should not be partitioned before timeout")
+	}
+	if !p.update(0, now.Add(11*time.Second)) || !p.isPartitioned() {
+		t.Fatal("should be partitioned after timeout")
+	}
+	if p.update(0, now.Add(20*time.Second)) {
+		t.Fatal("partition state should not change again")
+	}
+	if !p.update(1, now.Add(21*time.Second)) || p.isPartitioned() {
+		t.Fatal("should recover once peers return")
+	}
+
+	// 未配置超时时间时不检测
+	disabled := newPartitionDetector(0)
+	if disabled.update(0, now.Add(time.Hour)) || disabled.isPartitioned() {
+		t.Fatal("disabled detector should never be partitioned")
+	}
+}
+
+// peerCountNet 只提供邻居数，查询邻居详细信息时失败
+type peerCountNet struct {
+	network.Network
+	count int
+}
+
+func (n *peerCountNet) PeerCount() int { return n.count }
+func (n *peerCountNet) PeerInfo() pb.PeerInfo {
+	panic("checkPartition should not query peer info")
+}
+
+func TestCheckPartition(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	cfg := xconf.GetDefEngineConf()
+	cfg.PartitionTimeout = 10 * time.Second
+	net := &peerCountNet{}
+	clock := &fixedClock{now: time.Now()}
+	m := &Miner{
+		log:       log,
+		clock:     clock,
+		partition: newPartitionDetector(cfg.PartitionTimeout),
+		ctx: &common.ChainCtx{
+			BCName: "xuper",
+			EngCtx: &common.EngineCtx{EngCfg: cfg, Net: net},
+		},
+	}
+	m.partition.lastSeen = clock.now
+
+	clock.now = clock.now.Add(11 * time.Second)
+	m.checkPartition()
+	if !m.IsPartitioned() {
+		t.Fatal("should be partitioned without peers")
+	}
+	net.count = 2
+	m.checkPartition()
+	if m.IsPartitioned() {
+		t.Fatal("should recover once peers are connected")
+	}
+}
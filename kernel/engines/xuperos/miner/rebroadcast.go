@@ -0,0 +1,88 @@
+package miner
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 同一笔交易两次重新广播之间的最少间隔轮数
+	txRebroadcastCooldownRounds = 3
+)
+
+// rebroadcastUnconfirmedTx 周期性重新广播本地未确认交易
+// 非矿工节点收到的交易如果没有扩散到矿工，可能一直无法上链，定期重播可以提高交易被打包的概率
+func (t *Miner) rebroadcastUnconfirmedTx() {
+	defer t.exitWG.Done()
+
+	interval := t.ctx.EngCtx.EngCfg.TxRebroadcastInterval
+
+	// 记录最近已重播过的交易，避免每一轮重复广播同一批交易
+	recent := cache.New(interval*txRebroadcastCooldownRounds, faultCacheGCInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// 重播间隔可能很长，按tickOnCalcBlock检查退出标志
+	exitTicker := time.NewTicker(tickOnCalcBlock)
+	defer exitTicker.Stop()
+	for !t.IsExit() {
+		select {
+		case <-ticker.C:
+			t.rebroadcastOnce(recent, interval)
+		case <-exitTicker.C:
+		}
+	}
+}
+
+// rebroadcastOnce 执行一轮重新广播
+func (t *Miner) rebroadcastOnce(recent *cache.Cache, interval time.Duration) {
+	batchSize := t.ctx.EngCtx.EngCfg.TxRebroadcastBatchSize
+	if batchSize <= 0 {
+		return
+	}
+
+	// dedup为true时会查询账本，过滤掉已经在主干上确认的交易
+	txs, err := t.ctx.State.GetUnconfirmedTx(true, -1)
+	if err != nil {
+		t.log.Warn("rebroadcast get unconfirmed tx failed", "err", err)
+		return
+	}
+
+	count := 0
+	now := t.clock.Now()
+	for _, tx := range txs {
+		if count >= batchSize {
+			break
+		}
+		if !needRebroadcast(tx, recent, now, interval) {
+			continue
+		}
+
+		msg := p2p.NewMessage(protos.XuperMessage_POSTTX, tx, p2p.WithBCName(t.ctx.BCName))
+		if err := t.ctx.EngCtx.Net.SendMessage(t.ctx, msg); err != nil {
+			t.log.Warn("rebroadcast unconfirmed tx failed", "txid", utils.F(tx.GetTxid()), "err", err)
+			continue
+		}
+		recent.SetDefault(string(tx.GetTxid()), true)
+		count++
+	}
+
+	if count > 0 {
+		t.log.Debug("rebroadcast unconfirmed tx", "count", count, "unconfirmed", len(txs))
+	}
+}
+
+// needRebroadcast 判断交易是否需要重新广播
+// 刚收到的交易已经随接收流程广播过，最近重播过的交易处于冷却期，都不需要再次广播
+func needRebroadcast(tx *lpb.Transaction, recent *cache.Cache, now time.Time, interval time.Duration) bool {
+	if now.Sub(time.Unix(0, tx.GetReceivedTimestamp())) < interval {
+		return false
+	}
+	_, ok := recent.Get(string(tx.GetTxid()))
+	return !ok
+}
@@ -0,0 +1,107 @@
+package miner
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// postTxNet 统计广播的交易消息
+type postTxNet struct {
+	network.Network
+	sent int32
+}
+
+func (n *postTxNet) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage, opts ...p2p.OptionFunc) error {
+	if msg.GetHeader().GetType() == pb.XuperMessage_POSTTX {
+		atomic.AddInt32(&n.sent, 1)
+	}
+	return nil
+}
+
+func newRebroadcastMiner(t *testing.T, interval time.Duration) (*Miner, *postTxNet, *lpb.Transaction) {
+	cfg := xconf.GetDefEngineConf()
+	cfg.TxRebroadcastInterval = interval
+	cfg.TxRebroadcastBatchSize = 10
+	l := newTestLedger(t)
+	s := newTestState(t, l)
+	log, _ := logs.NewLogger("", "miner")
+	net := &postTxNet{}
+	m := &Miner{
+		log:   log,
+		clock: realClock{},
+		ctx: &common.ChainCtx{
+			BCName: "xuper",
+			Ledger: l,
+			State:  s,
+			EngCtx: &common.EngineCtx{EngCfg: cfg, Net: net},
+		},
+	}
+
+	tx := &lpb.Transaction{Version: 1, Desc: []byte("rebroadcast"), Timestamp: time.Now().UnixNano()}
+	var err error
+	if tx.Txid, err = txhash.MakeTransactionID(tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DoTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	return m, net, tx
+}
+
+func TestRebroadcastOnce(t *testing.T) {
+	interval := time.Minute
+	m, net, tx := newRebroadcastMiner(t, interval)
+	recent := cache.New(interval*txRebroadcastCooldownRounds, faultCacheGCInterval)
+
+	// 刚收到的交易不重播
+	m.rebroadcastOnce(recent, interval)
+	if net.sent != 0 {
+		t.Fatalf("fresh tx should not be rebroadcast, sent %d", net.sent)
+	}
+
+	// 超过重播间隔后重播一次，冷却期内不再重播
+	tx.ReceivedTimestamp = time.Now().Add(-interval).UnixNano()
+	m.rebroadcastOnce(recent, interval)
+	m.rebroadcastOnce(recent, interval)
+	if net.sent != 1 {
+		t.Fatalf("expect tx rebroadcast once in cooldown, sent %d", net.sent)
+	}
+}
+
+func TestRebroadcastUnconfirmedTxExit(t *testing.T) {
+	interval := time.Hour
+	m, net, _ := newRebroadcastMiner(t, interval)
+
+	m.exitWG.Add(1)
+	go m.rebroadcastUnconfirmedTx()
+	// 等待循环进入select后停止，重播间隔很长时仍按tickOnCalcBlock及时退出
+	time.Sleep(50 * time.Millisecond)
+	m.isExit = true
+
+	done := make(chan struct{})
+	go func() {
+		m.exitWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * tickOnCalcBlock):
+		t.Fatal("rebroadcast loop should exit soon after stop")
+	}
+	if sent := atomic.LoadInt32(&net.sent); sent != 0 {
+		t.Fatalf("no tx should be rebroadcast before interval, sent %d", sent)
+	}
+}
@@ -0,0 +1,64 @@
+package miner
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// forceResyncTimeout 强制同步追到最长链的最长时间
+	forceResyncTimeout = syncOnStatusChangeTimeout
+	// forceResyncBackoff 同步失败后首次重试前的等待时间，之后每次翻倍，不超过forceResyncMaxBackoff
+	forceResyncBackoff    = 100 * time.Millisecond
+	forceResyncMaxBackoff = 5 * time.Second
+)
+
+// ForceResync 立即向验证人集合查询最长链并同步，用于网络分区恢复后运维手动触发追块。
+// 返回同步结束后的账本高度而不是查询到的最长链高度：同步成功时二者相同，失败时可据此判断追到了哪里。
+// 每轮同步与矿工循环互斥执行，轮次之间释放锁，并发调用合并为一次同步并共享结果
+func (t *Miner) ForceResync() (int64, error) {
+	return t.forceResync(forceResyncTimeout)
+}
+
+// forceResync 在timeout内循环同步直到最长链没有新的区块，同步失败后退避重试，等待期间不持有锁
+func (t *Miner) forceResync(timeout time.Duration) (int64, error) {
+	v, err, _ := t.resyncGroup.Do("resync", func() (interface{}, error) {
+		ctx := t.newRoundContext()
+		deadline := time.Now().Add(timeout)
+		backoff := forceResyncBackoff
+		var err error = newError(ErrSync, "forceResync", errors.New("resync timeout"))
+		for time.Now().Before(deadline) {
+			var size int
+			syncErr := t.withChainLock(func() error {
+				var err error
+				size, err = t.syncWithLongestChain(ctx)
+				return err
+			})
+			if errors.Is(syncErr, ErrSyncRedirected) {
+				// 出现了更高的同步目标，重新选取最长链
+				continue
+			}
+			if syncErr == nil {
+				if size == 0 {
+					err = nil
+					break
+				}
+				backoff = forceResyncBackoff
+				continue
+			}
+			t.warnThrottled(ctx.GetLog(), "force resync error", syncErr, "retryAfter", backoff)
+			if wait := time.Until(deadline); wait < backoff {
+				backoff = wait
+			}
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > forceResyncMaxBackoff {
+				backoff = forceResyncMaxBackoff
+			}
+		}
+		height := t.ctx.Ledger.GetMeta().TrunkHeight
+		ctx.GetLog().Info("force resync finish", "height", height, "err", err)
+		return height, err
+	})
+	height, _ := v.(int64)
+	return height, err
+}
@@ -0,0 +1,223 @@
+package miner
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/tdpos"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// validatorsConsensus 返回固定的验证人集合
+type validatorsConsensus struct {
+	consensus.PluggableConsensusInterface
+	consensus.ConsensusStatus
+	validators []string
+}
+
+func (c *validatorsConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error) {
+	return c, nil
+}
+
+func (c *validatorsConsensus) GetCurrentValidatorsInfo() []byte {
+	buf, _ := json.Marshal(&tdpos.ValidatorsInfo{Validators: c.validators})
+	return buf
+}
+
+// chainStatusNet 由status决定每次链状态查询的返回
+type chainStatusNet struct {
+	network.Network
+	queries int32
+	status  func(n int32) (*lpb.LedgerMeta, error)
+	caps    []string
+}
+
+func (n *chainStatusNet) PeerCapabilities(peerID string) []string {
+	return n.caps
+}
+
+func (n *chainStatusNet) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMessage,
+	opts ...p2p.OptionFunc) ([]*pb.XuperMessage, error) {
+	meta, err := n.status(atomic.AddInt32(&n.queries, 1))
+	if err != nil {
+		return nil, err
+	}
+	resp := p2p.NewMessage(pb.XuperMessage_GET_BLOCKCHAINSTATUS_RES, &xpb.ChainStatus{LedgerMeta: meta})
+	resp.Header.From = "peer"
+	return []*pb.XuperMessage{resp}, nil
+}
+
+func newResyncMiner(t *testing.T, net network.Network) *Miner {
+	log, _ := logs.NewLogger("", "miner")
+	cfg := xconf.GetDefEngineConf()
+	return &Miner{
+		log:               log,
+		clock:             realClock{},
+		faultBlockIdCache: cache.New(faultBlockIdCacheExpired, faultCacheGCInterval),
+		faultPeerIdCache:  cache.New(faultPeerIdCacheExpired, faultCacheGCInterval),
+		warnThrottle:      newWarnThrottle(cfg.MinerWarnThrottle),
+		incompatible:      newIncompatiblePeers(),
+		ctx: &common.ChainCtx{
+			BCName:    "xuper",
+			Ledger:    newTestLedger(t),
+			Consensus: &validatorsConsensus{validators: []string{"local", "peer"}},
+			Address:   &xaddress.Address{Address: "local"},
+			EngCtx:    &common.EngineCtx{EngCfg: cfg, Net: net},
+		},
+	}
+}
+
+func TestForceResync(t *testing.T) {
+	net := &chainStatusNet{}
+	m := newResyncMiner(t, net)
+	local := m.ctx.Ledger.GetMeta()
+
+	// 查询失败时在截止时间内重试，直到最长链没有新的区块
+	net.status = func(n int32) (*lpb.LedgerMeta, error) {
+		if n < 3 {
+			return nil, errors.New("no response")
+		}
+		return local, nil
+	}
+	height, err := m.forceResync(time.Second)
+	if err != nil || height != local.GetTrunkHeight() {
+		t.Fatalf("unexpected resync result: %d, %v", height, err)
+	}
+	if net.queries != 3 {
+		t.Fatalf("expect retry until synced, got %d queries", net.queries)
+	}
+
+	// 一直无法同步时到期返回错误
+	net.status = func(n int32) (*lpb.LedgerMeta, error) {
+		return nil, errors.New("no response")
+	}
+	begin := time.Now()
+	if _, err := m.forceResync(100 * time.Millisecond); err == nil {
+		t.Fatal("resync should fail after deadline")
+	}
+	if cost := time.Since(begin); cost > time.Second {
+		t.Fatalf("resync should stop at deadline, cost %v", cost)
+	}
+}
+
+func TestForceResyncBackoff(t *testing.T) {
+	net := &chainStatusNet{}
+	m := newResyncMiner(t, net)
+	net.status = func(n int32) (*lpb.LedgerMeta, error) {
+		return nil, errors.New("no response")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.forceResync(500 * time.Millisecond)
+	}()
+	// 失败轮次之间释放锁，矿工循环不会被整个同步过程阻塞
+	time.Sleep(50 * time.Millisecond)
+	begin := time.Now()
+	m.withChainLock(func() error { return nil })
+	if waited := time.Since(begin); waited >= 100*time.Millisecond {
+		t.Fatalf("chain lock should be released between rounds, waited %s", waited)
+	}
+	<-done
+
+	// 100ms起翻倍退避，500ms内最多查询4轮
+	if queries := atomic.LoadInt32(&net.queries); queries < 2 || queries > 4 {
+		t.Fatalf("expect failed rounds backed off, got %d queries", queries)
+	}
+}
+
+func TestForceResyncCoalesce(t *testing.T) {
+	net := &chainStatusNet{}
+	m := newResyncMiner(t, net)
+	local := m.ctx.Ledger.GetMeta()
+
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	net.status = func(n int32) (*lpb.LedgerMeta, error) {
+		if n == 1 {
+			close(arrived)
+			<-release
+		}
+		return local, nil
+	}
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	resync := func() {
+		defer wg.Done()
+		_, err := m.forceResync(time.Second)
+		errs <- err
+	}
+	wg.Add(1)
+	go resync()
+	<-arrived
+	// 第一次同步进行中发起的请求合并到该次同步
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go resync()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if net.queries != 1 {
+		t.Fatalf("concurrent resync should be coalesced, got %d queries", net.queries)
+	}
+}
+
+func TestGetMaxBlockHeightEscalateOnError(t *testing.T) {
+	net := &chainStatusNet{}
+	m := newResyncMiner(t, net)
+	m.ctx.Consensus = &validatorsConsensus{validators: []string{"local", "peer1", "peer2"}}
+	m.ctx.EngCtx.EngCfg.SyncStatusFanout = 1
+	local := m.ctx.Ledger.GetMeta()
+	local.TrunkHeight, local.TipBlockid = 5, []byte("tip")
+
+	// 先询问的验证人超时，扩大范围后从其余验证人拿到链状态
+	net.status = func(n int32) (*lpb.LedgerMeta, error) {
+		if n == 1 {
+			return nil, errors.New("timeout")
+		}
+		return local, nil
+	}
+	ctx := m.newRoundContext()
+	m.ctx.EngCtx.EngCfg.SyncStatusEscalate = true
+	peer, height, _, err := m.getMaxBlockHeight(ctx)
+	if err != nil || peer != "peer" || height != local.GetTrunkHeight() {
+		t.Fatalf("expect escalated status, got %s %d %v", peer, height, err)
+	}
+	if net.queries != 2 {
+		t.Fatalf("expect escalation after error, got %d queries", net.queries)
+	}
+
+	// 不扩大范围时返回查询错误
+	net.queries = 0
+	m.ctx.EngCtx.EngCfg.SyncStatusEscalate = false
+	if _, _, _, err := m.getMaxBlockHeight(ctx); err == nil {
+		t.Fatal("expect error without escalation")
+	}
+}
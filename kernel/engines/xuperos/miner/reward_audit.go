@@ -0,0 +1,111 @@
+package miner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// 出块奖励审计日志
+// 开启RewardAuditLog后每出一个块记录一条奖励明细，各条记录按出块顺序用哈希串联，
+// 删除或修改任意一条都会导致后续记录的PrevHash对不上，便于事后核对链上奖励
+
+// RewardAuditEntry 单个区块的奖励明细
+type RewardAuditEntry struct {
+	Height  int64  `json:"height"`
+	BlockId string `json:"blockId"`
+	Miner   string `json:"miner"`
+	// 出块奖励，即coinbase交易的输出总额
+	Award string `json:"award"`
+	// 区块内交易支付给矿工的手续费总额
+	Fee string `json:"fee"`
+	// 上一条记录的哈希，节点启动后的第一条为空
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// blockAward 返回区块的出块奖励，第一笔交易为奖励交易
+func blockAward(block *lpb.InternalBlock) *big.Int {
+	award := big.NewInt(0)
+	if len(block.GetTransactions()) > 0 && block.Transactions[0].GetCoinbase() {
+		for _, output := range block.Transactions[0].GetTxOutputs() {
+			award.Add(award, new(big.Int).SetBytes(output.GetAmount()))
+		}
+	}
+	return award
+}
+
+// blockFee 返回区块内交易支付给矿工的手续费总额
+func blockFee(block *lpb.InternalBlock) *big.Int {
+	fee := big.NewInt(0)
+	for _, tx := range block.GetTransactions() {
+		if tx.GetCoinbase() {
+			continue
+		}
+		for _, output := range tx.GetTxOutputs() {
+			if string(output.GetToAddr()) == lpb.FeePlaceholder {
+				fee.Add(fee, new(big.Int).SetBytes(output.GetAmount()))
+			}
+		}
+	}
+	return fee
+}
+
+// rewardAuditor 生成哈希串联的奖励审计记录
+type rewardAuditor struct {
+	mutex    sync.Mutex
+	prevHash string
+}
+
+func (a *rewardAuditor) record(block *lpb.InternalBlock) *RewardAuditEntry {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	entry := &RewardAuditEntry{
+		Height:   block.GetHeight(),
+		BlockId:  hex.EncodeToString(block.GetBlockid()),
+		Miner:    string(block.GetProposer()),
+		Award:    blockAward(block).String(),
+		Fee:      blockFee(block).String(),
+		PrevHash: a.prevHash,
+	}
+	entry.Hash = rewardAuditHash(entry)
+	a.prevHash = entry.Hash
+	return entry
+}
+
+// rewardAuditHash 计算不含Hash字段的记录哈希
+func rewardAuditHash(entry *RewardAuditEntry) string {
+	e := *entry
+	e.Hash = ""
+	data, _ := json.Marshal(&e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyRewardAudit 校验一组按顺序排列的审计记录没有被修改、删除或插入
+func VerifyRewardAudit(entries []*RewardAuditEntry) bool {
+	for i, entry := range entries {
+		if rewardAuditHash(entry) != entry.Hash {
+			return false
+		}
+		if i > 0 && entry.PrevHash != entries[i-1].Hash {
+			return false
+		}
+	}
+	return true
+}
+
+// auditReward 开启审计时记录本节点所出区块的奖励明细
+func (t *Miner) auditReward(block *lpb.InternalBlock) {
+	if !t.ctx.EngCtx.EngCfg.RewardAuditLog {
+		return
+	}
+	entry := t.rewardAuditor.record(block)
+	t.log.Info("reward audit", "height", entry.Height, "blockId", entry.BlockId, "miner", entry.Miner,
+		"award", entry.Award, "fee", entry.Fee, "prevHash", entry.PrevHash, "hash", entry.Hash)
+}
@@ -0,0 +1,54 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestRewardAudit(t *testing.T) {
+	newBlock := func(height int64) *lpb.InternalBlock {
+		return &lpb.InternalBlock{
+			Height:   height,
+			Blockid:  []byte{byte(height)},
+			Proposer: []byte("miner"),
+			Transactions: []*lpb.Transaction{
+				{
+					Coinbase:  true,
+					TxOutputs: []*protos.TxOutput{{ToAddr: []byte("miner"), Amount: big.NewInt(1000).Bytes()}},
+				},
+				{
+					TxOutputs: []*protos.TxOutput{
+						{ToAddr: []byte("bob"), Amount: big.NewInt(500).Bytes()},
+						{ToAddr: []byte(lpb.FeePlaceholder), Amount: big.NewInt(7).Bytes()},
+					},
+				},
+			},
+		}
+	}
+
+	auditor := &rewardAuditor{}
+	var entries []*RewardAuditEntry
+	for h := int64(1); h <= 3; h++ {
+		entries = append(entries, auditor.record(newBlock(h)))
+	}
+	first := entries[0]
+	if first.Award != "1000" || first.Fee != "7" || first.Miner != "miner" || first.PrevHash != "" {
+		t.Fatalf("unexpected entry %+v", first)
+	}
+	if !VerifyRewardAudit(entries) {
+		t.Fatal("audit chain should be valid")
+	}
+
+	// 修改或删除任意一条记录都能被发现
+	tampered := *entries[1]
+	tampered.Award = "2000"
+	if VerifyRewardAudit([]*RewardAuditEntry{entries[0], &tampered, entries[2]}) {
+		t.Fatal("modified entry not detected")
+	}
+	if VerifyRewardAudit([]*RewardAuditEntry{entries[0], entries[2]}) {
+		t.Fatal("removed entry not detected")
+	}
+}
@@ -0,0 +1,77 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+var (
+	ErrMinerKeyInvalid      = errors.New("miner key invalid")
+	ErrMinerKeyMismatch     = errors.New("miner key not match address")
+	ErrMinerKeyRotateDenied = errors.New("miner key rotation not supported by consensus")
+)
+
+// addressCachedConsensus 构造时保存矿工地址副本的共识，tdpos/xpoa的选举和chained-bft
+// 都使用该副本判断本节点身份，替换矿工地址后共识仍按旧地址出块和投票，因此不允许替换
+var addressCachedConsensus = map[string]bool{
+	"tdpos": true,
+	"xpos":  true,
+	"poa":   true,
+	"xpoa":  true,
+}
+
+// RotateMinerKey 原子地替换出块使用的地址和密钥
+// 替换与出块循环互斥，只会发生在两轮step之间，不会出现用旧地址打包、新密钥签名的区块。
+// 只支持直接读取矿工地址对象的共识(如single、pow)，签名时通过Snapshot读取一致的副本；
+// tdpos/xpoa保存了地址副本，返回ErrMinerKeyRotateDenied。替换后网络层重新宣告本节点账户，
+// 使其他节点按新地址路由发给验证人的消息
+func (t *Miner) RotateMinerKey(newAddr *xaddress.Address) error {
+	if err := checkMinerKey(t.ctx.Crypto, newAddr); err != nil {
+		t.log.Warn("rotate miner key failed", "err", err)
+		return err
+	}
+
+	t.stepMutex.Lock()
+	defer t.stepMutex.Unlock()
+
+	status, err := t.ctx.Consensus.GetConsensusStatus()
+	if err != nil {
+		t.log.Warn("rotate miner key get consensus status failed", "err", err)
+		return err
+	}
+	if name := status.GetConsensusName(); addressCachedConsensus[name] {
+		t.log.Warn("rotate miner key denied", "consensus", name)
+		return fmt.Errorf("%w: %s", ErrMinerKeyRotateDenied, name)
+	}
+
+	oldAddr := t.ctx.Address.Address
+	t.ctx.Address.Rotate(newAddr)
+	if t.ctx.EngCtx != nil && t.ctx.EngCtx.Net != nil {
+		t.ctx.EngCtx.Net.SetAccount(newAddr.Address)
+	}
+	t.log.Warn("miner key rotated", "oldAddress", oldAddr, "newAddress", newAddr.Address)
+	return nil
+}
+
+// checkMinerKey 校验私钥、公钥和地址三者是否一致
+func checkMinerKey(crypto cryptoBase.CryptoClient, addr *xaddress.Address) error {
+	if addr == nil || addr.Address == "" || addr.PrivateKey == nil || addr.PublicKey == nil {
+		return ErrMinerKeyInvalid
+	}
+	pub := &addr.PrivateKey.PublicKey
+	if pub.X == nil || addr.PublicKey.X == nil ||
+		pub.X.Cmp(addr.PublicKey.X) != 0 || pub.Y.Cmp(addr.PublicKey.Y) != 0 {
+		return fmt.Errorf("%w: private key not match public key", ErrMinerKeyMismatch)
+	}
+	derived, err := crypto.GetAddressFromPublicKey(addr.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMinerKeyInvalid, err)
+	}
+	if derived != addr.Address {
+		return fmt.Errorf("%w: expect %s, got %s", ErrMinerKeyMismatch, derived, addr.Address)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package miner
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cbftCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	kmock "github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestCheckMinerKey(t *testing.T) {
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	if err := checkMinerKey(crypto, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkMinerKey(crypto, nil); !errors.Is(err, ErrMinerKeyInvalid) {
+		t.Fatalf("expect invalid, got %v", err)
+	}
+
+	wrongAddr := *addr
+	wrongAddr.Address = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	if err := checkMinerKey(crypto, &wrongAddr); !errors.Is(err, ErrMinerKeyMismatch) {
+		t.Fatalf("expect mismatch, got %v", err)
+	}
+
+	wrongPub := *addr
+	wrongPub.PublicKey = &ecdsa.PublicKey{
+		Curve: addr.PublicKey.Curve,
+		X:     addr.PublicKey.Y,
+		Y:     addr.PublicKey.X,
+	}
+	if err := checkMinerKey(crypto, &wrongPub); !errors.Is(err, ErrMinerKeyMismatch) {
+		t.Fatalf("expect mismatch, got %v", err)
+	}
+}
+
+type accountNet struct {
+	network.Network
+	mutex   sync.Mutex
+	account string
+}
+
+func (n *accountNet) SetAccount(account string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.account = account
+}
+
+func newTestAddress(t *testing.T, crypto cryptoBase.CryptoClient) *xaddress.Address {
+	seed, err := crypto.GenerateEntropy(248)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := crypto.GenerateKeyBySeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubStr, err := crypto.GetEcdsaPublicKeyJsonFormatStr(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := crypto.GetAddressFromPublicKey(&sk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &xaddress.Address{Address: addr, PrivateKey: sk, PublicKey: &sk.PublicKey, PublicKeyStr: pubStr}
+}
+
+// namedConsensus 只返回共识名称
+type namedConsensus struct {
+	consensus.PluggableConsensusInterface
+	consensus.ConsensusStatus
+	name string
+}
+
+func (c *namedConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error) {
+	return c, nil
+}
+
+func (c *namedConsensus) GetConsensusName() string {
+	return c.name
+}
+
+func TestRotateMinerKey(t *testing.T) {
+	kmock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldAddr := *(*xaddress.Address)(cAddr)
+	newAddr := newTestAddress(t, crypto)
+	shared := oldAddr
+	net := &accountNet{}
+	miner := &Miner{
+		log: log,
+		ctx: &common.ChainCtx{
+			EngCtx:    &common.EngineCtx{Net: net},
+			Crypto:    crypto,
+			Address:   &shared,
+			Consensus: &namedConsensus{name: "single"},
+		},
+	}
+
+	// 共识与矿工共享同一地址对象，替换过程中的签名身份必须一致
+	signer := cbftCrypto.NewCBFTCrypto((*cctx.Address)(&shared), crypto)
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+	signed := make(chan struct{}, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			sign, err := signer.SignVoteMsg([]byte("vote"))
+			if err == nil {
+				var ok bool
+				ok, err = signer.VerifyVoteMsgSign(sign, []byte("vote"))
+				if err == nil && !ok {
+					err = errors.New("mixed identity in vote sign")
+				}
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case signed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		// 每次替换前等待一次签名，保证签名与替换交错进行
+		select {
+		case <-signed:
+		case err := <-errCh:
+			t.Fatal(err)
+		}
+		next := newAddr
+		if i%2 == 1 {
+			next = &oldAddr
+		}
+		if err := miner.RotateMinerKey(next); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	select {
+	case err := <-errCh:
+		t.Fatal(err)
+	default:
+	}
+
+	if shared.Snapshot().Address != oldAddr.Address || net.account != oldAddr.Address {
+		t.Fatalf("unexpected address after rotate, shared:%s, net:%s", shared.Address, net.account)
+	}
+	wrong := *newAddr
+	wrong.Address = oldAddr.Address
+	if err := miner.RotateMinerKey(&wrong); !errors.Is(err, ErrMinerKeyMismatch) {
+		t.Fatalf("expect mismatch, got %v", err)
+	}
+}
+
+func TestRotateMinerKeyBFT(t *testing.T) {
+	kmock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldAddr := *(*xaddress.Address)(cAddr)
+	newAddr := newTestAddress(t, crypto)
+	for _, name := range []string{"tdpos", "xpos", "poa", "xpoa"} {
+		shared := oldAddr
+		net := &accountNet{account: oldAddr.Address}
+		miner := &Miner{
+			log: log,
+			ctx: &common.ChainCtx{
+				EngCtx:    &common.EngineCtx{Net: net},
+				Crypto:    crypto,
+				Address:   &shared,
+				Consensus: &namedConsensus{name: name},
+			},
+		}
+		// 共识保存了地址副本，拒绝替换且矿工地址和网络账户保持不变
+		if err := miner.RotateMinerKey(newAddr); !errors.Is(err, ErrMinerKeyRotateDenied) {
+			t.Fatalf("%s: expect rotate denied, got %v", name, err)
+		}
+		if shared.Address != oldAddr.Address || net.account != oldAddr.Address {
+			t.Fatalf("%s: address should not change, shared:%s, net:%s", name, shared.Address, net.account)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package miner
+
+import (
+	"sync"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+)
+
+// roundTimings 保留最近size轮出块的耗时，size不大于0时不记录
+type roundTimings struct {
+	mutex  sync.Mutex
+	size   int
+	rounds []common.RoundTiming
+	next   int
+}
+
+func newRoundTimings(size int) *roundTimings {
+	return &roundTimings{size: size}
+}
+
+// add 记录一轮出块耗时，超过size时覆盖最早的记录
+func (r *roundTimings) add(round common.RoundTiming) {
+	if r.size <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(r.rounds) < r.size {
+		r.rounds = append(r.rounds, round)
+		return
+	}
+	r.rounds[r.next] = round
+	r.next = (r.next + 1) % r.size
+}
+
+// list 按时间先后返回记录的出块耗时
+func (r *roundTimings) list() []common.RoundTiming {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rounds := make([]common.RoundTiming, 0, len(r.rounds))
+	rounds = append(rounds, r.rounds[r.next:]...)
+	return append(rounds, r.rounds[:r.next]...)
+}
+
+// RecentRoundTimings 返回最近若干轮出块的各阶段耗时，由旧到新排列，
+// 保留的轮数由MiningTimingHistory配置
+func (t *Miner) RecentRoundTimings() []common.RoundTiming {
+	return t.roundTimings.list()
+}
@@ -0,0 +1,48 @@
+package miner
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+)
+
+func TestRoundTimings(t *testing.T) {
+	r := newRoundTimings(3)
+	for height := int64(1); height <= 5; height++ {
+		r.add(common.RoundTiming{Height: height})
+	}
+	rounds := r.list()
+	if len(rounds) != 3 {
+		t.Fatalf("expect 3 rounds, got %d", len(rounds))
+	}
+	for i, round := range rounds {
+		if round.Height != int64(i+3) {
+			t.Fatalf("expect oldest first from height 3, got %v", rounds)
+		}
+	}
+
+	disabled := newRoundTimings(0)
+	disabled.add(common.RoundTiming{Height: 1})
+	if len(disabled.list()) != 0 {
+		t.Fatal("history disabled, should not keep rounds")
+	}
+
+	// 并发记录和读取
+	r = newRoundTimings(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := int64(0); height < 100; height++ {
+				r.add(common.RoundTiming{Height: height})
+				r.list()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(r.list()) != 8 {
+		t.Fatalf("expect 8 rounds, got %d", len(r.list()))
+	}
+}
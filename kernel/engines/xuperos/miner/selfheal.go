@@ -0,0 +1,92 @@
+package miner
+
+import (
+	"bytes"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// maxWalkRecoveryDepth 深度恢复时最多回滚的区块数，配置过大时截断，避免从创世块重放整条链
+const maxWalkRecoveryDepth = 1000
+
+// walkHealer 统计状态机同步账本连续失败的次数，达到阈值后触发一次深度恢复
+type walkHealer struct {
+	threshold int
+	failures  int
+}
+
+func newWalkHealer(threshold int) *walkHealer {
+	return &walkHealer{
+		threshold: threshold,
+	}
+}
+
+// fail 记录一次失败，返回是否需要进行深度恢复，触发后重新计数
+func (w *walkHealer) fail() bool {
+	if w.threshold <= 0 {
+		return false
+	}
+	w.failures++
+	if w.failures < w.threshold {
+		return false
+	}
+	w.failures = 0
+	return true
+}
+
+func (w *walkHealer) reset() {
+	w.failures = 0
+}
+
+// recoveryHeight 计算深度恢复时状态机回滚到的主干高度，depth不大于0时不回滚
+func recoveryHeight(tipHeight, depth int64) int64 {
+	if depth <= 0 {
+		return tipHeight
+	}
+	if depth > maxWalkRecoveryDepth {
+		depth = maxWalkRecoveryDepth
+	}
+	if depth > tipHeight {
+		return 0
+	}
+	return tipHeight - depth
+}
+
+// walkState 把状态机同步到账本最新区块
+// 连续失败达到阈值后，先把状态机回滚到主干上较早的区块，再重放到账本最新区块，
+// 用于从暂时性的状态损坏中恢复，恢复失败时返回原始错误，等待下一次触发
+func (t *Miner) walkState(ctx xctx.XContext, ledgerTipId []byte) error {
+	err := t.ctx.State.Walk(ledgerTipId, false)
+	if err == nil {
+		t.walkHealer.reset()
+		return nil
+	}
+	if !t.walkHealer.fail() {
+		return err
+	}
+
+	ledgerTipHeight := t.ctx.Ledger.GetMeta().TrunkHeight
+	height := recoveryHeight(ledgerTipHeight, t.ctx.EngCtx.EngCfg.WalkRecoveryDepth)
+	ctx.GetLog().Warn("state walk failed repeatedly, try to recover", "err", err,
+		"ledgerTipHeight", ledgerTipHeight, "recoveryHeight", height)
+	target, qerr := t.ctx.Ledger.QueryBlockHeaderByHeight(height)
+	if qerr != nil {
+		ctx.GetLog().Error("state recovery query block failed", "height", height, "err", qerr)
+		return err
+	}
+	if !bytes.Equal(target.GetBlockid(), t.ctx.State.GetLatestBlockid()) {
+		if werr := t.ctx.State.Walk(target.GetBlockid(), false); werr != nil {
+			ctx.GetLog().Error("state recovery rollback failed", "height", height,
+				"blockId", utils.F(target.GetBlockid()), "err", werr)
+			return err
+		}
+	}
+	if werr := t.ctx.State.Walk(ledgerTipId, false); werr != nil {
+		ctx.GetLog().Error("state recovery replay failed", "height", height,
+			"ledgerTipId", utils.F(ledgerTipId), "err", werr)
+		return err
+	}
+	ctx.GetLog().Info("state recovered", "recoveryHeight", height, "ledgerTipHeight", ledgerTipHeight)
+	return nil
+}
@@ -0,0 +1,134 @@
+package miner
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestWalkHealer(t *testing.T) {
+	w := newWalkHealer(3)
+	if w.fail() || w.fail() {
+		t.Fatal("should not recover before threshold")
+	}
+	if !w.fail() {
+		t.Fatal("should recover at threshold")
+	}
+	// 触发后重新计数
+	if w.fail() {
+		t.Fatal("failures should be reset after recovery")
+	}
+	w.reset()
+	if w.fail() || w.fail() {
+		t.Fatal("failures should be reset after success")
+	}
+
+	disabled := newWalkHealer(0)
+	for i := 0; i < 10; i++ {
+		if disabled.fail() {
+			t.Fatal("disabled healer should never recover")
+		}
+	}
+}
+
+func TestRecoveryHeight(t *testing.T) {
+	cases := []struct {
+		tip, depth, expect int64
+	}{
+		{100, 10, 90},
+		{100, 100, 0},
+		{5, 10, 0},
+		{100, 0, 100},
+		{100, -1, 100},
+		{5000, 100000, 5000 - maxWalkRecoveryDepth},
+	}
+	for _, c := range cases {
+		if h := recoveryHeight(c.tip, c.depth); h != c.expect {
+			t.Fatalf("recoveryHeight(%d, %d) expect %d, got %d", c.tip, c.depth, c.expect, h)
+		}
+	}
+}
+
+func TestWalkStateRecovery(t *testing.T) {
+	l := newTestLedger(t)
+	s := newTestState(t, l)
+	_, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	prev, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trunk []*lpb.InternalBlock
+	for i := int64(1); i <= 4; i++ {
+		block := newTestBlock(t, l, prev, addr, i)
+		if status := l.ConfirmBlock(block, false); !status.Succ {
+			t.Fatalf("confirm block at height %d failed", block.Height)
+		}
+		trunk = append(trunk, block)
+		prev = block
+	}
+	if err := s.Walk(prev.Blockid, false); err != nil {
+		t.Fatal(err)
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	m := &Miner{
+		log:        log,
+		walkHealer: newWalkHealer(2),
+		ctx: &common.ChainCtx{
+			BCName: "xuper",
+			Ledger: l,
+			State:  s,
+			EngCtx: &common.EngineCtx{EngCfg: &engconf.EngineConf{WalkRecoveryDepth: 2}},
+		},
+	}
+	ctx := m.newRoundContext()
+
+	// 未达到阈值时直接返回错误，不回滚状态机
+	missing := []byte("missing block")
+	if err := m.walkState(ctx, missing); err == nil {
+		t.Fatal("expect walk error")
+	}
+	if !bytes.Equal(s.GetLatestBlockid(), trunk[3].Blockid) {
+		t.Fatal("state should stay on tip before threshold")
+	}
+
+	// 达到阈值后回滚到主干上较早的区块再重放，重放失败时返回原始错误，状态机停在回滚的位置
+	if err := m.walkState(ctx, missing); err == nil {
+		t.Fatal("expect original walk error after failed recovery")
+	}
+	if !bytes.Equal(s.GetLatestBlockid(), trunk[1].Blockid) {
+		t.Fatal("state should be rolled back to recovery height")
+	}
+
+	// 目标可达时从回滚位置重放到账本最新区块，成功后清零失败计数
+	if err := m.walkState(ctx, trunk[3].Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.GetLatestBlockid(), trunk[3].Blockid) {
+		t.Fatal("state should be replayed to ledger tip")
+	}
+	if m.walkHealer.failures != 0 {
+		t.Fatalf("failures should be reset after success, got %d", m.walkHealer.failures)
+	}
+
+	// 默认阈值为0，不会触发恢复
+	m.walkHealer = newWalkHealer(engconf.GetDefEngineConf().WalkFailureThreshold)
+	for i := 0; i < 5; i++ {
+		if err := m.walkState(ctx, missing); err == nil {
+			t.Fatal("expect walk error")
+		}
+	}
+	if !bytes.Equal(s.GetLatestBlockid(), trunk[3].Blockid) {
+		t.Fatal("disabled recovery should not roll back state")
+	}
+}
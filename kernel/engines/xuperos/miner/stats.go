@@ -0,0 +1,80 @@
+package miner
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// MinerMetrics 本节点自启动以来的出块统计
+type MinerMetrics struct {
+	// BlocksMined 本节点生产并确认的区块数
+	BlocksMined int64
+	// TxsPacked 打包的交易总数，包含奖励交易和timer交易
+	TxsPacked int64
+	// TotalAward 获得的出块奖励总额
+	TotalAward *big.Int
+	// AvgPackTime 平均打包耗时
+	AvgPackTime time.Duration
+	// LastBlockTime 最近一次出块的区块时间戳，未出块时为零值
+	LastBlockTime time.Time
+}
+
+// minerStats 在出块过程中增量维护统计数据，不从账本重新计算
+type minerStats struct {
+	mutex         sync.Mutex
+	blocksMined   int64
+	txsPacked     int64
+	totalAward    *big.Int
+	packCount     int64
+	packTime      time.Duration
+	lastBlockTime time.Time
+}
+
+func newMinerStats() *minerStats {
+	return &minerStats{
+		totalAward: big.NewInt(0),
+	}
+}
+
+// observePack 记录一次打包耗时
+func (s *minerStats) observePack(cost time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.packCount++
+	s.packTime += cost
+}
+
+// observeBlock 记录一个本节点确认的区块，第一笔交易为奖励交易
+func (s *minerStats) observeBlock(block *lpb.InternalBlock) {
+	award := blockAward(block)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.blocksMined++
+	s.txsPacked += int64(block.GetTxCount())
+	s.totalAward.Add(s.totalAward, award)
+	s.lastBlockTime = time.Unix(0, block.GetTimestamp())
+}
+
+func (s *minerStats) snapshot() MinerMetrics {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	m := MinerMetrics{
+		BlocksMined:   s.blocksMined,
+		TxsPacked:     s.txsPacked,
+		TotalAward:    new(big.Int).Set(s.totalAward),
+		LastBlockTime: s.lastBlockTime,
+	}
+	if s.packCount > 0 {
+		m.AvgPackTime = s.packTime / time.Duration(s.packCount)
+	}
+	return m
+}
+
+// Metrics 返回本节点出块统计的快照
+func (t *Miner) Metrics() MinerMetrics {
+	return t.stats.snapshot()
+}
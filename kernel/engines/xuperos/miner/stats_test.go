@@ -0,0 +1,64 @@
+package miner
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestMinerStats(t *testing.T) {
+	s := newMinerStats()
+	m := s.snapshot()
+	if m.BlocksMined != 0 || m.AvgPackTime != 0 || m.TotalAward.Sign() != 0 || !m.LastBlockTime.IsZero() {
+		t.Fatalf("unexpected initial metrics %+v", m)
+	}
+
+	newBlock := func(ts int64, award int64, txCount int32) *lpb.InternalBlock {
+		return &lpb.InternalBlock{
+			Timestamp: ts,
+			TxCount:   txCount,
+			Transactions: []*lpb.Transaction{
+				{
+					Coinbase:  true,
+					TxOutputs: []*protos.TxOutput{{Amount: big.NewInt(award).Bytes()}},
+				},
+			},
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.observePack(time.Duration(i) * time.Millisecond)
+			s.observeBlock(newBlock(int64(i), 100, 3))
+		}(i)
+	}
+	wg.Wait()
+	s.observeBlock(newBlock(time.Unix(100, 0).UnixNano(), 50, 1))
+
+	m = s.snapshot()
+	if m.BlocksMined != 11 || m.TxsPacked != 31 {
+		t.Fatalf("unexpected count %d %d", m.BlocksMined, m.TxsPacked)
+	}
+	if m.TotalAward.Cmp(big.NewInt(1050)) != 0 {
+		t.Fatalf("unexpected award %s", m.TotalAward)
+	}
+	if m.AvgPackTime != 5500*time.Microsecond {
+		t.Fatalf("unexpected avg pack time %s", m.AvgPackTime)
+	}
+	if !m.LastBlockTime.Equal(time.Unix(100, 0)) {
+		t.Fatalf("unexpected last block time %s", m.LastBlockTime)
+	}
+
+	// 快照与内部状态互不影响
+	m.TotalAward.SetInt64(0)
+	if s.snapshot().TotalAward.Cmp(big.NewInt(1050)) != 0 {
+		t.Fatal("snapshot should not share award")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sort"
 	"time"
 
@@ -35,16 +36,40 @@ const (
 var (
 	ErrHashMissMatch = errors.New("hash miss match")
 	ErrNoNewBlock    = errors.New("no new block found")
+	ErrBlockChainGap = errors.New("blocks not form a chain")
 )
 
-func traceSync() func(string) {
-	last := time.Now()
+// traceSync 按注入的时钟统计同步各阶段耗时
+func (t *Miner) traceSync() func(string) {
+	last := t.clock.Now()
 	return func(action string) {
-		metrics.CallMethodHistogram.WithLabelValues("sync", action).Observe(time.Since(last).Seconds())
-		last = time.Now()
+		now := t.clock.Now()
+		metrics.CallMethodHistogram.WithLabelValues("sync", action).Observe(now.Sub(last).Seconds())
+		last = now
 	}
 }
 
+// blockPropagationLatency 计算区块从出块到本地确认的耗时，
+// 对端时钟快于本地时结果会为负，此时截断为0并返回skew=true
+func blockPropagationLatency(block *lpb.InternalBlock, now time.Time) (latency time.Duration, skew bool) {
+	latency = now.Sub(time.Unix(0, block.GetTimestamp()))
+	if latency < 0 {
+		return 0, true
+	}
+	return latency, false
+}
+
+// observeBlockPropagation 记录同步到的区块的传播耗时，同时作为本地时钟偏差检测的样本
+func (t *Miner) observeBlockPropagation(block *lpb.InternalBlock) {
+	now := t.clock.Now()
+	t.clockSkew.observe(string(block.GetProposer()), time.Unix(0, block.GetTimestamp()), now)
+	latency, skew := blockPropagationLatency(block, now)
+	if skew {
+		metrics.LedgerBlockClockSkewCounter.WithLabelValues(t.ctx.BCName).Inc()
+	}
+	metrics.LedgerBlockPropagationHistogram.WithLabelValues(t.ctx.BCName).Observe(latency.Seconds())
+}
+
 // 获取验证人（潜在的矿工节点）列表，除了自己
 func (t *Miner) getValidators(excludeAddr string) ([]string, error) {
 	status, err := t.ctx.Consensus.GetConsensusStatus()
@@ -72,9 +97,43 @@ func (t *Miner) getValidators(excludeAddr string) ([]string, error) {
 	return validators, nil
 }
 
+// pickValidators 随机选取fanout个验证人，返回选中的和剩余的验证人，fanout不大于0时选中全部
+func pickValidators(validators []string, fanout int) ([]string, []string) {
+	if fanout <= 0 || fanout >= len(validators) {
+		return validators, nil
+	}
+	shuffled := make([]string, len(validators))
+	copy(shuffled, validators)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:fanout], shuffled[fanout:]
+}
+
+// queryChainStatus 向指定验证人查询链状态
+func (t *Miner) queryChainStatus(validators []string) ([]*protos.XuperMessage, error) {
+	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCKCHAINSTATUS, nil, p2p.WithBCName(t.ctx.BCName))
+	return t.ctx.EngCtx.Net.SendMessageWithResponse(t.ctx, msg,
+		chainStatusOptions(validators, t.ctx.EngCtx.EngCfg.SyncStatusTimeout)...)
+}
+
+// chainStatusOptions 查询链状态的发送选项
+// 收到过半验证人的返回即可选出最长链，不必等待最慢的节点；设置了超时时间时到期后按已收到的返回处理
+func chainStatusOptions(validators []string, timeout time.Duration) []p2p.OptionFunc {
+	opts := []p2p.OptionFunc{
+		p2p.WithAccounts(validators),
+		p2p.WithMinResponses(len(validators)/2 + 1),
+	}
+	if timeout > 0 {
+		opts = append(opts, p2p.WithResponseDeadline(timeout))
+	}
+	return opts
+}
+
 // getMaxBlockHeight 从验证人列表里面获取当前最大的区块高度以及地址
 func (t *Miner) getMaxBlockHeight(ctx xctx.XContext) (string, int64, []byte, error) {
-	validators, err := t.getValidators(t.ctx.Address.Address)
+	addr := t.ctx.Address.Snapshot()
+	validators, err := t.getValidators(addr.Address)
 	if err != nil {
 		return "", 0, nil, err
 	}
@@ -82,29 +141,50 @@ func (t *Miner) getMaxBlockHeight(ctx xctx.XContext) (string, int64, []byte, err
 	if len(validators) == 0 {
 		return "", 0, nil, nil
 	}
-	opt := []p2p.MessageOption{
-		p2p.WithBCName(t.ctx.BCName),
-		// p2p.WithLogId(ctx.GetLog().GetLogId()),
-	}
-	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCKCHAINSTATUS, nil, opt...)
-	ctx.GetLog().Debug("getMaxBlockHeight", "validators", validators)
-	responses, err := t.ctx.EngCtx.Net.SendMessageWithResponse(t.ctx, msg, p2p.WithAccounts(validators))
+	// 大规模网络中只随机询问部分验证人，返回不足法定数量时再按配置扩大范围
+	selected, rest := pickValidators(validators, t.ctx.EngCtx.EngCfg.SyncStatusFanout)
+	ctx.GetLog().Debug("getMaxBlockHeight", "validators", selected)
+	// 部分验证人超时或出错时保留已收到的返回，并照常扩大查询范围
+	responses, err := t.queryChainStatus(selected)
 	if err != nil {
 		ctx.GetLog().Warn("get block chain status error", "err", err)
+	}
+	if len(responses) < len(selected)/2+1 && t.ctx.EngCtx.EngCfg.SyncStatusEscalate && len(rest) > 0 {
+		ctx.GetLog().Debug("getMaxBlockHeight escalate", "responses", len(responses), "validators", rest)
+		more, moreErr := t.queryChainStatus(rest)
+		if moreErr != nil {
+			ctx.GetLog().Warn("get block chain status error", "err", moreErr)
+		}
+		responses = append(responses, more...)
+	}
+	if len(responses) == 0 && err != nil {
 		return "", 0, nil, err
 	}
 
+	peer, maxHeight, blockId := t.pickLongestChain(ctx, responses, t.ctx.Ledger.GetMeta().RootBlockid)
+	return peer, maxHeight, blockId, nil
+}
+
+// pickLongestChain 从验证人返回的链状态中选取最长链，跳过创世块不同和多次同步出错的节点
+func (t *Miner) pickLongestChain(ctx xctx.XContext, responses []*protos.XuperMessage, genesis []byte) (string, int64, []byte) {
 	maxHeight := int64(0)
 	peer := ""
 	blockId := []byte("")
 	for _, response := range responses {
 		var status xpb.ChainStatus
-		err = p2p.Unmarshal(response, &status)
+		err := p2p.Unmarshal(response, &status)
 		if err != nil {
 			ctx.GetLog().Warn("unmarshal block chain status error", "err", err)
 			continue
 		}
-		if status.LedgerMeta.TrunkHeight > maxHeight {
+		// 创世块不同的节点永远无法同步，不参与最长链选择
+		peerGenesis := status.GetLedgerMeta().GetRootBlockid()
+		if !t.incompatible.compatible(response.Header.From, genesis, peerGenesis) {
+			t.markIncompatible(ctx, response.Header.From, peerGenesis)
+			continue
+		}
+		t.checkGasSchedule(ctx, response.Header.From)
+		if isBetterTip(status.LedgerMeta.TrunkHeight, status.LedgerMeta.TipBlockid, maxHeight, blockId) {
 			// 判断该TipBlockid是否曾经验证出错过
 			if curPeerId, has := t.faultBlockIdCache.Get(string(status.LedgerMeta.TipBlockid)); has {
 				ctx.GetLog().Debug("faultBlockIdCache blockId hit", "TipBlockid", status.LedgerMeta.TipBlockid, "curPeerId", curPeerId)
@@ -149,7 +229,16 @@ func (t *Miner) getMaxBlockHeight(ctx xctx.XContext) (string, int64, []byte, err
 			blockId = status.LedgerMeta.TipBlockid
 		}
 	}
-	return peer, maxHeight, blockId, nil
+	return peer, maxHeight, blockId
+}
+
+// isBetterTip 判断候选链是否优于当前选中的链，高度相同时按区块id字节序取较小者，
+// 保证多个等高分叉下每次选出的结果一致，避免同步在分叉之间来回切换
+func isBetterTip(height int64, tipId []byte, maxHeight int64, maxTipId []byte) bool {
+	if height != maxHeight {
+		return height > maxHeight
+	}
+	return height > 0 && bytes.Compare(tipId, maxTipId) < 0
 }
 
 // syncWithValidators 向拥有最长链的验证人节点进行区块同步，直到区块高度完全一致，timeout用于设置同步超时时间，超时之后无论是否同步完毕都停止。
@@ -157,15 +246,19 @@ func (t *Miner) syncWithValidators(ctx xctx.XContext, timeout time.Duration) err
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		size, err := t.syncWithLongestChain(ctx)
+		if errors.Is(err, ErrSyncRedirected) {
+			// 出现了更高的同步目标，重新选取最长链
+			continue
+		}
 		if err != nil {
-			ctx.GetLog().Warn("syncWithLongestChain error", "error", err)
+			t.warnThrottled(ctx.GetLog(), "syncWithLongestChain error", err)
 			continue
 		}
 		if size == 0 {
 			return nil
 		}
 	}
-	return errors.New("syncUpValidators timeout")
+	return newError(ErrSync, "syncWithValidators", errors.New("syncUpValidators timeout"))
 }
 
 // syncWithLongestChain 向验证人结合进行一次区块同步，返回同步的区块个数
@@ -174,7 +267,7 @@ func (t *Miner) syncWithLongestChain(ctx xctx.XContext) (int, error) {
 	peer, maxHeight, blockId, err := t.getMaxBlockHeight(ctx)
 	if err != nil {
 		ctx.GetLog().Error("getMaxBlockHeight error", "error", err)
-		return 0, err
+		return 0, newError(ErrSync, "getMaxBlockHeight", err)
 	}
 	if maxHeight == 0 {
 		return 0, nil
@@ -182,11 +275,25 @@ func (t *Miner) syncWithLongestChain(ctx xctx.XContext) (int, error) {
 	if maxHeight <= currentHeight {
 		return 0, nil
 	}
+	// 同步期间收到更高的区块时取消本次同步
+	ctx, done := t.syncTarget.begin(ctx, maxHeight, blockId)
+	defer done()
 	ctx = xctx.WithNewContext(ctx, context.WithValue(ctx, peersKey, []string{peer}))
 	height := currentHeight + 1
 	size := maxHeight - currentHeight
 	ctx.GetLog().Info("syncWithLongestChain", "peer", peer, "beginHeight", height, "size", size)
 	realSize, err := t.syncBlockWithHeight(ctx, height, int(size))
+	if errors.Is(err, common.ErrGenesisBlockDiff) {
+		// 对端的链追溯到不同的创世块，不是普通的同步错误，不再向该节点同步
+		t.markIncompatible(ctx, peer, nil)
+		return 0, newError(ErrSync, "syncBlockWithHeight", err)
+	}
+	if errors.Is(err, ErrSyncRedirected) {
+		targetHeight, targetId := t.syncTarget.current()
+		ctx.GetLog().Info("syncWithLongestChain redirected", "peer", peer, "maxHeight", maxHeight,
+			"targetHeight", targetHeight, "targetId", utils.F(targetId))
+		return 0, err
+	}
 	if err != nil {
 		// 同步出错，记录blockId
 		t.faultBlockIdCache.Set(string(blockId), peer, faultBlockIdCacheExpired)
@@ -200,7 +307,7 @@ func (t *Miner) syncWithLongestChain(ctx xctx.XContext) (int, error) {
 			"beginHeight", height, "size", size, "blockId", blockId, "count", count,
 			"maxHeight", maxHeight, "currentHeight", currentHeight, "errInc", errInc, "err", err)
 
-		return 0, err
+		return 0, newError(ErrSync, "syncBlockWithHeight", err)
 	}
 	return realSize, nil
 }
@@ -223,8 +330,12 @@ func (t *Miner) syncWithNeighbors(ctx xctx.XContext) error {
 
 func (t *Miner) syncBlockWithHeight(ctx xctx.XContext, height int64, size int) (int, error) {
 	ctx.GetLog().Debug("getBlocksByHeight", "height", height, "size", size)
-	trace := traceSync()
+	trace := t.traceSync()
 	blocks, err := t.getBlocksByHeight(ctx, height, size)
+	// 同步已被取消时，返回的区块可能只来自部分节点，不再使用
+	if syncCancelled(ctx) {
+		return 0, ErrSyncRedirected
+	}
 	if err == ErrNoNewBlock {
 		return 0, nil
 	}
@@ -236,7 +347,10 @@ func (t *Miner) syncBlockWithHeight(ctx xctx.XContext, height int64, size int) (
 	trace("getBlockByHeight")
 	ctx.GetLog().Info("getBlocksByHeight return blocks", "height", height, "size", size, "realSize", len(blocks))
 	err = t.batchConfirmBlocks(ctx, blocks)
-	if err == ErrHashMissMatch {
+	if errors.Is(err, ErrSyncRedirected) {
+		return 0, err
+	}
+	if errors.Is(err, ErrHashMissMatch) {
 		// 发生了分叉，处理分叉
 		ctx.GetLog().Error("sync peers with fork")
 		err = t.handleFork(ctx)
@@ -265,7 +379,7 @@ func (t *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 		Size:   int64(size),
 	}
 
-	trace := traceSync()
+	trace := t.traceSync()
 	opts := []p2p.OptionFunc{
 		// p2p.WithPercent(0.1),
 	}
@@ -290,8 +404,17 @@ func (t *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 		return nil, err
 	}
 	trace("getBlockHeader")
-	blocks := quorumBlocks(responses, size)
+	blocks := quorumBlocks(ctx, responses, height, size)
+	if err := checkPeerBlocks(blocks, height); err != nil {
+		ctx.GetLog().Warn("quorum blocks not form a chain", "height", height, "err", err)
+		return nil, err
+	}
 	for _, blk := range blocks {
+		if err := checkBlockHeader(blk); err != nil {
+			ctx.GetLog().Warn("download malformed block header", "height", blk.GetHeight(),
+				"blockId", utils.F(blk.GetBlockid()), "err", err)
+			return nil, err
+		}
 		blkid, _ := ledger.MakeBlockID(blk)
 		if !bytes.Equal(blkid, blk.GetBlockid()) {
 			ctx.GetLog().Warn("download bad block id", "height", blk.GetHeight(),
@@ -314,7 +437,7 @@ func (t *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 }
 
 func (t *Miner) fillBlockTxs(ctx xctx.XContext, block *lpb.InternalBlock) error {
-	trace := traceSync()
+	trace := t.traceSync()
 	txids := block.GetMerkleTree()[:block.GetTxCount()]
 
 	blockTxs := make([]*lpb.Transaction, len(txids))
@@ -412,64 +535,66 @@ func (t *Miner) downloadMissingTxs(ctx xctx.XContext, blockid []byte, txidx []in
 
 // 追加区块到账本中
 func (t *Miner) batchConfirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBlock) error {
+	return t.confirmBlocks(ctx, blocks, false)
+}
+
+// confirmBlocks 依次确认区块，trusted为true时跳过区块校验和矿工校验，仅用于运维可信的区块来源
+func (t *Miner) confirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBlock, trusted bool) error {
 	if len(blocks) < 1 {
 		return nil
 	}
 
-	for _, block := range blocks {
-		trace := traceSync()
+	for i, block := range blocks {
+		// 同步被重定向时停止确认剩余区块，已确认的区块保留
+		if !trusted && syncCancelled(ctx) {
+			return ErrSyncRedirected
+		}
+		// 区块高度不在blockid的计算范围内，哈希相连的区块也可能伪造高度，
+		// 确认前检查整批区块从账本最新高度开始逐个递增，避免共识按错误的高度校验矿工
+		if i == 0 {
+			if err := checkPeerBlocks(blocks, t.ctx.Ledger.GetMeta().TrunkHeight+1); err != nil {
+				ctx.GetLog().Warn("confirm blocks not contiguous", "err", err)
+				return newError(ErrValidation, "checkPeerBlocks", err)
+			}
+			// 整批区块只等待一次，持有stepMutex的等待时间不超过FutureBlockHold
+			if !trusted {
+				if err := t.holdFutureBlocks(ctx, blocks); err != nil {
+					return err
+				}
+			}
+		}
+		trace := t.traceSync()
 		timer := timer.NewXTimer()
-		valid, err := t.ctx.Ledger.VerifyBlock(block, ctx.GetLog().GetLogId())
-		if !valid {
-			ctx.GetLog().Warn("the verification of block failed.",
-				"blockId", utils.F(block.Blockid))
-			return fmt.Errorf("the verification of block failed from ledger")
-		}
-		timer.Mark("VerifyBlock")
-		trace("VerifyBlock")
-
-		if !bytes.Equal(t.ctx.Ledger.GetMeta().TipBlockid, block.PreHash) {
-			ctx.GetLog().Error("block.prehash != chunkBlockId",
-				"height", block.Height,
-				"chunk", utils.F(t.ctx.Ledger.GetMeta().TipBlockid),
-				"block", utils.F(block.Blockid),
-				"block.prehash", utils.F(block.PreHash),
-			)
-			return ErrHashMissMatch
-		}
-
-		blockAgent := state.NewBlockAgent(block)
-		isMatch, err := t.ctx.Consensus.CheckMinerMatch(ctx, blockAgent)
-		if !isMatch {
-			ctx.GetLog().Warn("consensus check miner match failed",
-				"blockId", utils.F(block.Blockid), "err", err)
-			return errors.New("consensus check miner match failed")
+		mark := func(stage string) {
+			timer.Mark(stage)
+			trace(stage)
+		}
+		if err := t.checkReceivedBlock(ctx, block, trusted, mark); err != nil {
+			return err
 		}
-		timer.Mark("CheckMinerMatch")
-		trace("CheckMinerMatch")
 
 		status := t.ctx.Ledger.ConfirmBlock(block, false)
 		if !status.Succ {
 			ctx.GetLog().Warn("ledger confirm block failed",
 				"blockId", utils.F(block.Blockid), "err", status.Error)
-			return errors.New("ledger confirm block failed")
+			return newError(ErrLedgerConfirm, "confirmBlocks", status.Error)
 		}
 		timer.Mark("ConfirmBlock")
 		trace("ConfirmBlock")
 
 		// 状态机确认区块
-		err = t.ctx.State.PlayAndRepost(block.Blockid, false, false)
+		err := t.ctx.State.PlayAndRepost(block.Blockid, false, false)
 		if err != nil {
 			ctx.GetLog().Warn("state play error", "error", err, "height", block.Height, "blockId", utils.F(block.Blockid))
 		}
 		trace("PlayAndRepost")
 		timer.Mark("PlayAndRepost")
 
-		err = t.ctx.Consensus.ProcessConfirmBlock(blockAgent)
+		err = t.ctx.Consensus.ProcessConfirmBlock(state.NewBlockAgent(block))
 		if err != nil {
 			ctx.GetLog().Warn("consensus process confirm block failed",
 				"blockId", utils.F(block.Blockid), "err", err)
-			return errors.New("consensus process confirm block failed")
+			return newError(ErrConsensus, "ProcessConfirmBlock", err)
 		}
 		trace("ConProcessConfirmBlock")
 		err = t.ctx.Consensus.SwitchConsensus(block.Height)
@@ -478,6 +603,7 @@ func (t *Miner) batchConfirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBloc
 				"err", err, "blockId", utils.F(block.GetBlockid()))
 			// todo 这里暂时不返回错误
 		}
+		t.observeBlockPropagation(block)
 		ctx.GetLog().Info("confirm block finish", "blockId", utils.F(block.Blockid), "height", block.Height, "txCount", block.TxCount, "size", proto.Size(block), "costs", timer.Print())
 	}
 
@@ -517,8 +643,38 @@ func (q *quorumBlockCounter) quorumBlock() *lpb.InternalBlock {
 	return q.count[0].Block
 }
 
+// checkBlockHeader 检查同步到的区块头的交易个数，区块至少包含矿工奖励交易，且交易个数不能超过merkle树的叶子数
+func checkBlockHeader(blk *lpb.InternalBlock) error {
+	if blk.GetTxCount() < 1 {
+		return fmt.Errorf("%w: height %d", ledger.ErrBlockNoTx, blk.GetHeight())
+	}
+	if int(blk.GetTxCount()) > len(blk.GetMerkleTree()) {
+		return fmt.Errorf("block tx count %d exceeds merkle tree size %d", blk.GetTxCount(), len(blk.GetMerkleTree()))
+	}
+	return nil
+}
+
+// checkPeerBlocks 检查区块是否从请求高度开始且前后相连，防止错误或恶意节点返回无关区块。
+// 按高度逐个投票选出的区块可能来自不同节点，合并后同样需要检查，断链时返回缺口位置
+func checkPeerBlocks(blocks []*lpb.InternalBlock, height int64) error {
+	for i, blk := range blocks {
+		if blk == nil {
+			return fmt.Errorf("%w: nil block at height %d", ErrBlockChainGap, height+int64(i))
+		}
+		if blk.GetHeight() != height+int64(i) {
+			return fmt.Errorf("%w: block height mismatch, expect:%d got:%d", ErrBlockChainGap, height+int64(i), blk.GetHeight())
+		}
+		if i > 0 && !bytes.Equal(blk.GetPreHash(), blocks[i-1].GetBlockid()) {
+			return fmt.Errorf("%w: block %s at height %d not link to %s at height %d, prehash %s",
+				ErrBlockChainGap, utils.F(blk.GetBlockid()), blk.GetHeight(),
+				utils.F(blocks[i-1].GetBlockid()), blocks[i-1].GetHeight(), utils.F(blk.GetPreHash()))
+		}
+	}
+	return nil
+}
+
 // quorumBlocks 根据节点们返回的p2p区块头消息列表算出大多数都认可的区块头列表，如果没有区块合适的区块信息，则返回nil
-func quorumBlocks(responses []*protos.XuperMessage, blockAmount int) []*lpb.InternalBlock {
+func quorumBlocks(ctx xctx.XContext, responses []*protos.XuperMessage, height int64, blockAmount int) []*lpb.InternalBlock {
 	var peerBlocks [][]*lpb.InternalBlock
 	for _, response := range responses {
 		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
@@ -532,6 +688,11 @@ func quorumBlocks(responses []*protos.XuperMessage, blockAmount int) []*lpb.Inte
 		if len(block.Blocks) == 0 {
 			continue
 		}
+		if err := checkPeerBlocks(block.Blocks, height); err != nil {
+			ctx.GetLog().Warn("skip bad block headers from peer", "from", response.GetHeader().GetFrom(),
+				"height", height, "err", err)
+			continue
+		}
 		peerBlocks = append(peerBlocks, block.Blocks)
 	}
 
@@ -582,7 +743,7 @@ func (t *Miner) findForkPoint(ctx xctx.XContext) (*lpb.InternalBlock, error) {
 		if height == 0 {
 			ctx.GetLog().Error("the genesis block is different",
 				"genesisBlockId", utils.F(ledger.GetMeta().RootBlockid))
-			return nil, errors.New("block diff at genesis block")
+			return nil, common.ErrGenesisBlockDiff
 		}
 		height -= 1
 
@@ -602,7 +763,7 @@ func (t *Miner) findForkPoint(ctx xctx.XContext) (*lpb.InternalBlock, error) {
 			ctx.GetLog().Warn("query block header error", "err", err)
 			return nil, err
 		}
-		blks := quorumBlocks(responses, 1)
+		blks := quorumBlocks(ctx, responses, height, 1)
 		if len(blks) == 0 {
 			ctx.GetLog().Warn("query block header with no response")
 			return nil, errors.New("query block header with no response")
@@ -0,0 +1,89 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// ErrSyncRedirected 同步过程中出现了更高的同步目标，进行中的同步被取消
+var ErrSyncRedirected = errors.New("sync redirected to higher target")
+
+// syncTarget 记录进行中的同步目标，收到更高的目标时取消进行中的同步，
+// 由同步循环重新选取最长链，避免在追块期间继续同步已经过时的目标
+type syncTarget struct {
+	mutex   sync.Mutex
+	height  int64
+	blockId []byte
+	cancel  context.CancelFunc
+}
+
+// begin 开始一次向height同步的过程，返回可被取消的上下文和结束同步时调用的函数
+func (s *syncTarget) begin(ctx xctx.XContext, height int64, blockId []byte) (xctx.XContext, func()) {
+	cctx, cancel := context.WithCancel(ctx)
+	s.mutex.Lock()
+	s.height, s.blockId, s.cancel = height, blockId, cancel
+	s.mutex.Unlock()
+
+	return xctx.WithNewContext(ctx, cctx), func() {
+		s.mutex.Lock()
+		s.height, s.blockId, s.cancel = 0, nil, nil
+		s.mutex.Unlock()
+		cancel()
+	}
+}
+
+// current 返回进行中的同步目标，没有进行中的同步时高度为0
+func (s *syncTarget) current() (int64, []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.height, s.blockId
+}
+
+// raise 收到高于进行中同步目标的区块时取消该同步，返回是否发生了重定向
+func (s *syncTarget) raise(height int64, blockId []byte) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel == nil || height <= s.height {
+		return false
+	}
+	s.height, s.blockId = height, blockId
+	s.cancel()
+	s.cancel = nil
+	return true
+}
+
+// proposedByValidator 判断区块是否由当前验证人产生，避免任意节点广播的区块频繁打断同步。
+// 区块中的proposer可以任意填写，需校验区块id和矿工签名确认区块确实由该验证人签发
+func (t *Miner) proposedByValidator(ctx xctx.XContext, block *lpb.InternalBlock) bool {
+	validators, err := t.getValidators("")
+	if err != nil {
+		return false
+	}
+	for _, addr := range validators {
+		if addr != string(block.GetProposer()) {
+			continue
+		}
+		if err := t.ctx.Ledger.VerifyBlockHeader(block, ctx.GetLog().GetLogId()); err != nil {
+			ctx.GetLog().Warn("ignore block with invalid header", "height", block.GetHeight(),
+				"blockId", utils.F(block.GetBlockid()), "err", err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// syncCancelled 判断同步上下文是否已被取消
+func syncCancelled(ctx xctx.XContext) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/mock"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestSyncTargetRedirect(t *testing.T) {
+	var target syncTarget
+	if target.raise(10, []byte("b10")) {
+		t.Fatal("raise without in-flight sync should not redirect")
+	}
+
+	ctx, done := target.begin(&xctx.BaseCtx{}, 5, []byte("b5"))
+	defer done()
+	if target.raise(5, []byte("b5")) || syncCancelled(ctx) {
+		t.Fatal("same target should not redirect")
+	}
+	if !target.raise(8, []byte("b8")) || !syncCancelled(ctx) {
+		t.Fatal("higher target should cancel in-flight sync")
+	}
+	if height, blockId := target.current(); height != 8 || string(blockId) != "b8" {
+		t.Fatalf("unexpected target: %d %s", height, blockId)
+	}
+
+	// 被取消的同步不再确认剩余区块
+	m := &Miner{}
+	blocks := []*lpb.InternalBlock{{Height: 6}}
+	if err := m.confirmBlocks(ctx, blocks, false); err != ErrSyncRedirected {
+		t.Fatalf("expect redirected, got %v", err)
+	}
+
+	done()
+	if height, _ := target.current(); height != 0 {
+		t.Fatalf("target should be cleared after sync, got %d", height)
+	}
+}
+
+func TestProcBlockRedirect(t *testing.T) {
+	l := newTestLedger(t)
+	crypto, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := (*xaddress.Address)(cAddr)
+	other := newTestAddress(t, crypto)
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	m := &Miner{
+		log:   log,
+		clock: realClock{},
+		ctx: &common.ChainCtx{
+			Ledger:    l,
+			Consensus: &validatorsConsensus{validators: []string{validator.Address}},
+			EngCtx:    &common.EngineCtx{EngCfg: &engconf.EngineConf{}},
+		},
+	}
+	ctx := &xctx.BaseCtx{XLog: log}
+	syncCtx, done := m.syncTarget.begin(ctx, 1, []byte("b1"))
+	defer done()
+
+	// 区块高度不在区块id中，修改高度后签名仍然有效
+	higher := func(addr *xaddress.Address, timestamp int64) *lpb.InternalBlock {
+		block := newTestBlock(t, l, root, addr, timestamp)
+		block.Height = 2
+		return block
+	}
+
+	// 非验证人产生的区块
+	if err := m.ProcBlock(ctx, higher(other, 1)); err != nil || syncCancelled(syncCtx) {
+		t.Fatal("block from non-validator should not redirect sync")
+	}
+	// 冒用验证人地址但签名不正确的区块
+	forged := higher(other, 2)
+	forged.Proposer = []byte(validator.Address)
+	if err := m.ProcBlock(ctx, forged); err != nil || syncCancelled(syncCtx) {
+		t.Fatal("block with forged proposer should not redirect sync")
+	}
+	// 区块id与内容不符的区块
+	tampered := higher(validator, 3)
+	tampered.Timestamp++
+	if err := m.ProcBlock(ctx, tampered); err != nil || syncCancelled(syncCtx) {
+		t.Fatal("block with mismatched id should not redirect sync")
+	}
+
+	if err := m.ProcBlock(ctx, higher(validator, 4)); err != nil || !syncCancelled(syncCtx) {
+		t.Fatal("valid block from validator should redirect sync")
+	}
+}
+
+func TestProcBlockFutureBlock(t *testing.T) {
+	l := newTestLedger(t)
+	_, cAddr, err := mock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := (*xaddress.Address)(cAddr)
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	now := time.Unix(1600000000, 0)
+	cfg := &engconf.EngineConf{MaxFutureBlockDrift: 5 * time.Second}
+	m := &Miner{
+		log:   log,
+		clock: &fixedClock{now: now},
+		ctx: &common.ChainCtx{
+			Ledger:    l,
+			Consensus: &validatorsConsensus{validators: []string{validator.Address}},
+			EngCtx:    &common.EngineCtx{EngCfg: cfg},
+		},
+	}
+	ctx := &xctx.BaseCtx{XLog: log}
+	syncCtx, done := m.syncTarget.begin(ctx, 1, []byte("b1"))
+	defer done()
+	blockAt := func(d time.Duration) *lpb.InternalBlock {
+		block := newTestBlock(t, l, root, validator, now.Add(cfg.MaxFutureBlockDrift+d).UnixNano())
+		block.Height = 2
+		return block
+	}
+
+	// 超出允许漂移的区块同步时会被拒绝，不能作为同步目标
+	if err := m.ProcBlock(ctx, blockAt(time.Millisecond)); err != nil || syncCancelled(syncCtx) {
+		t.Fatal("block beyond max drift should not redirect sync")
+	}
+	if height, _ := m.syncTarget.current(); height != 1 {
+		t.Fatalf("sync target should not be raised, got %d", height)
+	}
+	if err := m.ProcBlock(ctx, blockAt(0)); err != nil || !syncCancelled(syncCtx) {
+		t.Fatal("block just within max drift should redirect sync")
+	}
+}
@@ -0,0 +1,162 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// pickTip 用矿工的最长链选择逻辑从各节点的链状态中选出最新区块
+func pickTip(statuses []*xpb.ChainStatus) []byte {
+	m := &Miner{
+		faultBlockIdCache: cache.New(faultBlockIdCacheExpired, faultCacheGCInterval),
+		faultPeerIdCache:  cache.New(faultPeerIdCacheExpired, faultCacheGCInterval),
+		incompatible:      newIncompatiblePeers(),
+		ctx:               &common.ChainCtx{EngCtx: &common.EngineCtx{}},
+	}
+	responses := make([]*protos.XuperMessage, 0, len(statuses))
+	for i, status := range statuses {
+		resp := p2p.NewMessage(protos.XuperMessage_GET_BLOCKCHAINSTATUS_RES, status)
+		resp.Header.From = fmt.Sprintf("peer%d", i)
+		responses = append(responses, resp)
+	}
+	log, _ := logs.NewLogger("", "miner")
+	_, _, blockId := m.pickLongestChain(&xctx.BaseCtx{XLog: log}, responses, nil)
+	return blockId
+}
+
+func TestIsBetterTipStable(t *testing.T) {
+	mock.InitLogForTest()
+	newStatus := func(height int64, tip string) *xpb.ChainStatus {
+		return &xpb.ChainStatus{
+			LedgerMeta: &lpb.LedgerMeta{TrunkHeight: height, TipBlockid: []byte(tip)},
+		}
+	}
+	a := newStatus(10, "b2")
+	b := newStatus(10, "a1")
+	c := newStatus(10, "c3")
+	d := newStatus(9, "00")
+
+	orders := [][]*xpb.ChainStatus{
+		{a, b, c, d},
+		{c, b, a, d},
+		{d, c, a, b},
+		{b, d, c, a},
+	}
+	for i, order := range orders {
+		if got := string(pickTip(order)); got != "a1" {
+			t.Fatalf("order %d: expect tip a1, got %s", i, got)
+		}
+	}
+
+	if got := string(pickTip([]*xpb.ChainStatus{d, newStatus(11, "ff")})); got != "ff" {
+		t.Fatalf("expect higher tip ff, got %s", got)
+	}
+}
+
+func TestBlockPropagationLatency(t *testing.T) {
+	now := time.Now()
+	block := &lpb.InternalBlock{Timestamp: now.Add(-2 * time.Second).UnixNano()}
+	latency, skew := blockPropagationLatency(block, now)
+	if skew || latency != 2*time.Second {
+		t.Fatalf("unexpected latency: %v, skew: %v", latency, skew)
+	}
+
+	// 对端时钟快于本地
+	block.Timestamp = now.Add(time.Second).UnixNano()
+	latency, skew = blockPropagationLatency(block, now)
+	if !skew || latency != 0 {
+		t.Fatalf("skewed block should be clamped, latency: %v, skew: %v", latency, skew)
+	}
+}
+
+func TestChainStatusOptions(t *testing.T) {
+	validators := []string{"a", "b", "c", "d", "e"}
+	opt := p2p.Apply(chainStatusOptions(validators, 3*time.Second))
+	if opt.MinResponses != 3 {
+		t.Fatalf("expect quorum 3, got %d", opt.MinResponses)
+	}
+	if opt.ResponseDeadline != 3*time.Second {
+		t.Fatalf("unexpected deadline %s", opt.ResponseDeadline)
+	}
+	if len(opt.Accounts) != len(validators) {
+		t.Fatalf("unexpected accounts %v", opt.Accounts)
+	}
+
+	opt = p2p.Apply(chainStatusOptions(validators, 0))
+	if opt.ResponseDeadline != 0 {
+		t.Fatalf("expect no deadline, got %s", opt.ResponseDeadline)
+	}
+}
+
+func TestCheckPeerBlocksGap(t *testing.T) {
+	b1 := &lpb.InternalBlock{Blockid: []byte("b1"), Height: 1}
+	b2 := &lpb.InternalBlock{Blockid: []byte("b2"), PreHash: []byte("b1"), Height: 2}
+	b3 := &lpb.InternalBlock{Blockid: []byte("b3"), PreHash: []byte("b2"), Height: 3}
+	if err := checkPeerBlocks([]*lpb.InternalBlock{b1, b2, b3}, 1); err != nil {
+		t.Fatal(err)
+	}
+	// 缺失中间区块
+	if err := checkPeerBlocks([]*lpb.InternalBlock{b1, b3}, 1); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect chain gap, got %v", err)
+	}
+	// 哈希相连但高度跳跃的伪造区块
+	forged := &lpb.InternalBlock{Blockid: []byte("b2"), PreHash: []byte("b1"), Height: 5}
+	if err := checkPeerBlocks([]*lpb.InternalBlock{b1, forged}, 1); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect height gap, got %v", err)
+	}
+	// 整批区块需从账本最新高度的下一个开始
+	if err := checkPeerBlocks([]*lpb.InternalBlock{b2, b3}, 3); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect batch not start at tip, got %v", err)
+	}
+}
+
+func TestQuorumBlocksMixedPeers(t *testing.T) {
+	a1 := &lpb.InternalBlock{Blockid: []byte("a1"), Height: 1}
+	c1 := &lpb.InternalBlock{Blockid: []byte("c1"), Height: 1}
+	d2 := &lpb.InternalBlock{Blockid: []byte("d2"), PreHash: []byte("c1"), Height: 2}
+	response := func(blocks ...*lpb.InternalBlock) *protos.XuperMessage {
+		return p2p.NewMessage(protos.XuperMessage_GET_BLOCKS_HEADERS_RES,
+			&xpb.GetBlockHeaderResponse{Blocks: blocks}, p2p.WithErrorType(protos.XuperMessage_SUCCESS))
+	}
+	// 每个节点返回的区块各自相连，但按高度投票后拼出的区块不相连
+	responses := []*protos.XuperMessage{
+		response(a1), response(a1), response(a1),
+		response(c1, d2), response(c1, d2),
+	}
+	blocks := quorumBlocks(nil, responses, 1, 2)
+	if len(blocks) != 2 || string(blocks[0].Blockid) != "a1" || string(blocks[1].Blockid) != "d2" {
+		t.Fatalf("unexpected quorum blocks: %v", blocks)
+	}
+	if err := checkPeerBlocks(blocks, 1); !errors.Is(err, ErrBlockChainGap) {
+		t.Fatalf("expect chain gap, got %v", err)
+	}
+}
+
+func TestCheckBlockHeader(t *testing.T) {
+	blk := &lpb.InternalBlock{Height: 5, TxCount: 1, MerkleTree: [][]byte{[]byte("tx")}}
+	if err := checkBlockHeader(blk); err != nil {
+		t.Fatal(err)
+	}
+	empty := &lpb.InternalBlock{Height: 5}
+	if err := checkBlockHeader(empty); !errors.Is(err, ledger.ErrBlockNoTx) {
+		t.Fatalf("expect no tx error, got %v", err)
+	}
+	overflow := &lpb.InternalBlock{Height: 5, TxCount: 2, MerkleTree: [][]byte{[]byte("tx")}}
+	if err := checkBlockHeader(overflow); err == nil {
+		t.Fatal("tx count beyond merkle tree should be rejected")
+	}
+}
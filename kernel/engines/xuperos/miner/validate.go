@@ -0,0 +1,92 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// checkReceivedBlock 确认接收到的区块前的校验，依次检查区块时间、区块内容、与账本最新区块相连和矿工身份，
+// 不修改账本和状态机。trusted为true时只检查与账本最新区块相连，mark用于记录各阶段耗时
+func (t *Miner) checkReceivedBlock(ctx xctx.XContext, block *lpb.InternalBlock, trusted bool, mark func(string)) error {
+	if !trusted {
+		if err := t.checkFutureBlock(block); err != nil {
+			ctx.GetLog().Warn("reject future block", "err", err)
+			return newError(ErrValidation, "checkFutureBlock", err)
+		}
+		err := t.verifiedBlocks.verify(block, func() error {
+			return t.ctx.Ledger.VerifyBlockWithReason(block, ctx.GetLog().GetLogId())
+		})
+		if err != nil {
+			ctx.GetLog().Warn("the verification of block failed.",
+				"blockId", utils.F(block.Blockid), "reason", err)
+			// 返回具体原因，上层同步出错时会记录对应节点的错误次数
+			return newError(ErrValidation, "VerifyBlock", err)
+		}
+		mark("VerifyBlock")
+	}
+
+	if !bytes.Equal(t.ctx.Ledger.GetMeta().TipBlockid, block.PreHash) {
+		ctx.GetLog().Error("block.prehash != chunkBlockId",
+			"height", block.Height,
+			"chunk", utils.F(t.ctx.Ledger.GetMeta().TipBlockid),
+			"block", utils.F(block.Blockid),
+			"block.prehash", utils.F(block.PreHash),
+		)
+		return newError(ErrValidation, "checkReceivedBlock", ErrHashMissMatch)
+	}
+
+	if !trusted {
+		isMatch, err := t.ctx.Consensus.CheckMinerMatch(ctx, state.NewBlockAgent(block))
+		if !isMatch {
+			ctx.GetLog().Warn("consensus check miner match failed",
+				"blockId", utils.F(block.Blockid), "err", err)
+			return newError(ErrConsensus, "CheckMinerMatch", err)
+		}
+		mark("CheckMinerMatch")
+	}
+	return nil
+}
+
+// ValidateBlock 按接收区块的流程校验区块能否接在账本最新区块之后，包括区块头、区块时间、
+// 区块内容、矿工身份和区块中交易的合法性，不确认区块也不修改账本和状态机，用于外部组件提交区块前的预检。
+// 交易按状态机当前状态校验，状态机落后于账本时无法校验
+func (t *Miner) ValidateBlock(block *lpb.InternalBlock) (bool, error) {
+	if block == nil {
+		return false, newError(ErrValidation, "ValidateBlock", errors.New("nil block"))
+	}
+
+	ctx := t.newRoundContext()
+	if err := t.validateBlock(ctx, block); err != nil {
+		ctx.GetLog().Info("validate block failed", "blockId", utils.F(block.GetBlockid()),
+			"height", block.GetHeight(), "err", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (t *Miner) validateBlock(ctx xctx.XContext, block *lpb.InternalBlock) error {
+	if err := checkBlockHeader(block); err != nil {
+		return newError(ErrValidation, "checkBlockHeader", err)
+	}
+	// 在锁外等待区块时间进入允许范围，避免阻塞矿工循环
+	if err := t.holdFutureBlocks(ctx, []*lpb.InternalBlock{block}); err != nil {
+		return err
+	}
+	return t.withChainLock(func() error {
+		if err := checkPeerBlocks([]*lpb.InternalBlock{block}, t.ctx.Ledger.GetMeta().TrunkHeight+1); err != nil {
+			return newError(ErrValidation, "checkPeerBlocks", err)
+		}
+		if err := t.checkReceivedBlock(ctx, block, false, func(string) {}); err != nil {
+			return err
+		}
+		if err := t.ctx.State.VerifyBlockTxs(block); err != nil {
+			return newError(ErrValidation, "VerifyBlockTxs", err)
+		}
+		return nil
+	})
+}
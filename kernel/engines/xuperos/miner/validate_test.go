@@ -0,0 +1,114 @@
+package miner
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	cmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	xconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestValidateBlockHeader(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "miner")
+	m := &Miner{log: log}
+
+	if ok, err := m.ValidateBlock(nil); ok || !errors.Is(err, ErrValidation) {
+		t.Fatalf("nil block should be rejected, got %v %v", ok, err)
+	}
+
+	// 区块头不合法时在访问账本前拒绝
+	empty := &lpb.InternalBlock{Blockid: []byte("empty"), Height: 1}
+	ok, err := m.ValidateBlock(empty)
+	if ok || !errors.Is(err, ErrValidation) || !errors.Is(err, ledger.ErrBlockNoTx) {
+		t.Fatalf("block without tx should be rejected, got %v %v", ok, err)
+	}
+	overflow := &lpb.InternalBlock{Blockid: []byte("overflow"), Height: 1, TxCount: 2, MerkleTree: [][]byte{[]byte("tx")}}
+	if ok, err := m.ValidateBlock(overflow); ok || !errors.Is(err, ErrValidation) {
+		t.Fatalf("block with bad merkle tree should be rejected, got %v %v", ok, err)
+	}
+}
+
+func TestValidateBlock(t *testing.T) {
+	l := newTestLedger(t)
+	s := newTestState(t, l)
+	_, cAddr, err := cmock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := (*xaddress.Address)(cAddr)
+	root, err := l.QueryBlockHeaderByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log, _ := logs.NewLogger("", "miner")
+	cfg := xconf.GetDefEngineConf()
+	cons := &importConsensus{accept: true}
+	m := &Miner{
+		log:            log,
+		clock:          realClock{},
+		verifiedBlocks: newVerifiedBlockCache("xuper", cfg.VerifiedBlockCacheSize),
+		ctx: &common.ChainCtx{
+			BCName:    "xuper",
+			Ledger:    l,
+			State:     s,
+			Consensus: cons,
+			EngCtx:    &common.EngineCtx{EngCfg: cfg},
+		},
+	}
+	ledgerTip, stateTip := l.GetMeta().GetTipBlockid(), s.GetLatestBlockid()
+	unchanged := func() {
+		if !bytes.Equal(l.GetMeta().GetTipBlockid(), ledgerTip) || !bytes.Equal(s.GetLatestBlockid(), stateTip) {
+			t.Fatal("validate block should not change ledger or state")
+		}
+	}
+
+	// 区块签名损坏
+	forged := newTestBlock(t, l, root, addr, 1)
+	forged.Sign = []byte("forged")
+	if ok, err := m.ValidateBlock(forged); ok || !errors.Is(err, ErrValidation) {
+		t.Fatalf("block with bad sign should be rejected, got %v %v", ok, err)
+	}
+	unchanged()
+
+	// 共识拒绝矿工身份
+	cons.accept = false
+	if ok, err := m.ValidateBlock(newTestBlock(t, l, root, addr, 2)); ok || !errors.Is(err, ErrConsensus) {
+		t.Fatalf("block rejected by consensus should fail, got %v %v", ok, err)
+	}
+	unchanged()
+	cons.accept = true
+
+	// 区块中包含不合法的交易
+	bad := newTestCoinbase(t, addr.Address, []byte("bad tx"))
+	bad.Coinbase = false
+	if bad.Txid, err = txhash.MakeTransactionID(bad); err != nil {
+		t.Fatal(err)
+	}
+	award := newTestCoinbase(t, addr.Address, []byte("award"))
+	block, err := l.FormatBlock([]*lpb.Transaction{award, bad}, []byte(addr.Address), addr.PrivateKey,
+		3, 0, 0, root.GetBlockid(), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Height = 1
+	if ok, err := m.ValidateBlock(block); ok || !errors.Is(err, ErrValidation) {
+		t.Fatalf("block with invalid tx should be rejected, got %v %v", ok, err)
+	}
+	unchanged()
+
+	if ok, err := m.ValidateBlock(newTestBlock(t, l, root, addr, 4)); !ok || err != nil {
+		t.Fatalf("valid block should pass, got %v %v", ok, err)
+	}
+	unchanged()
+}
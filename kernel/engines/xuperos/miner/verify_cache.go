@@ -0,0 +1,65 @@
+package miner
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/golang/protobuf/proto"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/cache"
+	"github.com/xuperchain/xupercore/lib/metrics"
+)
+
+// verifiedBlockCache 缓存最近校验通过的区块，分叉切换后重新追加同一区块时跳过签名等校验。
+// blockid不覆盖区块签名等全部内容，因此以blockid为key同时记录区块完整内容的摘要，
+// 相同blockid但内容不同的区块视为未命中并重新校验，校验通过后覆盖原记录。
+// 只缓存校验通过的结果，失败的区块可能来自恶意节点，每次都需要给出具体原因
+type verifiedBlockCache struct {
+	bcName string
+	cache  *cache.LRUCache
+}
+
+// newVerifiedBlockCache size<=0时不缓存
+func newVerifiedBlockCache(bcName string, size int) *verifiedBlockCache {
+	c := &verifiedBlockCache{bcName: bcName}
+	if size > 0 {
+		c.cache = cache.NewLRUCache(size)
+	}
+	return c
+}
+
+// blockDigest 计算区块完整内容的摘要
+func blockDigest(block *lpb.InternalBlock) ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(block); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(buf.Bytes())
+	return digest[:], nil
+}
+
+// verify 区块已校验通过且内容未变时直接返回，否则调用verify校验并缓存通过的结果
+func (c *verifiedBlockCache) verify(block *lpb.InternalBlock, verify func() error) error {
+	if c == nil || c.cache == nil {
+		return verify()
+	}
+
+	key := string(block.GetBlockid())
+	digest, err := blockDigest(block)
+	if err != nil {
+		return verify()
+	}
+	if v, ok := c.cache.Get(key); ok && bytes.Equal(v.([]byte), digest) {
+		metrics.LedgerBlockVerifyCacheCounter.WithLabelValues(c.bcName, "hit").Inc()
+		return nil
+	}
+	metrics.LedgerBlockVerifyCacheCounter.WithLabelValues(c.bcName, "miss").Inc()
+
+	if err := verify(); err != nil {
+		return err
+	}
+	c.cache.Add(key, digest)
+	return nil
+}
@@ -0,0 +1,61 @@
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+func TestVerifiedBlockCacheReorg(t *testing.T) {
+	c := newVerifiedBlockCache("xuper", 2)
+	calls := 0
+	verify := func() error {
+		calls++
+		return nil
+	}
+
+	// 原分支上的区块a1确认后被b1分叉切换掉，再次切回原分支时重新追加a1
+	a1 := &lpb.InternalBlock{Blockid: []byte("a1"), Height: 1, Sign: []byte("sign"), FailedTxs: map[string]string{"tx1": "e", "tx2": "e"}}
+	b1 := &lpb.InternalBlock{Blockid: []byte("b1"), Height: 1, Sign: []byte("sign")}
+	for _, block := range []*lpb.InternalBlock{a1, b1, proto.Clone(a1).(*lpb.InternalBlock)} {
+		if err := c.verify(block, verify); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expect re-applied block not verified again, verify called %d times", calls)
+	}
+
+	// blockid相同但内容不同的区块需要重新校验
+	forged := proto.Clone(a1).(*lpb.InternalBlock)
+	forged.Sign = []byte("forged")
+	errForged := errors.New("bad sign")
+	if err := c.verify(forged, func() error { return errForged }); err != errForged {
+		t.Fatalf("expect forged block verified again, got %v", err)
+	}
+	// 校验失败的结果不缓存，也不覆盖原记录
+	if err := c.verify(forged, func() error { return errForged }); err != errForged {
+		t.Fatalf("expect failed result not cached, got %v", err)
+	}
+	if err := c.verify(a1, verify); err != nil || calls != 2 {
+		t.Fatalf("expect original block still cached, calls %d, err %v", calls, err)
+	}
+
+	// 超出容量后淘汰最久未使用的区块
+	c.verify(&lpb.InternalBlock{Blockid: []byte("c1")}, verify)
+	c.verify(b1, verify)
+	if calls != 4 {
+		t.Fatalf("expect evicted block verified again, verify called %d times", calls)
+	}
+
+	// 未开启缓存时每次都校验
+	off := newVerifiedBlockCache("xuper", 0)
+	off.verify(a1, verify)
+	off.verify(a1, verify)
+	if calls != 6 {
+		t.Fatalf("expect no cache when disabled, verify called %d times", calls)
+	}
+}
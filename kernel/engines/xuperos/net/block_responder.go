@@ -0,0 +1,54 @@
+package xuperos
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/lib/cache"
+)
+
+// blockResponder 服务GET_BLOCK请求的热点缓存
+// 区块广播模式下出块节点发出NEW_BLOCKID后，邻居会集中回调GET_BLOCK拉取同一个区块，
+// 缓存最近被请求的区块，并把同一区块的并发请求合并为一次查询，降低出块节点的瞬时负载。
+// 区块是否在主干上只会随账本末端变化，缓存key带上当前末端区块id，末端变化后旧的缓存自然失效
+type blockResponder struct {
+	cache *cache.LRUCache
+	group singleflight.Group
+}
+
+// newBlockResponder size<=0时不缓存，仍然合并并发请求
+func newBlockResponder(size int) *blockResponder {
+	r := &blockResponder{}
+	if size > 0 {
+		r.cache = cache.NewLRUCache(size)
+	}
+	return r
+}
+
+func blockResponderKey(bcName string, tipId, blockId []byte, needContent bool) string {
+	return fmt.Sprintf("%s/%x/%x/%t", bcName, tipId, blockId, needContent)
+}
+
+// get 优先从缓存获取区块，未命中时调用load查询，同一key的并发请求只查询一次
+func (r *blockResponder) get(key string, load func() (*xpb.BlockInfo, error)) (*xpb.BlockInfo, error) {
+	if r.cache != nil {
+		if v, ok := r.cache.Get(key); ok {
+			return v.(*xpb.BlockInfo), nil
+		}
+	}
+
+	v, err, _ := r.group.Do(key, func() (interface{}, error) {
+		info, err := load()
+		if err != nil {
+			return info, err
+		}
+		if r.cache != nil {
+			r.cache.Add(key, info)
+		}
+		return info, nil
+	})
+	info, _ := v.(*xpb.BlockInfo)
+	return info, err
+}
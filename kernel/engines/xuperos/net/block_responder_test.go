@@ -0,0 +1,62 @@
+package xuperos
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+)
+
+func TestBlockResponder(t *testing.T) {
+	r := newBlockResponder(2)
+	var loads int32
+	load := func() (*xpb.BlockInfo, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &xpb.BlockInfo{Status: lpb.BlockStatus_BLOCK_TRUNK}, nil
+	}
+
+	// 同一区块的并发请求只查询一次
+	key := blockResponderKey("xuper", []byte("tip"), []byte("block"), true)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.get(key, load); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := r.get(key, load); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Fatalf("expect 1 load, got %d", n)
+	}
+
+	// 末端变化后重新查询
+	if _, err := r.get(blockResponderKey("xuper", []byte("tip2"), []byte("block"), true), load); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&loads); n != 2 {
+		t.Fatalf("expect 2 loads, got %d", n)
+	}
+
+	// 查询失败不缓存
+	errKey := blockResponderKey("xuper", []byte("tip"), []byte("missing"), true)
+	failLoad := func() (*xpb.BlockInfo, error) {
+		atomic.AddInt32(&loads, 1)
+		return nil, errors.New("not exist")
+	}
+	r.get(errKey, failLoad)
+	r.get(errKey, failLoad)
+	if n := atomic.LoadInt32(&loads); n != 4 {
+		t.Fatalf("expect 4 loads, got %d", n)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -35,6 +36,8 @@ type NetEvent struct {
 	engine   common.Engine
 	msgChan  chan *protos.XuperMessage
 	exitChan chan bool
+	// GET_BLOCK热点区块缓存
+	blockResponder *blockResponder
 }
 
 func NewNetEvent(engine common.Engine) (*NetEvent, error) {
@@ -47,6 +50,8 @@ func NewNetEvent(engine common.Engine) (*NetEvent, error) {
 		engine:   engine,
 		msgChan:  make(chan *protos.XuperMessage, DefMsgChanBufSize),
 		exitChan: make(chan bool, 1),
+
+		blockResponder: newBlockResponder(engine.Context().EngCfg.GetBlockCacheSize),
 	}
 
 	// 订阅监听事件
@@ -85,6 +90,7 @@ func (t *NetEvent) Subscriber() error {
 		protos.XuperMessage_CONFIRM_BLOCKCHAINSTATUS: t.handleConfirmChainStatus,
 		protos.XuperMessage_GET_BLOCK_HEADERS:        t.handleGetBlockHeaders,
 		protos.XuperMessage_GET_BLOCK_TXS:            t.handleGetBlockTxs,
+		protos.XuperMessage_GET_CHECKPOINT:           t.handleGetCheckpoint,
 	}
 
 	net := t.engine.Context().Net
@@ -123,7 +129,16 @@ func (t *NetEvent) procMsgLoop() {
 	}
 }
 
+// procAsyncMsg 在独立协程中处理异步消息，handler发生panic时恢复并记录消息类型和堆栈，避免节点退出
 func (t *NetEvent) procAsyncMsg(request *protos.XuperMessage) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.log.Error("process async message panic", "type", request.GetHeader().GetType(),
+				"bc", request.GetHeader().GetBcname(), "from", request.GetHeader().GetFrom(),
+				"logid", request.GetHeader().GetLogid(), "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+
 	var AsyncMsgList = map[protos.XuperMessage_MessageType]AsyncMsgHandle{
 		protos.XuperMessage_POSTTX:      t.handlePostTx,
 		protos.XuperMessage_SENDBLOCK:   t.handleSendBlock,
@@ -334,7 +349,10 @@ func (t *NetEvent) handleGetBlock(ctx xctx.XContext,
 
 	ledgerReader := reader.NewLedgerReader(chain.Context(), ctx)
 	if input.Blockid != nil {
-		output, err = ledgerReader.QueryBlock(input.Blockid, input.NeedContent)
+		key := blockResponderKey(bcName, chain.Context().Ledger.GetMeta().GetTipBlockid(), input.Blockid, input.NeedContent)
+		output, err = t.blockResponder.get(key, func() (*xpb.BlockInfo, error) {
+			return ledgerReader.QueryBlock(input.Blockid, input.NeedContent)
+		})
 		if err != nil {
 			ctx.GetLog().Error("ledger reader query block error", "error", err)
 			return response(err)
@@ -518,6 +536,35 @@ func (t *NetEvent) handleGetChainStatus(ctx xctx.XContext, request *protos.Xuper
 	return response(nil)
 }
 
+func (t *NetEvent) handleGetCheckpoint(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
+	var output *xpb.Checkpoint
+
+	bcName := request.GetHeader().GetBcname()
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", request.Header.Bcname)
+		return response(common.ErrChainNotExist)
+	}
+
+	output, err = chain.LatestCheckpoint()
+	if err != nil {
+		ctx.GetLog().Debug("handleGetCheckpoint error", "bcName", bcName, "error", err)
+		return response(err)
+	}
+
+	return response(nil)
+}
+
 func (t *NetEvent) handleConfirmChainStatus(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
 	var input lpb.InternalBlock
 	var output *xpb.TipStatus
@@ -554,6 +601,8 @@ func (t *NetEvent) handleConfirmChainStatus(ctx xctx.XContext, request *protos.X
 
 	output = &xpb.TipStatus{
 		IsTrunkTip: false,
+		TipBlockid: chainStatus.LedgerMeta.GetTipBlockid(),
+		TipHeight:  chainStatus.LedgerMeta.GetTrunkHeight(),
 	}
 	if bytes.Equal(input.Blockid, chainStatus.LedgerMeta.TipBlockid) {
 		output.IsTrunkTip = true
@@ -0,0 +1,41 @@
+package xuperos
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// errorLogger 记录Error级别的日志
+type errorLogger struct {
+	logs.Logger
+	errors []string
+}
+
+func (l *errorLogger) Error(msg string, ctx ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprint(append([]interface{}{msg}, ctx...)...))
+}
+
+func TestProcAsyncMsgRecover(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "xuperos")
+	logger := &errorLogger{Logger: log}
+	// 未设置engine，handler查询链时panic
+	ev := &NetEvent{log: logger}
+
+	request := p2p.NewMessage(protos.XuperMessage_POSTTX, &lpb.Transaction{Txid: []byte("tx")})
+	ev.procAsyncMsg(request)
+
+	if len(logger.errors) != 1 {
+		t.Fatalf("expect one panic log, got %v", logger.errors)
+	}
+	if !strings.Contains(logger.errors[0], "POSTTX") || !strings.Contains(logger.errors[0], "procAsyncMsg") {
+		t.Fatalf("panic log should contain message type and stack: %s", logger.errors[0])
+	}
+}
@@ -16,6 +16,8 @@ var (
 	ErrBlockIDNil = errors.New("validation error: validateSendBlock Block.Blockid can't be null")
 	// ErrBlockNil is returned when block is nil
 	ErrBlockNil = errors.New("validation error: validateSendBlock Block.Block can't be null")
+	// ErrBlockNoTx is returned when block carries no transaction
+	ErrBlockNoTx = errors.New("validation error: validateSendBlock Block.Transactions can't be empty")
 	// ErrTxInvalid is returned when tx invaild
 	ErrTxInvalid = errors.New("validation error: tx info is invaild")
 )
@@ -49,5 +51,10 @@ func validateSendBlock(block *lpb.InternalBlock) error {
 		return ErrBlockIDNil
 	}
 
+	// 区块至少包含矿工奖励交易
+	if len(block.Transactions) == 0 {
+		return ErrBlockNoTx
+	}
+
 	return nil
 }
@@ -74,7 +74,8 @@ func (p *paraChainContract) handleCreateChain(ctx common.TaskContext) error {
 		return err
 	}
 	// 查看当前节点是否有权限创建/获取该平行链
-	haveAccess := isContain(args.Group.Admin, p.ChainCtx.Address.Address) || isContain(args.Group.Identities, p.ChainCtx.Address.Address)
+	addr := p.ChainCtx.Address.Snapshot()
+	haveAccess := isContain(args.Group.Admin, addr.Address) || isContain(args.Group.Identities, addr.Address)
 	if !haveAccess {
 		return nil
 	}
@@ -122,7 +123,8 @@ func (p *paraChainContract) handleRefreshChain(ctx common.TaskContext) error {
 		return err
 	}
 	// 根据当前节点目前是否有权限获取该链，决定当前是停掉链还是加载链
-	haveAccess := isContain(args.Group.Admin, p.ChainCtx.Address.Address) || isContain(args.Group.Identities, p.ChainCtx.Address.Address)
+	addr := p.ChainCtx.Address.Snapshot()
+	haveAccess := isContain(args.Group.Admin, addr.Address) || isContain(args.Group.Identities, addr.Address)
 	if haveAccess {
 		return p.doCreateChain(args.BcName, args.GenesisConfig)
 	}
@@ -2,6 +2,7 @@ package reader
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
@@ -21,6 +22,27 @@ type ChainReader interface {
 	GetNetURL() (string, error)
 	// 获取共识状态
 	GetConsensusStatus() (*xpb.ConsensusStatus, error)
+	// 获取结构化的共识状态，便于浏览器等工具直接使用
+	GetConsensusDetail() (*ConsensusDetail, error)
+}
+
+// ValidatorsDetail 解析后的验证人信息，兼容各共识ValidatorsInfo的json格式
+type ValidatorsDetail struct {
+	Validators []string `json:"validators"`
+	Miner      string   `json:"miner,omitempty"`
+	Curterm    int64    `json:"curterm,omitempty"`
+	Contract   string   `json:"contract,omitempty"`
+}
+
+// ConsensusDetail 结构化的共识状态
+type ConsensusDetail struct {
+	Version       int64            `json:"version"`
+	ConsensusName string           `json:"consensusName"`
+	StartHeight   int64            `json:"startHeight"`
+	CurrentTerm   int64            `json:"currentTerm"`
+	Validators    ValidatorsDetail `json:"validatorsDetail"`
+	// 原始的验证人信息，保持向后兼容
+	ValidatorsInfo string `json:"validatorsInfo"`
 }
 
 type chainReader struct {
@@ -43,10 +65,38 @@ func NewChainReader(chainCtx *common.ChainCtx, baseCtx xctx.XContext) ChainReade
 	return reader
 }
 
+// 读取链状态期间账本或状态机tip变化时的最大重读次数
+const chainStatusReadRetry = 5
+
+// GetChainStatus 获取链状态，账本meta、状态机meta、tip区块及分支信息对应同一时刻。
+// 各字段分别读取，读取完成后重新检查账本tip和状态机tip，读取期间二者均未变化才返回，
+// 否则重新读取；连续多次均有区块确认时返回ErrChainStatus
 func (t *chainReader) GetChainStatus() (*xpb.ChainStatus, error) {
+	return snapshotChainStatus(t.readChainStatus, func() ([]byte, []byte) {
+		return t.chainCtx.Ledger.GetMeta().GetTipBlockid(), t.chainCtx.State.GetLatestBlockid()
+	})
+}
+
+// snapshotChainStatus 读取链状态，直到读取前后账本tip和状态机tip均未变化
+func snapshotChainStatus(read func() (*xpb.ChainStatus, error), tips func() ([]byte, []byte)) (*xpb.ChainStatus, error) {
+	for i := 0; i < chainStatusReadRetry; i++ {
+		status, err := read()
+		if err != nil {
+			return nil, err
+		}
+		ledgerTip, stateTip := tips()
+		if bytes.Equal(ledgerTip, status.GetLedgerMeta().GetTipBlockid()) &&
+			bytes.Equal(stateTip, status.GetUtxoMeta().GetLatestBlockid()) {
+			return status, nil
+		}
+	}
+	return nil, common.ErrChainStatus
+}
+
+func (t *chainReader) readChainStatus() (*xpb.ChainStatus, error) {
 	chainStatus := &xpb.ChainStatus{}
-	chainStatus.LedgerMeta = t.chainCtx.Ledger.GetMeta()
 	chainStatus.UtxoMeta = t.chainCtx.State.GetMeta()
+	chainStatus.LedgerMeta = t.chainCtx.Ledger.GetMeta()
 	branchIds, err := t.chainCtx.Ledger.GetBranchInfo([]byte("0"), int64(0))
 	if err != nil {
 		t.log.Warn("get branch info error", "err", err)
@@ -83,6 +133,30 @@ func (t *chainReader) GetConsensusStatus() (*xpb.ConsensusStatus, error) {
 	return status, nil
 }
 
+func (t *chainReader) GetConsensusDetail() (*ConsensusDetail, error) {
+	consensus, err := t.chainCtx.Consensus.GetConsensusStatus()
+	if err != nil {
+		t.log.Warn("get consensus info error", "err", err)
+		return nil, common.ErrConsensusStatus
+	}
+
+	validatorsInfo := consensus.GetCurrentValidatorsInfo()
+	detail := &ConsensusDetail{
+		Version:        consensus.GetVersion(),
+		ConsensusName:  consensus.GetConsensusName(),
+		StartHeight:    consensus.GetConsensusBeginInfo(),
+		CurrentTerm:    consensus.GetCurrentTerm(),
+		ValidatorsInfo: string(validatorsInfo),
+	}
+	if len(validatorsInfo) > 0 {
+		if err := json.Unmarshal(validatorsInfo, &detail.Validators); err != nil {
+			t.log.Warn("unmarshal validators info error", "err", err)
+			return nil, common.ErrConsensusStatus
+		}
+	}
+	return detail, nil
+}
+
 func (t *chainReader) IsTrunkTipBlock(blkId []byte) (bool, error) {
 	meta := t.chainCtx.Ledger.GetMeta()
 	if bytes.Equal(meta.TipBlockid, blkId) {
@@ -109,6 +183,13 @@ func (t *chainReader) GetSystemStatus() (*xpb.SystemStatus, error) {
 	}
 	systemStatus.PeerUrls = peerUrls
 
+	if chainM := t.chainCtx.EngCtx.ChainM; chainM != nil {
+		if chain, err := chainM.Get(t.chainCtx.BCName); err == nil {
+			systemStatus.MiningPaused = chain.IsMiningPaused()
+			systemStatus.Partitioned = chain.IsPartitioned()
+		}
+	}
+
 	return systemStatus, nil
 }
 
@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+func TestSnapshotChainStatus(t *testing.T) {
+	var ledgerHeight, stateHeight int64
+	tipOf := func(height int64) []byte {
+		return []byte(fmt.Sprintf("block-%d", height))
+	}
+	read := func() (*xpb.ChainStatus, error) {
+		ledgerMeta := &lpb.LedgerMeta{TrunkHeight: atomic.LoadInt64(&ledgerHeight)}
+		ledgerMeta.TipBlockid = tipOf(ledgerMeta.TrunkHeight)
+		// 模拟读取各字段之间发生了区块确认
+		time.Sleep(100 * time.Microsecond)
+		block := &lpb.InternalBlock{Height: ledgerMeta.TrunkHeight, Blockid: ledgerMeta.TipBlockid}
+		time.Sleep(100 * time.Microsecond)
+		utxoMeta := &lpb.UtxoMeta{LatestBlockid: tipOf(atomic.LoadInt64(&stateHeight))}
+		return &xpb.ChainStatus{LedgerMeta: ledgerMeta, UtxoMeta: utxoMeta, Block: block}, nil
+	}
+	tips := func() ([]byte, []byte) {
+		return tipOf(atomic.LoadInt64(&ledgerHeight)), tipOf(atomic.LoadInt64(&stateHeight))
+	}
+
+	// 模拟矿工确认区块：先写账本，间隔一段时间后写状态机
+	stop := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			atomic.AddInt64(&ledgerHeight, 1)
+			time.Sleep(300 * time.Microsecond)
+			atomic.AddInt64(&stateHeight, 1)
+			time.Sleep(2 * time.Millisecond)
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		status, err := snapshotChainStatus(read, tips)
+		if err != nil {
+			continue
+		}
+		var stateHeight int64
+		fmt.Sscanf(string(status.UtxoMeta.LatestBlockid), "block-%d", &stateHeight)
+		// 状态机只会落后于账本正在执行的区块，不会超前于账本tip
+		if lag := status.LedgerMeta.TrunkHeight - stateHeight; lag < 0 || lag > 1 {
+			t.Fatalf("inconsistent status, ledger height: %d, state height: %d",
+				status.LedgerMeta.TrunkHeight, stateHeight)
+		}
+		if status.Block.Height != status.LedgerMeta.TrunkHeight {
+			t.Fatalf("tip block %d mismatch ledger height %d", status.Block.Height, status.LedgerMeta.TrunkHeight)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	// tip持续变化时不返回不一致的结果
+	moving := func() ([]byte, []byte) {
+		return tipOf(atomic.AddInt64(&ledgerHeight, 1)), tipOf(atomic.AddInt64(&stateHeight, 1))
+	}
+	if _, err := snapshotChainStatus(read, moving); err != common.ErrChainStatus {
+		t.Fatalf("expect ErrChainStatus, got %v", err)
+	}
+}
+
+// fixedConsensusStatus 返回固定的共识状态
+type fixedConsensusStatus struct {
+	consensus.ConsensusStatus
+	validatorsInfo []byte
+}
+
+func (s *fixedConsensusStatus) GetVersion() int64                { return 2 }
+func (s *fixedConsensusStatus) GetConsensusBeginInfo() int64     { return 100 }
+func (s *fixedConsensusStatus) GetConsensusName() string         { return "tdpos" }
+func (s *fixedConsensusStatus) GetCurrentTerm() int64            { return 7 }
+func (s *fixedConsensusStatus) GetCurrentValidatorsInfo() []byte { return s.validatorsInfo }
+
+type statusConsensus struct {
+	consensus.PluggableConsensusInterface
+	status *fixedConsensusStatus
+}
+
+func (c *statusConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error) {
+	return c.status, nil
+}
+
+func TestGetConsensusDetail(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "reader")
+	status := &fixedConsensusStatus{
+		validatorsInfo: []byte(`{"validators":["addr1","addr2"],"miner":"addr2","curterm":7,"contract":"tdpos"}`),
+	}
+	reader := &chainReader{
+		chainCtx: &common.ChainCtx{Consensus: &statusConsensus{status: status}},
+		log:      log,
+	}
+
+	detail, err := reader.GetConsensusDetail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Version != 2 || detail.ConsensusName != "tdpos" || detail.StartHeight != 100 || detail.CurrentTerm != 7 {
+		t.Fatalf("unexpected consensus detail: %+v", detail)
+	}
+	v := detail.Validators
+	if len(v.Validators) != 2 || v.Validators[1] != "addr2" || v.Miner != "addr2" || v.Curterm != 7 || v.Contract != "tdpos" {
+		t.Fatalf("unexpected validators detail: %+v", v)
+	}
+	// 保留原始的验证人信息
+	if detail.ValidatorsInfo != string(status.validatorsInfo) {
+		t.Fatalf("raw validators info changed: %s", detail.ValidatorsInfo)
+	}
+
+	// 没有验证人信息时返回空的解析结果
+	status.validatorsInfo = nil
+	if detail, err = reader.GetConsensusDetail(); err != nil || len(detail.Validators.Validators) != 0 {
+		t.Fatalf("unexpected detail without validators info: %+v %v", detail, err)
+	}
+
+	status.validatorsInfo = []byte("not json")
+	if _, err := reader.GetConsensusDetail(); err != common.ErrConsensusStatus {
+		t.Fatalf("expect ErrConsensusStatus, got %v", err)
+	}
+}
@@ -230,6 +230,8 @@ func (m *ChainStatus) GetBranchIds() []string {
 type SystemStatus struct {
 	ChainStatus          *ChainStatus `protobuf:"bytes,1,opt,name=chain_status,json=chainStatus,proto3" json:"chain_status,omitempty"`
 	PeerUrls             []string     `protobuf:"bytes,2,rep,name=peer_urls,json=peerUrls,proto3" json:"peer_urls,omitempty"`
+	MiningPaused         bool         `protobuf:"varint,3,opt,name=mining_paused,json=miningPaused,proto3" json:"mining_paused,omitempty"`
+	Partitioned          bool         `protobuf:"varint,4,opt,name=partitioned,proto3" json:"partitioned,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -274,8 +276,24 @@ func (m *SystemStatus) GetPeerUrls() []string {
 	return nil
 }
 
+func (m *SystemStatus) GetMiningPaused() bool {
+	if m != nil {
+		return m.MiningPaused
+	}
+	return false
+}
+
+func (m *SystemStatus) GetPartitioned() bool {
+	if m != nil {
+		return m.Partitioned
+	}
+	return false
+}
+
 type TipStatus struct {
 	IsTrunkTip           bool     `protobuf:"varint,1,opt,name=is_trunk_tip,json=isTrunkTip,proto3" json:"is_trunk_tip,omitempty"`
+	TipBlockid           []byte   `protobuf:"bytes,2,opt,name=tip_blockid,json=tipBlockid,proto3" json:"tip_blockid,omitempty"`
+	TipHeight            int64    `protobuf:"varint,3,opt,name=tip_height,json=tipHeight,proto3" json:"tip_height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -313,6 +331,20 @@ func (m *TipStatus) GetIsTrunkTip() bool {
 	return false
 }
 
+func (m *TipStatus) GetTipBlockid() []byte {
+	if m != nil {
+		return m.TipBlockid
+	}
+	return nil
+}
+
+func (m *TipStatus) GetTipHeight() int64 {
+	if m != nil {
+		return m.TipHeight
+	}
+	return 0
+}
+
 type BlockID struct {
 	Bcname  string `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
 	Blockid []byte `protobuf:"bytes,2,opt,name=blockid,proto3" json:"blockid,omitempty"`
@@ -620,6 +652,87 @@ func (m *GetBlockTxsResponse) GetTxs() []*xldgpb.Transaction {
 	return nil
 }
 
+// 由检查点授权节点签名的区块，快速同步时可据此信任该区块之前的链
+type Checkpoint struct {
+	Bcname  string `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Height  int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Blockid []byte `protobuf:"bytes,3,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	// 签名节点地址及公钥
+	Address              string   `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	PublicKey            string   `protobuf:"bytes,5,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,6,opt,name=sign,proto3" json:"sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Checkpoint) Reset()         { *m = Checkpoint{} }
+func (m *Checkpoint) String() string { return proto.CompactTextString(m) }
+func (*Checkpoint) ProtoMessage()    {}
+func (*Checkpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{12}
+}
+
+func (m *Checkpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Checkpoint.Unmarshal(m, b)
+}
+func (m *Checkpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Checkpoint.Marshal(b, m, deterministic)
+}
+func (m *Checkpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checkpoint.Merge(m, src)
+}
+func (m *Checkpoint) XXX_Size() int {
+	return xxx_messageInfo_Checkpoint.Size(m)
+}
+func (m *Checkpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checkpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checkpoint proto.InternalMessageInfo
+
+func (m *Checkpoint) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *Checkpoint) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetBlockid() []byte {
+	if m != nil {
+		return m.Blockid
+	}
+	return nil
+}
+
+func (m *Checkpoint) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Checkpoint) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *Checkpoint) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Transactions)(nil), "protos.Transactions")
 	proto.RegisterType((*TxInfo)(nil), "protos.TxInfo")
@@ -633,6 +746,7 @@ func init() {
 	proto.RegisterType((*GetBlockHeaderResponse)(nil), "protos.GetBlockHeaderResponse")
 	proto.RegisterType((*GetBlockTxsRequest)(nil), "protos.GetBlockTxsRequest")
 	proto.RegisterType((*GetBlockTxsResponse)(nil), "protos.GetBlockTxsResponse")
+	proto.RegisterType((*Checkpoint)(nil), "protos.Checkpoint")
 }
 
 func init() {
@@ -640,47 +754,55 @@ func init() {
 }
 
 var fileDescriptor_e9685bde11a1952e = []byte{
-	// 672 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x51, 0x6b, 0xdb, 0x3a,
-	0x14, 0x26, 0x71, 0x9a, 0xc6, 0xc7, 0xb9, 0x6d, 0x51, 0x69, 0xf1, 0xed, 0xe5, 0x42, 0xea, 0xad,
-	0x2c, 0x50, 0x9a, 0xd0, 0x94, 0xed, 0x61, 0xf4, 0xa9, 0x1d, 0xb4, 0x85, 0x6d, 0x0f, 0x6e, 0x0a,
-	0x63, 0x83, 0x19, 0xc5, 0x3e, 0x4d, 0x44, 0x1d, 0xc9, 0x93, 0xe4, 0xe2, 0x8d, 0xfd, 0x96, 0xfd,
-	0x93, 0xfd, 0xb7, 0x61, 0xc9, 0x4e, 0xb3, 0xb2, 0xac, 0x0f, 0xc6, 0x3a, 0x47, 0xdf, 0xa7, 0xef,
-	0x3b, 0xc7, 0xf2, 0x81, 0xe7, 0x77, 0x28, 0x39, 0xa6, 0x43, 0xe4, 0x53, 0xc6, 0x51, 0x0d, 0x8b,
-	0x3c, 0x43, 0x29, 0xd4, 0xb0, 0xc8, 0x26, 0xe5, 0x33, 0xc8, 0xa4, 0xd0, 0x82, 0xb4, 0xcd, 0x4b,
-	0xed, 0x1d, 0x9b, 0xed, 0x58, 0x48, 0x1c, 0x4e, 0x62, 0x35, 0x4c, 0x31, 0x99, 0xa2, 0x1c, 0x16,
-	0x8b, 0x77, 0x32, 0x2d, 0x69, 0x36, 0xb4, 0xd4, 0xe0, 0x25, 0x74, 0xc7, 0x92, 0x72, 0x45, 0x63,
-	0xcd, 0x04, 0x57, 0xe4, 0x00, 0x1c, 0x5d, 0x28, 0xbf, 0xd1, 0x73, 0xfa, 0xde, 0x68, 0x7b, 0x60,
-	0x39, 0x83, 0x25, 0x48, 0x58, 0xee, 0x07, 0xdf, 0xa1, 0x3d, 0x2e, 0xae, 0xf8, 0xad, 0x20, 0xc7,
-	0xd0, 0x56, 0x9a, 0xea, 0xbc, 0xe4, 0x34, 0xfa, 0x1b, 0xa3, 0x7f, 0xff, 0xc0, 0xb9, 0x36, 0x80,
-	0xb0, 0x02, 0x92, 0x3d, 0xe8, 0x24, 0x4c, 0x69, 0xca, 0x63, 0xf4, 0x9b, 0xbd, 0x46, 0xdf, 0x09,
-	0x17, 0x31, 0x79, 0x06, 0x4d, 0x5d, 0xf8, 0x4e, 0xaf, 0xb1, 0x4a, 0xbe, 0xa9, 0x8b, 0x00, 0xc1,
-	0x3d, 0x4b, 0x45, 0x7c, 0x67, 0x0c, 0x1c, 0x3e, 0x32, 0xb0, 0x60, 0x19, 0xc8, 0x23, 0xe9, 0x43,
-	0x58, 0x9b, 0x94, 0x69, 0xa3, 0xeb, 0x8d, 0x76, 0x6a, 0xec, 0x15, 0xd7, 0x28, 0x39, 0x4d, 0x0d,
-	0x27, 0xb4, 0x98, 0xe0, 0x67, 0x03, 0xbc, 0xf3, 0x19, 0x65, 0x95, 0x7f, 0x72, 0x02, 0x9e, 0xed,
-	0x5d, 0x34, 0x47, 0x4d, 0x8d, 0x9c, 0x37, 0x22, 0xf5, 0x11, 0x6f, 0xcd, 0xd6, 0x3b, 0xd4, 0x34,
-	0x84, 0x74, 0xb1, 0x26, 0x47, 0xe0, 0xe6, 0xba, 0x10, 0x96, 0x62, 0x55, 0xb7, 0x6a, 0xca, 0x8d,
-	0x2e, 0x84, 0x21, 0x74, 0xf2, 0x6a, 0xf5, 0x60, 0xd0, 0x79, 0xda, 0x20, 0xf9, 0x1f, 0x60, 0x22,
-	0x29, 0x8f, 0x67, 0x11, 0x4b, 0x94, 0xdf, 0xea, 0x39, 0x7d, 0x37, 0x74, 0x6d, 0xe6, 0x2a, 0x51,
-	0x41, 0x0c, 0xdd, 0xeb, 0xaf, 0x4a, 0xe3, 0xbc, 0xf2, 0xff, 0x0a, 0xba, 0x71, 0x59, 0x4e, 0xb4,
-	0xd4, 0xaf, 0xb2, 0xcb, 0xf6, 0xf6, 0x0c, 0x96, 0x4a, 0x0d, 0xbd, 0x78, 0xa9, 0xee, 0xff, 0xc0,
-	0xcd, 0x10, 0x65, 0x94, 0xcb, 0x54, 0xf9, 0x4d, 0xa3, 0xd2, 0x29, 0x13, 0x37, 0x32, 0x55, 0xc1,
-	0x11, 0xb8, 0x63, 0x96, 0x55, 0xc8, 0x1e, 0x74, 0x99, 0x8a, 0xb4, 0xcc, 0xf9, 0x5d, 0xa4, 0x59,
-	0x66, 0x14, 0x3a, 0x21, 0x30, 0x35, 0x2e, 0x53, 0x63, 0x96, 0x05, 0x9f, 0x61, 0xdd, 0x7e, 0xba,
-	0x37, 0x64, 0x17, 0xda, 0x93, 0x98, 0xd3, 0x39, 0x1a, 0x98, 0x1b, 0x56, 0x11, 0xf1, 0x61, 0xdd,
-	0x94, 0xc7, 0x12, 0xd3, 0xaf, 0x6e, 0x58, 0x87, 0x64, 0x1f, 0xba, 0x1c, 0x31, 0x89, 0x62, 0xc1,
-	0x35, 0x72, 0x6d, 0x7a, 0xd4, 0x09, 0xbd, 0x32, 0x77, 0x6e, 0x53, 0xc1, 0x8f, 0x06, 0x6c, 0x9e,
-	0x0b, 0xae, 0x90, 0xab, 0x5c, 0x55, 0xae, 0x7c, 0x58, 0xbf, 0x47, 0xa9, 0x98, 0xe0, 0x95, 0x52,
-	0x1d, 0x92, 0x03, 0xd8, 0x88, 0x6b, 0x70, 0x64, 0xac, 0x34, 0x0d, 0xe0, 0x9f, 0x45, 0xf6, 0x7d,
-	0xe9, 0x68, 0x1f, 0xba, 0x4a, 0x53, 0xa9, 0xa3, 0x19, 0xb2, 0xe9, 0xcc, 0xea, 0xba, 0xa1, 0x67,
-	0x72, 0x97, 0x26, 0x45, 0x5e, 0xc0, 0xe6, 0x3d, 0x4d, 0x59, 0x42, 0xb5, 0x90, 0x2a, 0x62, 0xfc,
-	0x56, 0xf8, 0x2d, 0x83, 0xda, 0x78, 0x48, 0x97, 0xd7, 0x35, 0xf8, 0x04, 0x3b, 0x17, 0xa8, 0x4d,
-	0x0f, 0x2e, 0x91, 0x26, 0x28, 0x43, 0xfc, 0x92, 0xa3, 0xd2, 0x2b, 0xdb, 0xb1, 0x0b, 0xed, 0x4a,
-	0xd6, 0xfe, 0x2b, 0x55, 0x44, 0x08, 0xb4, 0x14, 0xfb, 0x86, 0xc6, 0x8c, 0x13, 0x9a, 0x75, 0x70,
-	0x01, 0xbb, 0x8f, 0x0f, 0x57, 0x59, 0x59, 0x0a, 0x39, 0x82, 0xb6, 0xe9, 0x62, 0xfd, 0x6b, 0xaf,
-	0xb8, 0x58, 0x15, 0x28, 0xf8, 0x00, 0xa4, 0x3e, 0x68, 0x5c, 0xa8, 0xa7, 0x2c, 0xae, 0xfe, 0x62,
-	0x5b, 0x76, 0x9c, 0x38, 0x3d, 0xa7, 0xbf, 0x66, 0x27, 0xc7, 0x29, 0x6c, 0xff, 0x76, 0x72, 0xe5,
-	0xaf, 0x9a, 0x3b, 0xad, 0xbf, 0xcf, 0x9d, 0xb3, 0xd3, 0x8f, 0xaf, 0xa7, 0x4c, 0xcf, 0xf2, 0xc9,
-	0x20, 0x16, 0x73, 0x3b, 0x0d, 0xcd, 0x4d, 0x1d, 0x3e, 0x4c, 0xbe, 0xd5, 0x13, 0x73, 0x62, 0xe7,
-	0xe4, 0xc9, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x5b, 0x60, 0xeb, 0x04, 0x56, 0x05, 0x00, 0x00,
+	// 789 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x5b, 0x8b, 0xdb, 0x46,
+	0x14, 0xc6, 0xd6, 0xae, 0xd7, 0x3a, 0x52, 0x36, 0x61, 0x42, 0x16, 0x35, 0xa5, 0xd4, 0x51, 0x1a,
+	0x6a, 0x08, 0xb1, 0xc9, 0x86, 0xf6, 0xa1, 0xe4, 0x69, 0xb7, 0x90, 0x2c, 0xbd, 0x50, 0x14, 0x07,
+	0x4a, 0x0b, 0x15, 0x23, 0xe9, 0xc4, 0x1e, 0x2c, 0x8f, 0xa6, 0x33, 0xa3, 0x45, 0x5b, 0xfa, 0x5b,
+	0xfa, 0xd2, 0xb7, 0xfe, 0x87, 0xfe, 0xb7, 0x32, 0x17, 0x79, 0xbd, 0x4b, 0xcd, 0x92, 0x07, 0xa1,
+	0x39, 0xdf, 0x9c, 0xcb, 0x77, 0xbe, 0xb9, 0xc1, 0x17, 0x6b, 0x94, 0x1c, 0xeb, 0x39, 0xf2, 0x25,
+	0xe3, 0xa8, 0xe6, 0x5d, 0x2b, 0x50, 0x36, 0x6a, 0xde, 0x89, 0xc2, 0x7c, 0x33, 0x21, 0x1b, 0xdd,
+	0x90, 0x91, 0xfd, 0xa9, 0xc7, 0x2f, 0xed, 0x74, 0xd9, 0x48, 0x9c, 0x17, 0xa5, 0x9a, 0xd7, 0x58,
+	0x2d, 0x51, 0xce, 0xbb, 0xed, 0xbf, 0x5a, 0x8a, 0xa2, 0x37, 0x5d, 0x68, 0xfa, 0x15, 0xc4, 0x0b,
+	0x49, 0xb9, 0xa2, 0xa5, 0x66, 0x0d, 0x57, 0xe4, 0x19, 0x04, 0xba, 0x53, 0xc9, 0x60, 0x12, 0x4c,
+	0xa3, 0xd3, 0x87, 0x33, 0x17, 0x33, 0xdb, 0x71, 0xc9, 0xcc, 0x7c, 0xfa, 0x27, 0x8c, 0x16, 0xdd,
+	0x05, 0xff, 0xd0, 0x90, 0x97, 0x30, 0x52, 0x9a, 0xea, 0xd6, 0xc4, 0x0c, 0xa6, 0xc7, 0xa7, 0x9f,
+	0xfc, 0x4f, 0xcc, 0x3b, 0xeb, 0x90, 0x79, 0x47, 0xf2, 0x18, 0xc6, 0x15, 0x53, 0x9a, 0xf2, 0x12,
+	0x93, 0xe1, 0x64, 0x30, 0x0d, 0xb2, 0xad, 0x4d, 0x9e, 0xc2, 0x50, 0x77, 0x49, 0x30, 0x19, 0xec,
+	0x2b, 0x3f, 0xd4, 0x5d, 0x8a, 0x10, 0x9e, 0xd5, 0x4d, 0xb9, 0xb6, 0x04, 0x9e, 0xdf, 0x22, 0xb0,
+	0x8d, 0xb2, 0x2e, 0xb7, 0x4a, 0x3f, 0x87, 0xc3, 0xc2, 0xc0, 0xb6, 0x6e, 0x74, 0xfa, 0xa8, 0xf7,
+	0xbd, 0xe0, 0x1a, 0x25, 0xa7, 0xb5, 0x8d, 0xc9, 0x9c, 0x4f, 0xfa, 0xef, 0x00, 0xa2, 0xf3, 0x15,
+	0x65, 0x9e, 0x3f, 0x79, 0x05, 0x91, 0xd3, 0x2e, 0xdf, 0xa0, 0xa6, 0xb6, 0x5c, 0x74, 0x4a, 0xfa,
+	0x14, 0xdf, 0xdb, 0xa9, 0x1f, 0x50, 0xd3, 0x0c, 0xea, 0xed, 0x98, 0xbc, 0x80, 0xb0, 0xd5, 0x5d,
+	0xe3, 0x42, 0x5c, 0xd5, 0x07, 0x7d, 0xc8, 0x7b, 0xdd, 0x35, 0x36, 0x60, 0xdc, 0xfa, 0xd1, 0x35,
+	0xc1, 0xe0, 0x6e, 0x82, 0xe4, 0x33, 0x80, 0x42, 0x52, 0x5e, 0xae, 0x72, 0x56, 0xa9, 0xe4, 0x60,
+	0x12, 0x4c, 0xc3, 0x2c, 0x74, 0xc8, 0x45, 0xa5, 0xd2, 0x7f, 0x06, 0x10, 0xbf, 0xbb, 0x52, 0x1a,
+	0x37, 0xbe, 0x81, 0xaf, 0x21, 0x2e, 0x4d, 0x3f, 0xf9, 0x8e, 0x60, 0x46, 0x66, 0xb7, 0x7d, 0x66,
+	0x3b, 0xbd, 0x66, 0x51, 0x79, 0x6d, 0x90, 0x4f, 0x21, 0x14, 0x88, 0x32, 0x6f, 0x65, 0xad, 0x92,
+	0xa1, 0x2d, 0x33, 0x36, 0xc0, 0x7b, 0x59, 0x2b, 0xf2, 0x14, 0xee, 0x6d, 0x18, 0x67, 0x7c, 0x99,
+	0x0b, 0xda, 0x2a, 0xac, 0x2c, 0xf3, 0x71, 0x16, 0x3b, 0xf0, 0x27, 0x8b, 0x91, 0x09, 0x44, 0x82,
+	0x4a, 0xcd, 0xcc, 0x12, 0x62, 0x95, 0x1c, 0x58, 0x97, 0x5d, 0x28, 0xdd, 0x40, 0xb8, 0x60, 0xc2,
+	0x17, 0x9c, 0x40, 0xcc, 0x54, 0xae, 0x65, 0xcb, 0xd7, 0xb9, 0x66, 0xc2, 0x12, 0x1d, 0x67, 0xc0,
+	0xd4, 0xc2, 0x40, 0x0b, 0x26, 0xc8, 0xe7, 0x10, 0x69, 0x26, 0x72, 0xab, 0x03, 0xab, 0xac, 0xb0,
+	0x71, 0x06, 0x9a, 0x89, 0x33, 0x87, 0x18, 0x6d, 0x8c, 0xc3, 0x0a, 0xd9, 0x72, 0xa5, 0x2d, 0xa7,
+	0x20, 0x0b, 0x35, 0x13, 0x6f, 0x2d, 0x90, 0xfe, 0x06, 0x47, 0x6e, 0x0b, 0x7d, 0x4b, 0x4e, 0x60,
+	0x54, 0x94, 0x9c, 0x6e, 0xd0, 0x96, 0x09, 0x33, 0x6f, 0x91, 0x04, 0x8e, 0x6e, 0xa6, 0xef, 0x4d,
+	0xf2, 0x04, 0x62, 0x8e, 0x58, 0xe5, 0x65, 0xc3, 0x35, 0x72, 0xed, 0x3b, 0x8e, 0x0c, 0x76, 0xee,
+	0xa0, 0xf4, 0xaf, 0x01, 0xdc, 0x3f, 0x6f, 0xb8, 0x42, 0xae, 0x5a, 0xe5, 0xbb, 0x4a, 0xe0, 0xe8,
+	0x12, 0xa5, 0x62, 0x0d, 0xf7, 0x95, 0x7a, 0x93, 0x3c, 0x83, 0xe3, 0xb2, 0x77, 0xce, 0x2d, 0x95,
+	0xa1, 0x75, 0xb8, 0xb7, 0x45, 0x7f, 0x34, 0x8c, 0x9e, 0x40, 0xac, 0x34, 0x95, 0x7a, 0xb7, 0xab,
+	0x30, 0x8b, 0x2c, 0xe6, 0xfa, 0x22, 0x5f, 0xc2, 0xfd, 0x4b, 0x5a, 0xb3, 0x8a, 0xea, 0x46, 0xaa,
+	0x9c, 0xf1, 0x0f, 0x8d, 0x15, 0x3b, 0xcc, 0x8e, 0xaf, 0x61, 0x73, 0x6c, 0xd2, 0x5f, 0xe1, 0xd1,
+	0x1b, 0xd4, 0x56, 0x83, 0xb7, 0x48, 0x2b, 0x94, 0x19, 0xfe, 0xde, 0xa2, 0xd2, 0x7b, 0xe5, 0x38,
+	0x81, 0x91, 0x2f, 0xeb, 0xce, 0xac, 0xb7, 0x08, 0x81, 0x03, 0xc5, 0xfe, 0x40, 0x2f, 0xb1, 0x1d,
+	0xa7, 0x6f, 0xe0, 0xe4, 0x76, 0x72, 0x25, 0x4c, 0x2b, 0xe4, 0x05, 0x8c, 0xac, 0x8a, 0xfd, 0x15,
+	0xb3, 0x67, 0x83, 0x7b, 0xa7, 0xf4, 0x67, 0x20, 0x7d, 0xa2, 0x45, 0xa7, 0xee, 0xa2, 0xb8, 0x7f,
+	0xc5, 0x1e, 0xb8, 0x6b, 0x2d, 0x98, 0x04, 0xd3, 0x43, 0x77, 0x83, 0xbd, 0x86, 0x87, 0x37, 0x32,
+	0x7b, 0x7e, 0xfe, 0xfe, 0x3b, 0xb8, 0xe3, 0xfe, 0xfb, 0x7b, 0x00, 0x70, 0xbe, 0xc2, 0x72, 0x2d,
+	0x1a, 0xc6, 0x3f, 0x5e, 0xb3, 0x1d, 0xa2, 0xc1, 0x4d, 0xa2, 0x09, 0x1c, 0xd1, 0xaa, 0x92, 0xa8,
+	0x94, 0x5f, 0xb7, 0xde, 0x34, 0x1b, 0x5a, 0xb4, 0x45, 0xcd, 0xca, 0x7c, 0x8d, 0x57, 0xc9, 0xa1,
+	0x9d, 0x0c, 0x1d, 0xf2, 0x1d, 0x5e, 0xb9, 0x65, 0x58, 0xf2, 0x64, 0x64, 0xf3, 0xd9, 0xf1, 0xd9,
+	0xeb, 0x5f, 0xbe, 0x59, 0x32, 0xbd, 0x6a, 0x8b, 0x59, 0xd9, 0x6c, 0xdc, 0xdb, 0x61, 0x8f, 0xf5,
+	0xfc, 0xfa, 0x9d, 0xd8, 0xff, 0xbe, 0x14, 0xee, 0x55, 0x79, 0xf5, 0xdf, 0x00, 0x84, 0xfe, 0xfa,
+	0xa0, 0x84, 0x06, 0x00, 0x00,
 }
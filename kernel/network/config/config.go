@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
 	"github.com/spf13/viper"
 )
 
@@ -22,8 +27,22 @@ const (
 	DefaultMaxBroadcastPeers = 20
 	DefaultServiceName       = "localhost"
 	DefaultIsBroadCast       = true
+	DefaultOversizeBanTime   = 600
+	DefaultDialTimeout       = 10
+	DefaultMaxConnectRetries = 10
+	DefaultReconnectBackoff  = 3
 )
 
+// limits of dial settings, values out of range are rejected at load time
+const (
+	maxDialTimeout      = 300
+	maxConnectRetries   = 100
+	maxReconnectBackoff = 600
+)
+
+// ModuleP2PV1 is the grpc based p2p module, whose peer addresses are host:port
+const ModuleP2PV1 = "p2pv1"
+
 // Config is the config of p2p server. Attention, config of dht are not expose
 type NetConf struct {
 	// Module is the name of p2p module plugin
@@ -48,8 +67,23 @@ type NetConf struct {
 	MaxStreamLimits int32 `yaml:"maxStreamLimits,omitempty"`
 	// maxMessageSize config the max message size
 	MaxMessageSize int64 `yaml:"maxMessageSize,omitempty"`
+	// MessageSizeLimits config the max message size in KB per message type, such as POSTTX,
+	// message types not listed are only limited by MaxMessageSize
+	MessageSizeLimits map[string]int64 `yaml:"messageSizeLimits,omitempty"`
+	// OversizeBanThreshold config how many oversized messages a peer may send before being banned, 0 means never ban
+	OversizeBanThreshold int `yaml:"oversizeBanThreshold,omitempty"`
+	// OversizeBanTime config how long in seconds a peer is banned
+	OversizeBanTime int64 `yaml:"oversizeBanTime,omitempty"`
 	// timeout config the timeout of Request with response
 	Timeout int64 `yaml:"timeout,omitempty"`
+	// DialTimeout config the timeout in seconds of a single dial to boot or static peer
+	DialTimeout int64 `yaml:"dialTimeout,omitempty"`
+	// MaxConnectRetries config how many rounds to dial boot and static peers before giving up
+	MaxConnectRetries int `yaml:"maxConnectRetries,omitempty"`
+	// ReconnectBackoff config the wait in seconds before the first retry, doubled after each retry
+	ReconnectBackoff int64 `yaml:"reconnectBackoff,omitempty"`
+	// MaxReconnectBackoff config the upper bound in seconds of the wait between retries
+	MaxReconnectBackoff int64 `yaml:"maxReconnectBackoff,omitempty"`
 	// StreamIPLimitSize set the limitation size for same ip
 	StreamIPLimitSize int64 `yaml:"streamIPLimitSize,omitempty"`
 	// MaxBroadcastPeers limit the number of common peers in a broadcast,
@@ -67,6 +101,10 @@ func LoadP2PConf(cfgFile string) (*NetConf, error) {
 	if err != nil {
 		return nil, fmt.Errorf("load p2p config failed.err:%s", err)
 	}
+	cfg.normalize()
+	if err = cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid p2p config.err:%s", err)
+	}
 
 	return cfg, nil
 }
@@ -82,7 +120,13 @@ func GetDefP2PConf() *NetConf {
 		IsHidden:        DefaultNetIsHidden,
 		MaxStreamLimits: DefaultMaxStreamLimits,
 		MaxMessageSize:  DefaultMaxMessageSize,
+		OversizeBanTime: DefaultOversizeBanTime,
 		Timeout:         DefaultTimeout,
+		// dial settings, backoff does not grow by default
+		DialTimeout:         DefaultDialTimeout,
+		MaxConnectRetries:   DefaultMaxConnectRetries,
+		ReconnectBackoff:    DefaultReconnectBackoff,
+		MaxReconnectBackoff: DefaultReconnectBackoff,
 		// default stream ip limit size
 		StreamIPLimitSize: DefaultStreamIPLimitSize,
 		MaxBroadcastPeers: DefaultMaxBroadcastPeers,
@@ -110,3 +154,89 @@ func (t *NetConf) loadConf(cfgFile string) error {
 
 	return nil
 }
+
+// normalize fills in values implied by other settings before validation,
+// maxReconnectBackoff below reconnectBackoff is raised to it, so raising only reconnectBackoff is allowed
+func (t *NetConf) normalize() {
+	if t.MaxReconnectBackoff < t.ReconnectBackoff {
+		t.MaxReconnectBackoff = t.ReconnectBackoff
+	}
+}
+
+// validate checks the listen address and peer addresses, both IPv4 and IPv6 are supported,
+// so that malformed addresses are rejected at load time rather than at dial time
+func (t *NetConf) validate() error {
+	addr, err := multiaddr.NewMultiaddr(t.Address)
+	if err != nil {
+		return fmt.Errorf("address %q is not a valid multiaddr: %v", t.Address, err)
+	}
+	if _, _, err = manet.DialArgs(addr); err != nil {
+		return fmt.Errorf("address %q is not a dialable multiaddr: %v", t.Address, err)
+	}
+
+	if err := t.validateDial(); err != nil {
+		return err
+	}
+
+	check := validatePeerMultiaddr
+	if t.Module == ModuleP2PV1 {
+		check = validatePeerHostPort
+	}
+	for _, peerAddr := range t.BootNodes {
+		if err := check(peerAddr); err != nil {
+			return fmt.Errorf("bootNodes: %v", err)
+		}
+	}
+	for bcname, peers := range t.StaticNodes {
+		for _, peerAddr := range peers {
+			if err := check(peerAddr); err != nil {
+				return fmt.Errorf("staticNodes of %s: %v", bcname, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDial checks the dial timeout and retry policy are in sane ranges
+func (t *NetConf) validateDial() error {
+	if t.DialTimeout <= 0 || t.DialTimeout > maxDialTimeout {
+		return fmt.Errorf("dialTimeout %d out of range (0, %d]", t.DialTimeout, maxDialTimeout)
+	}
+	if t.MaxConnectRetries <= 0 || t.MaxConnectRetries > maxConnectRetries {
+		return fmt.Errorf("maxConnectRetries %d out of range (0, %d]", t.MaxConnectRetries, maxConnectRetries)
+	}
+	if t.ReconnectBackoff <= 0 || t.ReconnectBackoff > maxReconnectBackoff {
+		return fmt.Errorf("reconnectBackoff %d out of range (0, %d]", t.ReconnectBackoff, maxReconnectBackoff)
+	}
+	if t.MaxReconnectBackoff < t.ReconnectBackoff || t.MaxReconnectBackoff > maxReconnectBackoff {
+		return fmt.Errorf("maxReconnectBackoff %d out of range [%d, %d]", t.MaxReconnectBackoff, t.ReconnectBackoff, maxReconnectBackoff)
+	}
+	return nil
+}
+
+// validatePeerMultiaddr checks a p2pv2 peer address, such as /ip6/::1/tcp/47101/p2p/Qm...
+func validatePeerMultiaddr(peerAddr string) error {
+	addr, err := multiaddr.NewMultiaddr(peerAddr)
+	if err != nil {
+		return fmt.Errorf("peer address %q is not a valid multiaddr: %v", peerAddr, err)
+	}
+	if _, err = peer.AddrInfoFromP2pAddr(addr); err != nil {
+		return fmt.Errorf("peer address %q has no valid peer id: %v", peerAddr, err)
+	}
+	return nil
+}
+
+// validatePeerHostPort checks a p2pv1 peer address, such as 127.0.0.1:47101 or [::1]:47101
+func validatePeerHostPort(peerAddr string) error {
+	host, port, err := net.SplitHostPort(peerAddr)
+	if err != nil {
+		return fmt.Errorf("peer address %q is not host:port: %v", peerAddr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("peer address %q has empty host", peerAddr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("peer address %q has invalid port", peerAddr)
+	}
+	return nil
+}
@@ -21,3 +21,65 @@ func TestLoadP2PConf(t *testing.T) {
 
 	fmt.Println(cfg)
 }
+
+func TestNetConfValidate(t *testing.T) {
+	const peerId = "Qmf2HeHe4sspGkfRCTq6257Vm3UHzvh2TeQJHHvHzzuFw6"
+	cases := []struct {
+		module    string
+		address   string
+		bootNodes []string
+		valid     bool
+	}{
+		{"p2pv2", "/ip4/127.0.0.1/tcp/47101", []string{"/ip4/127.0.0.1/tcp/47102/p2p/" + peerId}, true},
+		{"p2pv2", "/ip6/::1/tcp/47101", []string{"/ip6/::1/tcp/47102/p2p/" + peerId}, true},
+		{"p2pv2", "/ip6/::/tcp/47101", []string{"/dns4/node.example.com/tcp/47102/p2p/" + peerId}, true},
+		{"p2pv1", "/ip4/0.0.0.0/tcp/47101", []string{"127.0.0.1:47102", "[::1]:47102", "node:47102"}, true},
+		{"p2pv2", "127.0.0.1:47101", nil, false},
+		{"p2pv2", "/ip6/fe80::1::2/tcp/47101", nil, false},
+		{"p2pv2", "/ip4/127.0.0.1/tcp/47101", []string{"/ip4/127.0.0.1/tcp/47102"}, false},
+		{"p2pv2", "/ip4/127.0.0.1/tcp/47101", []string{"/ip6/::1::2/tcp/47102/p2p/" + peerId}, false},
+		{"p2pv1", "/ip4/127.0.0.1/tcp/47101", []string{"::1:47102"}, false},
+		{"p2pv1", "/ip4/127.0.0.1/tcp/47101", []string{"127.0.0.1:0"}, false},
+		{"p2pv1", "/ip4/127.0.0.1/tcp/47101", []string{":47102"}, false},
+	}
+	for i, c := range cases {
+		cfg := GetDefP2PConf()
+		cfg.Module = c.module
+		cfg.Address = c.address
+		cfg.BootNodes = c.bootNodes
+		err := cfg.validate()
+		if (err == nil) != c.valid {
+			t.Errorf("case %d: expect valid %v, got err %v", i, c.valid, err)
+		}
+	}
+
+	dials := []func(*NetConf){
+		func(c *NetConf) { c.DialTimeout = 0 },
+		func(c *NetConf) { c.DialTimeout = maxDialTimeout + 1 },
+		func(c *NetConf) { c.MaxConnectRetries = 0 },
+		func(c *NetConf) { c.ReconnectBackoff = 0 },
+		func(c *NetConf) { c.ReconnectBackoff, c.MaxReconnectBackoff = 10, 5 },
+		func(c *NetConf) { c.MaxReconnectBackoff = maxReconnectBackoff + 1 },
+	}
+	for i, set := range dials {
+		cfg := GetDefP2PConf()
+		set(cfg)
+		if err := cfg.validate(); err == nil {
+			t.Errorf("dial case %d: expect invalid", i)
+		}
+	}
+
+	// 只调大reconnectBackoff时上限随之提高
+	cfg := GetDefP2PConf()
+	cfg.ReconnectBackoff = DefaultReconnectBackoff + 10
+	cfg.normalize()
+	if err := cfg.validate(); err != nil || cfg.MaxReconnectBackoff != cfg.ReconnectBackoff {
+		t.Errorf("max backoff should be raised to reconnectBackoff, got %d %v", cfg.MaxReconnectBackoff, err)
+	}
+
+	cfg = GetDefP2PConf()
+	cfg.StaticNodes = map[string][]string{"xuper": {"/ip4/127.0.0.1/tcp/47102"}}
+	if err := cfg.validate(); err == nil {
+		t.Error("static node without peer id should be rejected")
+	}
+}
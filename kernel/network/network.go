@@ -72,6 +72,9 @@ type Network interface {
 
 	Context() *nctx.NetCtx
 	PeerInfo() pb.PeerInfo
+	PeerCapabilities(peerID string) []string
+	PeerCount() int
+	SetAccount(account string)
 }
 
 // 如果有领域内公共逻辑，可以在这层扩展，对上层暴露高级接口
@@ -163,6 +166,18 @@ func (t *NetworkImpl) PeerInfo() pb.PeerInfo {
 	return t.p2pServ.PeerInfo()
 }
 
+func (t *NetworkImpl) PeerCapabilities(peerID string) []string {
+	return t.p2pServ.PeerCapabilities(peerID)
+}
+
+func (t *NetworkImpl) PeerCount() int {
+	return t.p2pServ.PeerCount()
+}
+
+func (t *NetworkImpl) SetAccount(account string) {
+	t.p2pServ.SetAccount(account)
+}
+
 func (t *NetworkImpl) isInit() bool {
 	if t.ctx == nil || t.p2pServ == nil {
 		return false
@@ -62,6 +62,17 @@ func (t *MockP2PServ) PeerInfo() pb.PeerInfo {
 	return pb.PeerInfo{}
 }
 
+func (t *MockP2PServ) PeerCapabilities(string) []string {
+	return nil
+}
+
+func (t *MockP2PServ) PeerCount() int {
+	return 0
+}
+
+func (t *MockP2PServ) SetAccount(string) {
+}
+
 func TestNewNetwork(t *testing.T) {
 	mock.InitLogForTest()
 
@@ -0,0 +1,45 @@
+package p2p
+
+import (
+	"sort"
+	"sync"
+)
+
+// 节点能力协商
+// 新的网络特性（如批量拉取区块、消息压缩）上线时先注册对应的能力标识，
+// 本节点在握手时通过PeerInfo.Capabilities对外宣告，发送方调用PeerCapabilities
+// 判断对端是否支持，不支持时回退到老的协议，保证新老版本节点可以混合部署
+
+var (
+	capMutex     sync.RWMutex
+	capabilities = map[string]struct{}{}
+)
+
+// RegisterCapability 注册本节点支持的能力，需在网络启动前完成
+func RegisterCapability(name string) {
+	capMutex.Lock()
+	defer capMutex.Unlock()
+	capabilities[name] = struct{}{}
+}
+
+// LocalCapabilities 返回本节点支持的能力列表，按名称排序
+func LocalCapabilities() []string {
+	capMutex.RLock()
+	defer capMutex.RUnlock()
+	caps := make([]string, 0, len(capabilities))
+	for name := range capabilities {
+		caps = append(caps, name)
+	}
+	sort.Strings(caps)
+	return caps
+}
+
+// HasCapability 判断能力列表中是否包含指定能力
+func HasCapability(caps []string, name string) bool {
+	for _, c := range caps {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
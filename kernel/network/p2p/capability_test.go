@@ -0,0 +1,30 @@
+package p2p
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	RegisterCapability("test_cap_b")
+	RegisterCapability("test_cap_a")
+	RegisterCapability("test_cap_b")
+
+	caps := LocalCapabilities()
+	if !HasCapability(caps, "test_cap_a") || !HasCapability(caps, "test_cap_b") {
+		t.Fatalf("registered capabilities missing: %v", caps)
+	}
+	if HasCapability(caps, "test_cap_c") {
+		t.Fatalf("unexpected capability: %v", caps)
+	}
+
+	// 能力列表有序，便于对外宣告时保持稳定
+	if !reflect.DeepEqual(caps, LocalCapabilities()) || caps[0] > caps[len(caps)-1] {
+		t.Fatalf("capabilities not sorted: %v", caps)
+	}
+
+	// 老版本节点不宣告能力，需回退到老协议
+	if HasCapability(nil, "test_cap_a") {
+		t.Fatal("peer without capabilities should support nothing")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -141,10 +142,18 @@ func (d *dispatcher) Dispatch(msg *pb.XuperMessage, stream Stream) error {
 		d.parallel <- struct{}{}
 		wg.Add(1)
 		go func(sub Subscriber) {
-			defer wg.Done()
+			// 订阅者处理消息时panic不能影响其他订阅者和后续消息，并发名额需要归还
+			defer func() {
+				if r := recover(); r != nil {
+					ctx.GetLog().Error("Dispatch: subscriber panic", "bc", msg.GetHeader().GetBcname(),
+						"type", msg.GetHeader().GetType(), "from", msg.GetHeader().GetFrom(),
+						"panic", r, "stack", string(debug.Stack()))
+				}
+				<-d.parallel
+				wg.Done()
+			}()
 
 			sub.HandleMessage(ctx, msg, stream)
-			<-d.parallel
 		}(sub)
 	}
 	d.mu.RUnlock()
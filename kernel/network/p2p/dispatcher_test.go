@@ -3,6 +3,7 @@ package p2p
 import (
 	"testing"
 
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	nctx "github.com/xuperchain/xupercore/kernel/network/context"
 	pb "github.com/xuperchain/xupercore/protos"
@@ -121,3 +122,46 @@ func TestDispatcher(t *testing.T) {
 		}
 	}
 }
+
+type panicSubscriber struct {
+	typ pb.XuperMessage_MessageType
+}
+
+func (s *panicSubscriber) GetMessageType() pb.XuperMessage_MessageType { return s.typ }
+func (s *panicSubscriber) Match(*pb.XuperMessage) bool                 { return true }
+func (s *panicSubscriber) Dropped() uint64                             { return 0 }
+func (s *panicSubscriber) HandleMessage(xctx.XContext, *pb.XuperMessage, Stream) error {
+	panic("subscriber crashed")
+}
+
+func TestDispatcherSubscriberPanic(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := nctx.NewNetCtx(ecfg)
+
+	d := NewDispatcher(ctx).(*dispatcher)
+	ch := make(chan *pb.XuperMessage, 2)
+	if err := d.Register(&panicSubscriber{typ: pb.XuperMessage_SENDBLOCK}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Register(NewSubscriber(ctx, pb.XuperMessage_SENDBLOCK, ch)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其中一个订阅者panic不影响其他订阅者和后续消息的处理
+	for _, logid := range []string{"1", "2"} {
+		msg := NewMessage(pb.XuperMessage_SENDBLOCK, &pb.XuperMessage{}, WithLogId(logid))
+		if err := d.Dispatch(msg, &mockStream{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(ch) != 2 {
+		t.Fatalf("expect 2 messages delivered, got %d", len(ch))
+	}
+	if len(d.parallel) != 0 {
+		t.Fatalf("expect parallel slots released, got %d", len(d.parallel))
+	}
+}
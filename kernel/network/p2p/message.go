@@ -164,6 +164,7 @@ var requestToResponse = map[pb.XuperMessage_MessageType]pb.XuperMessage_MessageT
 	pb.XuperMessage_GET_AUTHENTICATION:       pb.XuperMessage_GET_AUTHENTICATION_RES,
 	pb.XuperMessage_GET_BLOCK_HEADERS:        pb.XuperMessage_GET_BLOCKS_HEADERS_RES,
 	pb.XuperMessage_GET_BLOCK_TXS:            pb.XuperMessage_GET_BLOCKS_TXS_RES,
+	pb.XuperMessage_GET_CHECKPOINT:           pb.XuperMessage_GET_CHECKPOINT_RES,
 }
 
 // GetRespMessageType get the message type
@@ -1,5 +1,14 @@
 package p2p
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotEnoughDelivered is returned when fewer peers than required accept a message
+var ErrNotEnoughDelivered = errors.New("message delivered to too few peers")
+
 type Option struct {
 	Filters   []FilterStrategy
 	Addresses []string
@@ -11,6 +20,13 @@ type Option struct {
 	Percent float32 // percent wait for return
 
 	Factor float64
+
+	// 带返回的请求收到MinResponses个有效返回后立即结束，其余请求被取消
+	MinResponses int
+	// 带返回的请求最长等待时间，超时后返回已收到的结果
+	ResponseDeadline time.Duration
+	// 不带返回的消息至少成功发送给MinDelivered个节点，否则返回错误，0表示尽力发送
+	MinDelivered int
 }
 
 // OptionFunc define single Option function for send message
@@ -63,6 +79,35 @@ func WithFactor(factor float64) OptionFunc {
 	}
 }
 
+// WithMinResponses return as soon as n valid responses arrive
+func WithMinResponses(n int) OptionFunc {
+	return func(o *Option) {
+		o.MinResponses = n
+	}
+}
+
+// WithResponseDeadline stop waiting for responses after d
+func WithResponseDeadline(d time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.ResponseDeadline = d
+	}
+}
+
+// WithMinDelivered require the message to be sent to at least n peers
+func WithMinDelivered(n int) OptionFunc {
+	return func(o *Option) {
+		o.MinDelivered = n
+	}
+}
+
+// CheckDelivered return ErrNotEnoughDelivered if the message reached fewer peers than required
+func (o *Option) CheckDelivered(delivered int) error {
+	if delivered < o.MinDelivered {
+		return fmt.Errorf("%w: delivered %d, required %d", ErrNotEnoughDelivered, delivered, o.MinDelivered)
+	}
+	return nil
+}
+
 // Apply apply OptionFunc
 func Apply(optFunc []OptionFunc) *Option {
 	opt := &Option{
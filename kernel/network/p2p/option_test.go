@@ -0,0 +1,29 @@
+package p2p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDelivered(t *testing.T) {
+	cases := []struct {
+		min       int
+		delivered int
+		err       error
+	}{
+		{0, 0, nil},
+		{0, 3, nil},
+		{2, 2, nil},
+		{2, 3, nil},
+		{3, 2, ErrNotEnoughDelivered},
+		{1, 0, ErrNotEnoughDelivered},
+	}
+
+	for i, c := range cases {
+		opt := Apply([]OptionFunc{WithMinDelivered(c.min)})
+		err := opt.CheckDelivered(c.delivered)
+		if !errors.Is(err, c.err) {
+			t.Errorf("case %d: expect %v, got %v", i, c.err, err)
+		}
+	}
+}
@@ -22,4 +22,10 @@ type Server interface {
 	Context() *nctx.NetCtx
 
 	PeerInfo() pb.PeerInfo
+	// PeerCapabilities 返回对端节点宣告的能力列表，未知或老版本节点返回空
+	PeerCapabilities(peerID string) []string
+	// PeerCount 返回当前已连接的邻居节点数，不查询邻居的详细信息
+	PeerCount() int
+	// SetAccount 替换本节点宣告的账户，出块密钥替换后调用
+	SetAccount(account string)
 }
@@ -0,0 +1,60 @@
+package p2p
+
+import (
+	"context"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// WithResponseContext 根据Option生成带返回请求的上下文，设置了ResponseDeadline时附带超时
+func WithResponseContext(parent context.Context, opt *Option) (context.Context, context.CancelFunc) {
+	if opt.ResponseDeadline > 0 {
+		return context.WithTimeout(parent, opt.ResponseDeadline)
+	}
+	return context.WithCancel(parent)
+}
+
+// CollectResponses 收集带返回请求的结果
+// 收到的有效返回达到阈值(MinResponses或按Percent计算)、全部请求结束(done关闭)或ctx结束时返回
+func CollectResponses(ctx context.Context, respCh <-chan *pb.XuperMessage, done <-chan struct{},
+	peerCount int, opt *Option) []*pb.XuperMessage {
+
+	threshold := int(float32(peerCount) * opt.Percent)
+	if opt.MinResponses > 0 && opt.MinResponses < threshold {
+		threshold = opt.MinResponses
+	}
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	response := make([]*pb.XuperMessage, 0, threshold)
+	accept := func(resp *pb.XuperMessage) bool {
+		if VerifyChecksum(resp) {
+			response = append(response, resp)
+		}
+		return len(response) >= threshold
+	}
+
+	for {
+		select {
+		case resp := <-respCh:
+			if accept(resp) {
+				return response
+			}
+		case <-done:
+			// 所有请求都已结束，取完channel中剩余的返回
+			for {
+				select {
+				case resp := <-respCh:
+					if accept(resp) {
+						return response
+					}
+				default:
+					return response
+				}
+			}
+		case <-ctx.Done():
+			return response
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func TestCollectResponses(t *testing.T) {
+	newResp := func() *pb.XuperMessage {
+		return NewMessage(pb.XuperMessage_GET_BLOCK_RES, &pb.XuperMessage{})
+	}
+
+	// 收到MinResponses个返回后立即结束
+	respCh := make(chan *pb.XuperMessage, 3)
+	respCh <- newResp()
+	respCh <- newResp()
+	opt := Apply([]OptionFunc{WithMinResponses(2)})
+	resp := CollectResponses(context.Background(), respCh, make(chan struct{}), 3, opt)
+	if len(resp) != 2 {
+		t.Fatalf("expect 2 responses, got %d", len(resp))
+	}
+
+	// 所有请求结束时返回已收到的结果
+	respCh <- newResp()
+	done := make(chan struct{})
+	close(done)
+	resp = CollectResponses(context.Background(), respCh, done, 3, Apply(nil))
+	if len(resp) != 1 {
+		t.Fatalf("expect 1 response, got %d", len(resp))
+	}
+
+	// 超时后返回已收到的结果
+	opt = Apply([]OptionFunc{WithResponseDeadline(10 * time.Millisecond)})
+	ctx, cancel := WithResponseContext(context.Background(), opt)
+	defer cancel()
+	respCh <- newResp()
+	resp = CollectResponses(ctx, respCh, make(chan struct{}), 3, opt)
+	if len(resp) != 1 {
+		t.Fatalf("expect 1 response, got %d", len(resp))
+	}
+}
@@ -0,0 +1,204 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/xuperchain/xupercore/kernel/network/config"
+	"github.com/xuperchain/xupercore/lib/metrics"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	ErrMessageTooLarge = errors.New("message too large")
+	ErrPeerBanned      = errors.New("peer banned")
+)
+
+// 消息大小限制
+// 传输层按MaxMessageSize在读取完整消息前拒绝超大的帧，SizeLimiter在分发前按消息类型
+// 再做一次更严格的检查（如交易消息远小于区块消息），避免业务层反序列化超大的数据；
+// 多次发送超限消息的节点在一段时间内被拒绝
+
+type offender struct {
+	count       int
+	lastOffense time.Time
+	bannedUntil time.Time
+}
+
+// expired 拒绝期已过且banDuration内没有新的超限消息，记录可以清除
+func (o *offender) expired(now time.Time, banDuration time.Duration) bool {
+	return !now.Before(o.bannedUntil) && now.Sub(o.lastOffense) >= banDuration
+}
+
+// SizeLimiter 按消息类型限制消息大小，并统计发送超限消息的节点，nil表示不做限制
+type SizeLimiter struct {
+	limits       map[pb.XuperMessage_MessageType]int
+	banThreshold int
+	banDuration  time.Duration
+
+	mutex     sync.Mutex
+	offenders map[string]*offender
+	lastSweep time.Time
+}
+
+// NewSizeLimiter 根据网络配置创建SizeLimiter，消息类型名不区分大小写
+func NewSizeLimiter(cfg *config.NetConf) (*SizeLimiter, error) {
+	limiter := &SizeLimiter{
+		limits:       make(map[pb.XuperMessage_MessageType]int, len(cfg.MessageSizeLimits)),
+		banThreshold: cfg.OversizeBanThreshold,
+		banDuration:  time.Duration(cfg.OversizeBanTime) * time.Second,
+		offenders:    make(map[string]*offender),
+	}
+	for name, size := range cfg.MessageSizeLimits {
+		typ, ok := pb.XuperMessage_MessageType_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown message type in messageSizeLimits: %s", name)
+		}
+		if size <= 0 {
+			return nil, fmt.Errorf("bad message size limit for %s: %d", name, size)
+		}
+		limiter.limits[pb.XuperMessage_MessageType(typ)] = int(size) << 10
+	}
+	return limiter, nil
+}
+
+// Check 检查peer发来的消息，被拒绝的节点返回ErrPeerBanned，超过类型限制时返回ErrMessageTooLarge
+func (l *SizeLimiter) Check(peer string, msg *pb.XuperMessage) error {
+	if l == nil {
+		return nil
+	}
+	now := time.Now()
+	if l.IsBanned(peer, now) {
+		return ErrPeerBanned
+	}
+	limit, ok := l.limits[msg.GetHeader().GetType()]
+	if !ok || len(msg.GetData().GetMsgInfo()) <= limit {
+		return nil
+	}
+	metrics.NetworkMsgOversizeCounter.WithLabelValues(msg.GetHeader().GetType().String()).Inc()
+	l.RecordOversize(peer, now)
+	return ErrMessageTooLarge
+}
+
+// CheckFrame 在反序列化之前检查序列化的XuperMessage，只解析消息头取得消息类型，
+// msgInfo超过类型限制时返回ErrMessageTooLarge，无法解析的数据留给反序列化报错
+func (l *SizeLimiter) CheckFrame(data []byte) error {
+	if l == nil || len(l.limits) == 0 {
+		return nil
+	}
+	var header pb.XuperMessage_MessageHeader
+	size := 0
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, data); n < 0 {
+				return nil
+			}
+			data = data[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+		switch num {
+		case 1:
+			if err := proto.Unmarshal(value, &header); err != nil {
+				return nil
+			}
+		case 2:
+			size = msgInfoSize(value)
+		}
+	}
+	limit, ok := l.limits[header.GetType()]
+	if !ok || size <= limit {
+		return nil
+	}
+	metrics.NetworkMsgOversizeCounter.WithLabelValues(header.GetType().String()).Inc()
+	return ErrMessageTooLarge
+}
+
+// msgInfoSize 返回序列化的MessageData中msgInfo的长度，无法解析时按整体长度计算
+func msgInfoSize(data []byte) int {
+	size := len(data)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return size
+		}
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return size
+		}
+		if num == 3 && typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(data)
+			return len(value)
+		}
+		data = data[n:]
+	}
+	return 0
+}
+
+// RecordOversize 记录一次超限消息，达到阈值后在banDuration内拒绝该节点
+func (l *SizeLimiter) RecordOversize(peer string, now time.Time) {
+	if l == nil || l.banThreshold <= 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep(now)
+	o, ok := l.offenders[peer]
+	if !ok || o.expired(now, l.banDuration) {
+		o = new(offender)
+		l.offenders[peer] = o
+	}
+	o.lastOffense = now
+	o.count++
+	if o.count >= l.banThreshold {
+		o.count = 0
+		o.bannedUntil = now.Add(l.banDuration)
+	}
+}
+
+// IsBanned 节点当前是否被拒绝
+func (l *SizeLimiter) IsBanned(peer string, now time.Time) bool {
+	if l == nil || l.banThreshold <= 0 {
+		return false
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	o, ok := l.offenders[peer]
+	if !ok {
+		return false
+	}
+	if o.expired(now, l.banDuration) {
+		delete(l.offenders, peer)
+		return false
+	}
+	return now.Before(o.bannedUntil)
+}
+
+// sweep 每个banDuration最多清理一次过期的记录，避免发送过超限消息的节点一直占用内存，调用方持有锁
+func (l *SizeLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.banDuration {
+		return
+	}
+	l.lastSweep = now
+	for peer, o := range l.offenders {
+		if o.expired(now, l.banDuration) {
+			delete(l.offenders, peer)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/kernel/network/config"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func TestSizeLimiter(t *testing.T) {
+	cfg := config.GetDefP2PConf()
+	cfg.MessageSizeLimits = map[string]int64{"posttx": 1}
+	cfg.OversizeBanThreshold = 2
+	cfg.OversizeBanTime = 60
+	limiter, err := NewSizeLimiter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newMsg := func(typ pb.XuperMessage_MessageType, size int) *pb.XuperMessage {
+		return &pb.XuperMessage{
+			Header: &pb.XuperMessage_MessageHeader{Type: typ},
+			Data:   &pb.XuperMessage_MessageData{MsgInfo: make([]byte, size)},
+		}
+	}
+
+	if err := limiter.Check("peer1", newMsg(pb.XuperMessage_POSTTX, 1024)); err != nil {
+		t.Fatalf("expect accepted, got %v", err)
+	}
+	// 没有配置限制的消息类型只受传输层限制
+	if err := limiter.Check("peer1", newMsg(pb.XuperMessage_SENDBLOCK, 4096)); err != nil {
+		t.Fatalf("expect accepted, got %v", err)
+	}
+	if err := limiter.Check("peer1", newMsg(pb.XuperMessage_POSTTX, 1025)); err != ErrMessageTooLarge {
+		t.Fatalf("expect too large, got %v", err)
+	}
+	if limiter.IsBanned("peer1", time.Now()) {
+		t.Fatal("should not be banned before threshold")
+	}
+	limiter.RecordOversize("peer1", time.Now())
+	if err := limiter.Check("peer1", newMsg(pb.XuperMessage_POSTTX, 1)); err != ErrPeerBanned {
+		t.Fatalf("expect banned, got %v", err)
+	}
+	if err := limiter.Check("peer2", newMsg(pb.XuperMessage_POSTTX, 1)); err != nil {
+		t.Fatalf("other peers should not be affected, got %v", err)
+	}
+	if limiter.IsBanned("peer1", time.Now().Add(time.Minute+time.Second)) {
+		t.Fatal("ban should expire")
+	}
+	// 拒绝期结束后清除记录
+	if _, ok := limiter.offenders["peer1"]; ok {
+		t.Fatal("expired ban should be evicted")
+	}
+
+	// 未达到阈值的记录在banDuration后清除，重新计数
+	now := time.Now()
+	limiter.RecordOversize("peer3", now)
+	limiter.RecordOversize("peer4", now.Add(2*time.Minute))
+	if _, ok := limiter.offenders["peer3"]; ok {
+		t.Fatal("stale offender should be swept")
+	}
+	limiter.RecordOversize("peer4", now.Add(4*time.Minute))
+	if limiter.IsBanned("peer4", now.Add(4*time.Minute)) {
+		t.Fatal("offenses older than ban duration should not count")
+	}
+	if len(limiter.offenders) != 1 {
+		t.Fatalf("expect only peer4 tracked, got %d", len(limiter.offenders))
+	}
+
+	// 反序列化前按序列化数据检查
+	frame := func(typ pb.XuperMessage_MessageType, size int) []byte {
+		data, err := proto.Marshal(newMsg(typ, size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	if err := limiter.CheckFrame(frame(pb.XuperMessage_POSTTX, 1024)); err != nil {
+		t.Fatalf("expect accepted frame, got %v", err)
+	}
+	if err := limiter.CheckFrame(frame(pb.XuperMessage_POSTTX, 1025)); err != ErrMessageTooLarge {
+		t.Fatalf("expect frame too large, got %v", err)
+	}
+	if err := limiter.CheckFrame(frame(pb.XuperMessage_SENDBLOCK, 4096)); err != nil {
+		t.Fatalf("expect unlimited type accepted, got %v", err)
+	}
+	if err := limiter.CheckFrame([]byte{0xff}); err != nil {
+		t.Fatalf("malformed frame should be left to unmarshal, got %v", err)
+	}
+
+	cfg.MessageSizeLimits = map[string]int64{"unknown": 1}
+	if _, err := NewSizeLimiter(cfg); err == nil {
+		t.Fatal("expect unknown message type error")
+	}
+}
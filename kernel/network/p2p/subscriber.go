@@ -3,21 +3,46 @@ package p2p
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	nctx "github.com/xuperchain/xupercore/kernel/network/context"
 	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/lib/timer"
 	pb "github.com/xuperchain/xupercore/protos"
+
+	prom "github.com/prometheus/client_golang/prometheus"
 )
 
 var (
 	ErrHandlerError    = errors.New("handler error")
+	ErrHandlerPanic    = errors.New("handler panic")
 	ErrResponseNil     = errors.New("handler response is nil")
 	ErrStreamSendError = errors.New("send response error")
 	ErrChannelBlock    = errors.New("channel block")
+	ErrChannelOverflow = errors.New("channel overflow")
+)
+
+// OverflowPolicy 定义channel类订阅者在队列满时的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest 丢弃队列中最旧的消息，保证最新的消息(如最新区块)能够投递
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest 丢弃当前新到达的消息
+	OverflowDropNewest
+	// OverflowBlock 阻塞等待队列空闲，超时后丢弃当前消息
+	OverflowBlock
+)
+
+const (
+	// 阻塞策略下等待channel空闲的超时时间
+	channelBlockTimeout = 3 * time.Second
 )
 
 // Subscriber is the interface for p2p message subscriber
@@ -25,6 +50,8 @@ type Subscriber interface {
 	GetMessageType() pb.XuperMessage_MessageType
 	Match(*pb.XuperMessage) bool
 	HandleMessage(xctx.XContext, *pb.XuperMessage, Stream) error
+	// Dropped 返回因队列溢出而被丢弃的消息数
+	Dropped() uint64
 }
 
 // Stream send p2p response message
@@ -48,6 +75,20 @@ func WithFilterBCName(bcName string) SubscriberOption {
 	}
 }
 
+// WithQueueSize 限制channel类订阅者允许积压的消息数，不超过channel本身的容量
+func WithQueueSize(size int) SubscriberOption {
+	return func(s *subscriber) {
+		s.queueSize = size
+	}
+}
+
+// WithOverflowPolicy 设置channel类订阅者队列满时的处理策略，默认为OverflowDropOldest
+func WithOverflowPolicy(policy OverflowPolicy) SubscriberOption {
+	return func(s *subscriber) {
+		s.overflow = policy
+	}
+}
+
 func NewSubscriber(ctx *nctx.NetCtx, typ pb.XuperMessage_MessageType,
 	v interface{}, opts ...SubscriberOption) Subscriber {
 
@@ -76,6 +117,11 @@ func NewSubscriber(ctx *nctx.NetCtx, typ pb.XuperMessage_MessageType,
 		opt(s)
 	}
 
+	// 积压上限不超过channel容量
+	if s.channel != nil && (s.queueSize <= 0 || s.queueSize > cap(s.channel)) {
+		s.queueSize = cap(s.channel)
+	}
+
 	return s
 }
 
@@ -91,6 +137,11 @@ type subscriber struct {
 
 	channel chan *pb.XuperMessage
 	handler HandleFunc
+
+	// channel积压控制
+	queueSize int
+	overflow  OverflowPolicy
+	dropped   uint64
 }
 
 var _ Subscriber = &subscriber{}
@@ -99,6 +150,10 @@ func (s *subscriber) GetMessageType() pb.XuperMessage_MessageType {
 	return s.typ
 }
 
+func (s *subscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
 func (s *subscriber) Match(msg *pb.XuperMessage) bool {
 	if s.from != "" && s.from != msg.GetHeader().GetFrom() {
 		s.log.Debug("subscriber: subscriber from not match", "log_id", msg.GetHeader().GetLogid(),
@@ -123,7 +178,7 @@ func (s *subscriber) HandleMessage(ctx xctx.XContext, msg *pb.XuperMessage, stre
 	}()
 
 	if s.handler != nil {
-		resp, err := s.handler(ctx, msg)
+		resp, err := s.callHandler(ctx, msg)
 		ctx.GetTimer().Mark("handle")
 		if err != nil {
 			ctx.GetLog().Error("subscriber: call user handler error", "err", err)
@@ -150,18 +205,86 @@ func (s *subscriber) HandleMessage(ctx xctx.XContext, msg *pb.XuperMessage, stre
 	}
 
 	if s.channel != nil {
-		timeout, cancel := context.WithTimeout(ctx, 3*time.Second)
+		err := s.deliver(ctx, msg)
+		ctx.GetTimer().Mark("channel")
+		if err != nil {
+			ctx.GetLog().Warn("subscriber: discard message because channel overflow", "type", msg.GetHeader().GetType(),
+				"policy", s.overflow, "queueSize", s.queueSize, "dropped", s.Dropped(), "err", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// callHandler 调用用户handler，handler发生panic时恢复并记录消息类型和堆栈，
+// 对端收到错误响应，避免单个消息的处理异常导致接收协程乃至节点退出
+func (s *subscriber) callHandler(ctx xctx.XContext, msg *pb.XuperMessage) (resp *pb.XuperMessage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ctx.GetLog().Error("subscriber: user handler panic", "type", msg.GetHeader().GetType(),
+				"bc", msg.GetHeader().GetBcname(), "from", msg.GetHeader().GetFrom(),
+				"panic", r, "stack", string(debug.Stack()))
+			resp, err = nil, fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+		}
+	}()
+
+	return s.handler(ctx, msg)
+}
+
+// deliver 按照溢出策略将消息投递到channel
+func (s *subscriber) deliver(ctx xctx.XContext, msg *pb.XuperMessage) error {
+	switch s.overflow {
+	case OverflowBlock:
+		timeout, cancel := context.WithTimeout(ctx, channelBlockTimeout)
 		defer cancel()
 
 		select {
+		case s.channel <- msg:
+			return nil
 		case <-timeout.Done():
-			ctx.GetLog().Error("subscriber: discard message because channel block", "err", timeout.Err())
+			s.drop(msg)
 			return ErrChannelBlock
-		case s.channel <- msg:
-			ctx.GetTimer().Mark("channel")
-		default:
 		}
+	case OverflowDropNewest:
+		if len(s.channel) < s.queueSize {
+			select {
+			case s.channel <- msg:
+				return nil
+			default:
+			}
+		}
+		s.drop(msg)
+		return ErrChannelOverflow
+	default:
+		// 腾出空间的次数有上限，避免并发投递时长时间自旋
+		for i := 0; i <= s.queueSize; i++ {
+			if len(s.channel) < s.queueSize {
+				select {
+				case s.channel <- msg:
+					return nil
+				default:
+				}
+			}
+
+			select {
+			case old := <-s.channel:
+				s.drop(old)
+			default:
+			}
+		}
+		s.drop(msg)
+		return ErrChannelOverflow
 	}
+}
 
-	return nil
+func (s *subscriber) drop(msg *pb.XuperMessage) {
+	atomic.AddUint64(&s.dropped, 1)
+	if s.ctx.EnvCfg != nil && s.ctx.EnvCfg.MetricSwitch {
+		labels := prom.Labels{
+			metrics.LabelBCName:      msg.GetHeader().GetBcname(),
+			metrics.LabelMessageType: msg.GetHeader().GetType().String(),
+		}
+		metrics.NetworkMsgDroppedCounter.With(labels).Inc()
+	}
 }
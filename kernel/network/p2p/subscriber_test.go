@@ -112,3 +112,100 @@ func TestSubscriber(t *testing.T) {
 		}
 	}
 }
+
+func TestSubscriberOverflow(t *testing.T) {
+	mock.InitLogForTest()
+
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := nctx.NewNetCtx(ecfg)
+
+	newMsg := func(logid string) *pb.XuperMessage {
+		return NewMessage(pb.XuperMessage_SENDBLOCK, &pb.XuperMessage{}, WithLogId(logid))
+	}
+	log, _ := logs.NewLogger("", def.SubModName)
+	rctx := &xctx.BaseCtx{
+		XLog:  log,
+		Timer: timer.NewXTimer(),
+	}
+
+	// 默认丢弃最旧的消息
+	ch := make(chan *pb.XuperMessage, 2)
+	sub := NewSubscriber(ctx, pb.XuperMessage_SENDBLOCK, ch)
+	for _, logid := range []string{"1", "2", "3"} {
+		if err := sub.HandleMessage(rctx, newMsg(logid), &mockStream{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sub.Dropped() != 1 {
+		t.Fatalf("expect 1 dropped, got %d", sub.Dropped())
+	}
+	if msg := <-ch; msg.GetHeader().GetLogid() != "2" {
+		t.Fatalf("expect oldest message dropped, got %s", msg.GetHeader().GetLogid())
+	}
+
+	// 丢弃新到达的消息，且积压上限受WithQueueSize限制
+	ch = make(chan *pb.XuperMessage, 2)
+	sub = NewSubscriber(ctx, pb.XuperMessage_SENDBLOCK, ch,
+		WithQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+	if err := sub.HandleMessage(rctx, newMsg("1"), &mockStream{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.HandleMessage(rctx, newMsg("2"), &mockStream{}); err != ErrChannelOverflow {
+		t.Fatalf("expect overflow error, got %v", err)
+	}
+	if sub.Dropped() != 1 || len(ch) != 1 {
+		t.Fatalf("unexpected state, dropped:%d, len:%d", sub.Dropped(), len(ch))
+	}
+	if msg := <-ch; msg.GetHeader().GetLogid() != "1" {
+		t.Fatalf("expect newest message dropped, got %s", msg.GetHeader().GetLogid())
+	}
+}
+
+type recordStream struct {
+	sent []*pb.XuperMessage
+}
+
+func (s *recordStream) Send(msg *pb.XuperMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestSubscriberHandlerPanic(t *testing.T) {
+	mock.InitLogForTest()
+
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := nctx.NewNetCtx(ecfg)
+
+	var handler HandleFunc = func(ctx xctx.XContext, msg *pb.XuperMessage) (*pb.XuperMessage, error) {
+		panic("handler crashed")
+	}
+	sub := NewSubscriber(ctx, pb.XuperMessage_GET_BLOCK, handler)
+
+	log, _ := logs.NewLogger("", def.SubModName)
+	rctx := &xctx.BaseCtx{
+		XLog:  log,
+		Timer: timer.NewXTimer(),
+	}
+	msg := NewMessage(pb.XuperMessage_GET_BLOCK, &pb.XuperMessage{},
+		WithBCName("xuper"), WithLogId("1234567890"))
+
+	// handler panic后仍然向对端返回错误响应
+	stream := &recordStream{}
+	if err := sub.HandleMessage(rctx, msg, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 1 {
+		t.Fatalf("expect 1 response, got %d", len(stream.sent))
+	}
+	resp := stream.sent[0]
+	if resp.GetHeader().GetErrorType() != pb.XuperMessage_UNKNOW_ERROR ||
+		resp.GetHeader().GetLogid() != "1234567890" {
+		t.Fatalf("unexpected response header: %v", resp.GetHeader())
+	}
+}
@@ -56,7 +56,7 @@ func bufferFileHandler(path string, fmtr log.Format, interval int, backupCount i
 
 	h := log.FuncHandler(func(r *log.Record) error {
 		buf := fmtr.Format(r)
-		_, err = w.Write(buf)
+		_, err := w.Write(buf)
 		return err
 	})
 	return h, nil
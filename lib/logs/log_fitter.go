@@ -76,6 +76,19 @@ type Logger interface {
 	Debug(msg string, ctx ...interface{})
 }
 
+// 可按级别判断是否输出的日志实例，用于避免构造不会输出的日志字段
+type LevelLogger interface {
+	Enabled(lvl Lvl) bool
+}
+
+// 判断日志实例是否输出lvl级别的日志，无法判断级别的日志实例总是返回true
+func Enabled(log Logger, lvl Lvl) bool {
+	if l, ok := log.(LevelLogger); ok {
+		return l.Enabled(lvl)
+	}
+	return log != nil
+}
+
 // Logger Fitter
 // 方便系统对日志输出做自定义扩展
 type LogFitter struct {
@@ -185,6 +198,10 @@ func (t *LogFitter) Debug(msg string, ctx ...interface{}) {
 	t.logger.Debug(msg, t.fmtCommLogger(ctx...)...)
 }
 
+func (t *LogFitter) Enabled(lvl Lvl) bool {
+	return t.isInit() && lvl <= t.minLvl
+}
+
 func (t *LogFitter) getCommField() []interface{} {
 	t.commFieldLck.RLock()
 	defer t.commFieldLck.RUnlock()
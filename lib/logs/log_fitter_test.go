@@ -57,6 +57,41 @@ func TestInfo(t *testing.T) {
 	fmt.Printf("remove dir:%s\n", logDir)
 }
 
+// plainLogger 不支持按级别判断的日志实例
+type plainLogger struct {
+	Logger
+}
+
+func TestEnabled(t *testing.T) {
+	confFile := getConfFile()
+	logDir := getLogDir()
+	InitLog(confFile, logDir)
+	defer os.RemoveAll(logDir)
+
+	log, err := NewLogger("", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.minLvl = LvlInfo
+	if !Enabled(log, LvlInfo) || !Enabled(log, LvlWarn) {
+		t.Fatal("info and above should be enabled")
+	}
+	if Enabled(log, LvlDebug) || Enabled(log, LvlTrace) {
+		t.Fatal("debug and trace should be disabled at info level")
+	}
+	log.minLvl = LvlDebug
+	if !Enabled(log, LvlDebug) {
+		t.Fatal("debug should be enabled at debug level")
+	}
+
+	if !Enabled(&plainLogger{}, LvlDebug) {
+		t.Fatal("logger without level should always be enabled")
+	}
+	if Enabled(nil, LvlError) {
+		t.Fatal("nil logger should be disabled")
+	}
+}
+
 func getConfFile() string {
 	dir := utils.GetCurFileDir()
 	return filepath.Join(dir, "config/conf/log.yaml")
@@ -22,10 +22,21 @@ const (
 
 	LabelModule = "module"
 	LabelHandle = "handle"
+
+	LabelCacheResult = "result"
 )
 
 var DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
 
+// ContractGasBuckets 合约单次调用消耗的cpu gas分布
+var ContractGasBuckets = prom.ExponentialBuckets(1e3, 10, 8)
+
+// ContractMemoryBuckets 合约单次调用的线性内存峰值分布，从64KB到1GB
+var ContractMemoryBuckets = prom.ExponentialBuckets(64*1024, 4, 8)
+
+// PropagationBuckets 区块传播耗时分布，覆盖到分钟级
+var PropagationBuckets = []float64{.05, .1, .25, .5, 1, 2, 3, 5, 10, 30, 60}
+
 // common
 var (
 	// 并发请求量
@@ -85,6 +96,26 @@ var (
 			Buckets:   DefBuckets,
 		},
 		[]string{LabelBCName, LabelContractModuleName, LabelContractName, LabelContractMethod})
+	// 合约单次调用消耗的cpu gas（按计价表折算后的指令开销），仅用于统计，不参与执行
+	ContractInvokeGasHistogram = prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "invoke_cpu_gas",
+			Help:      "Histogram of cpu gas used per contract invoke.",
+			Buckets:   ContractGasBuckets,
+		},
+		[]string{LabelBCName, LabelContractModuleName, LabelContractName, LabelContractMethod})
+	// 合约单次调用的线性内存峰值，仅用于统计，不参与执行
+	ContractInvokeMemoryHistogram = prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "invoke_memory_bytes",
+			Help:      "Histogram of peak linear memory used per contract invoke.",
+			Buckets:   ContractMemoryBuckets,
+		},
+		[]string{LabelBCName, LabelContractModuleName, LabelContractName, LabelContractMethod})
 )
 
 // ledger
@@ -113,6 +144,39 @@ var (
 			Help:      "Total number of ledger switch branch.",
 		},
 		[]string{LabelBCName})
+	LedgerBranchGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemLedger,
+			Name:      "branch_gauge",
+			Help:      "Number of ledger retained branches, 0 when side branch pruning is disabled.",
+		},
+		[]string{LabelBCName})
+	LedgerBlockPropagationHistogram = prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemLedger,
+			Name:      "block_propagation_seconds",
+			Help:      "Histogram of latency (seconds) from block produced to confirmed locally.",
+			Buckets:   PropagationBuckets,
+		},
+		[]string{LabelBCName})
+	LedgerBlockClockSkewCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemLedger,
+			Name:      "block_clock_skew_total",
+			Help:      "Total number of received blocks with timestamp ahead of local clock.",
+		},
+		[]string{LabelBCName})
+	LedgerBlockVerifyCacheCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemLedger,
+			Name:      "block_verify_cache_total",
+			Help:      "Total number of verified block cache lookups by result (hit or miss).",
+		},
+		[]string{LabelBCName, LabelCacheResult})
 )
 
 // state
@@ -180,6 +244,30 @@ var (
 			Buckets:   DefBuckets,
 		},
 		[]string{LabelBCName, LabelMessageType})
+	NetworkMsgDroppedCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemNetwork,
+			Name:      "msg_dropped_total",
+			Help:      "Total number of P2P message dropped by subscriber overflow.",
+		},
+		[]string{LabelBCName, LabelMessageType})
+	NetworkMsgOversizeCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemNetwork,
+			Name:      "msg_oversize_total",
+			Help:      "Total number of P2P message rejected by message size limit.",
+		},
+		[]string{LabelMessageType})
+	NetworkPartitionGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemNetwork,
+			Name:      "partitioned",
+			Help:      "Whether the chain is partitioned from the network, 1 means partitioned.",
+		},
+		[]string{LabelBCName})
 )
 
 func RegisterMetrics() {
@@ -191,10 +279,16 @@ func RegisterMetrics() {
 	// contract
 	prom.MustRegister(ContractInvokeCounter)
 	prom.MustRegister(ContractInvokeHistogram)
+	prom.MustRegister(ContractInvokeGasHistogram)
+	prom.MustRegister(ContractInvokeMemoryHistogram)
 	// ledger
 	prom.MustRegister(LedgerConfirmTxCounter)
 	prom.MustRegister(LedgerSwitchBranchCounter)
 	prom.MustRegister(LedgerHeightGauge)
+	prom.MustRegister(LedgerBranchGauge)
+	prom.MustRegister(LedgerBlockPropagationHistogram)
+	prom.MustRegister(LedgerBlockClockSkewCounter)
+	prom.MustRegister(LedgerBlockVerifyCacheCounter)
 	// state
 	prom.MustRegister(StateUnconfirmedTxGauge)
 	// network
@@ -204,4 +298,7 @@ func RegisterMetrics() {
 	prom.MustRegister(NetworkMsgReceivedCounter)
 	prom.MustRegister(NetworkMsgReceivedBytesCounter)
 	prom.MustRegister(NetworkServerHandlingHistogram)
+	prom.MustRegister(NetworkMsgDroppedCounter)
+	prom.MustRegister(NetworkMsgOversizeCounter)
+	prom.MustRegister(NetworkPartitionGauge)
 }
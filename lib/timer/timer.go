@@ -51,3 +51,23 @@ func (timer *XTimer) Print() string {
 	msg = append(msg, fmt.Sprintf("total:%.2fms", deltaTotal/float64(time.Millisecond)))
 	return strings.Join(msg, ",")
 }
+
+// Stage is a marked point with the time elapsed since the previous point
+type Stage struct {
+	Tag  string
+	Cost time.Duration
+}
+
+// Stages returns a copy of all marked points in order
+func (timer *XTimer) Stages() []Stage {
+	stages := make([]Stage, 0, len(timer.points))
+	for _, point := range timer.points {
+		stages = append(stages, Stage{Tag: point.tag, Cost: time.Duration(point.delta)})
+	}
+	return stages
+}
+
+// Elapsed returns the time elapsed since the timer was created
+func (timer *XTimer) Elapsed() time.Duration {
+	return time.Duration(time.Now().UnixNano() - timer.bornTime)
+}
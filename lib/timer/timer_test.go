@@ -15,3 +15,21 @@ func TestMark(t *testing.T) {
 
 	fmt.Println(tmr.Print())
 }
+
+func TestStages(t *testing.T) {
+	tmr := NewXTimer()
+	tmr.Mark("step_1")
+	time.Sleep(10 * time.Millisecond)
+	tmr.Mark("step_2")
+
+	stages := tmr.Stages()
+	if len(stages) != 2 || stages[0].Tag != "step_1" || stages[1].Tag != "step_2" {
+		t.Fatalf("unexpected stages %v", stages)
+	}
+	if stages[1].Cost < 10*time.Millisecond {
+		t.Fatalf("step_2 cost %s less than sleep", stages[1].Cost)
+	}
+	if tmr.Elapsed() < stages[0].Cost+stages[1].Cost {
+		t.Fatal("elapsed should cover all stages")
+	}
+}
@@ -402,6 +402,7 @@ type WasmCodeDesc struct {
 	Digest               []byte   `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
 	VmCompiler           string   `protobuf:"bytes,4,opt,name=vm_compiler,json=vmCompiler,proto3" json:"vm_compiler,omitempty"`
 	ContractType         string   `protobuf:"bytes,5,opt,name=contract_type,json=contractType,proto3" json:"contract_type,omitempty"`
+	Capabilities         []string `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -467,6 +468,13 @@ func (m *WasmCodeDesc) GetContractType() string {
 	return ""
 }
 
+func (m *WasmCodeDesc) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 type ContractEvent struct {
 	Contract             string   `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -666,57 +674,57 @@ func init() {
 func init() { proto.RegisterFile("protos/contract.proto", fileDescriptor_919de52f3bf773d2) }
 
 var fileDescriptor_919de52f3bf773d2 = []byte{
-	// 817 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xc6, 0xbe, 0x8b, 0x7d, 0x9e, 0x38, 0xa9, 0xb5, 0xb4, 0xe8, 0x08, 0xa0, 0x46, 0x07, 0x42,
-	0x56, 0x25, 0x62, 0x91, 0x4a, 0x14, 0xf1, 0x01, 0x89, 0x3a, 0x06, 0x45, 0x50, 0x52, 0x6d, 0x5a,
-	0x51, 0x10, 0x92, 0xb5, 0xbe, 0x5b, 0x2e, 0xa7, 0x78, 0x6f, 0x8f, 0x7d, 0xb1, 0x6c, 0x7e, 0x0e,
-	0x3f, 0x81, 0xcf, 0xfc, 0x2a, 0x7e, 0x01, 0xda, 0x37, 0xfb, 0xec, 0xf6, 0xdb, 0x3e, 0x33, 0xcf,
-	0xcc, 0x3d, 0x33, 0xb3, 0xb3, 0x07, 0x8f, 0x1a, 0xc1, 0x15, 0x97, 0x93, 0x9c, 0xd7, 0x4a, 0x90,
-	0x5c, 0x5d, 0x58, 0x8c, 0x7a, 0xce, 0x7c, 0xf6, 0xc9, 0x5a, 0x37, 0x54, 0xe4, 0x5c, 0xd0, 0x89,
-	0x27, 0x2e, 0x69, 0x51, 0x52, 0xe1, 0x68, 0xd9, 0x5f, 0x90, 0xfc, 0x40, 0xe4, 0x4b, 0x51, 0xe5,
-	0x14, 0x7d, 0x08, 0x49, 0xde, 0xe8, 0xb9, 0x20, 0x8a, 0xa6, 0x9d, 0xf3, 0xce, 0x38, 0xc2, 0xfd,
-	0xbc, 0xd1, 0x98, 0x28, 0xeb, 0x62, 0x94, 0x39, 0x57, 0xd7, 0xb9, 0x18, 0x65, 0xd6, 0xf5, 0x11,
-	0x0c, 0x8a, 0x4a, 0xde, 0x3b, 0x5f, 0x64, 0x7d, 0x89, 0x31, 0x04, 0xe7, 0xfa, 0x0f, 0x4a, 0x9d,
-	0x33, 0x76, 0x4e, 0x63, 0x30, 0xce, 0xec, 0x06, 0x4e, 0x30, 0x95, 0x5c, 0x8b, 0x9c, 0xfe, 0x54,
-	0xb1, 0x4a, 0xa1, 0x31, 0xc4, 0x6a, 0xd3, 0xb8, 0x8f, 0x9f, 0x5e, 0x3e, 0x74, 0x12, 0xe5, 0x45,
-	0x20, 0xbd, 0xda, 0x34, 0x14, 0x5b, 0x06, 0x7a, 0x08, 0x47, 0x4b, 0x13, 0xe2, 0xc5, 0x38, 0x90,
-	0xfd, 0xdb, 0x85, 0x93, 0xeb, 0x7a, 0xc5, 0xef, 0x29, 0xa6, 0x7f, 0x6a, 0x2a, 0x15, 0x7a, 0x0c,
-	0xc7, 0x8c, 0x17, 0x7a, 0x49, 0xe7, 0x35, 0x61, 0x2e, 0xf1, 0x00, 0x83, 0x33, 0xfd, 0x4c, 0x18,
-	0x45, 0x9f, 0xc2, 0x49, 0x68, 0x9c, 0xa3, 0x74, 0x2d, 0x65, 0x18, 0x8c, 0x96, 0x64, 0xb2, 0x50,
-	0x75, 0xc7, 0x0b, 0x47, 0x89, 0x7c, 0x16, 0x6b, 0xb2, 0x84, 0xa7, 0x10, 0x13, 0x51, 0xca, 0x34,
-	0x3e, 0x8f, 0xc6, 0xc7, 0x97, 0x8f, 0x83, 0xf0, 0x3d, 0x2d, 0x17, 0xdf, 0x89, 0x52, 0xce, 0x6a,
-	0x25, 0x36, 0xd8, 0x92, 0xd1, 0xb7, 0xf0, 0x40, 0xf8, 0xca, 0xe6, 0x56, 0xbf, 0x4c, 0x8f, 0x6c,
-	0xfc, 0xa3, 0xc3, 0xc2, 0x6d, 0x77, 0xf0, 0xa9, 0x68, 0x43, 0x89, 0x3e, 0x80, 0x1e, 0x61, 0x5c,
-	0xd7, 0x2a, 0xed, 0x59, 0x41, 0x1e, 0x9d, 0x3d, 0x83, 0xc1, 0xf6, 0x53, 0x68, 0x04, 0xd1, 0x3d,
-	0xdd, 0xf8, 0xc2, 0xcd, 0xd1, 0xb4, 0x6e, 0x45, 0x96, 0xda, 0x55, 0x3a, 0xc4, 0x0e, 0x7c, 0xd3,
-	0xfd, 0xba, 0x93, 0xfd, 0xd7, 0x85, 0xd3, 0x20, 0x59, 0x36, 0xbc, 0x96, 0x14, 0x3d, 0x81, 0x5e,
-	0x55, 0x37, 0x5a, 0xc9, 0xb4, 0x63, 0xa5, 0xa1, 0x20, 0xed, 0xd5, 0xfa, 0xda, 0xd8, 0x67, 0x6b,
-	0x85, 0x3d, 0x03, 0x7d, 0x01, 0x7d, 0xae, 0x95, 0x25, 0x77, 0x2d, 0xf9, 0xfd, 0x1d, 0xf9, 0xc6,
-	0x3a, 0x0c, 0x3b, 0x70, 0xd0, 0x19, 0x24, 0xc2, 0x7f, 0x26, 0x8d, 0xce, 0xa3, 0xf1, 0x10, 0x6f,
-	0xb1, 0xb9, 0x6e, 0x25, 0x91, 0x73, 0x2d, 0x69, 0xe1, 0x6f, 0x4d, 0xbf, 0x24, 0xf2, 0xb5, 0xa4,
-	0x05, 0xfa, 0xd2, 0x84, 0xd9, 0x86, 0xbe, 0xd5, 0xae, 0xbd, 0x76, 0xe3, 0x2d, 0x0d, 0x7d, 0x05,
-	0x83, 0x90, 0x59, 0xa6, 0x3d, 0x1b, 0x93, 0x86, 0x98, 0xa9, 0x9f, 0x73, 0xa8, 0x18, 0xef, 0xa8,
-	0x68, 0x02, 0xa0, 0xd5, 0x9a, 0x5f, 0xbb, 0x06, 0xf4, 0x6d, 0xe0, 0x83, 0x83, 0x06, 0xe0, 0x16,
-	0x05, 0x5d, 0xc2, 0xb1, 0x41, 0x37, 0xbe, 0x0b, 0x89, 0x8d, 0x18, 0x1d, 0x76, 0x01, 0xb7, 0x49,
-	0xd9, 0x1b, 0x18, 0x1d, 0x6a, 0x30, 0x93, 0x95, 0x8a, 0x28, 0x2d, 0xed, 0xdc, 0x8e, 0xb0, 0x47,
-	0x28, 0x85, 0x3e, 0xa3, 0x52, 0x92, 0x32, 0x5c, 0xd3, 0x00, 0x11, 0x82, 0x78, 0xc1, 0x8b, 0x8d,
-	0xbd, 0x9a, 0x43, 0x6c, 0xcf, 0xd9, 0xdf, 0x1d, 0x18, 0xfe, 0x42, 0x24, 0x9b, 0xf2, 0x82, 0x5e,
-	0x51, 0x99, 0x9b, 0x70, 0xa1, 0x6b, 0x55, 0x6d, 0x17, 0x21, 0x40, 0x33, 0x8b, 0x9c, 0xb3, 0xa6,
-	0x5a, 0x52, 0xe1, 0x33, 0x6f, 0xb1, 0x11, 0x53, 0x54, 0x25, 0x95, 0xca, 0x27, 0xf7, 0xc8, 0x2c,
-	0xc5, 0x8a, 0xcd, 0xb7, 0x61, 0xb1, 0x5b, 0x8a, 0x15, 0x9b, 0x86, 0xc0, 0xf6, 0x6a, 0xd9, 0xb5,
-	0x3e, 0xda, 0x5f, 0x2d, 0xb3, 0xce, 0xd9, 0x2d, 0x9c, 0x84, 0xf2, 0x67, 0x2b, 0x5a, 0x2b, 0x27,
-	0xc5, 0x19, 0xbc, 0xca, 0x2d, 0x36, 0x55, 0xb6, 0x76, 0xd4, 0x9e, 0xdf, 0x59, 0xf9, 0xef, 0xbb,
-	0x9e, 0xde, 0x2a, 0xa2, 0xae, 0x88, 0x22, 0x28, 0x83, 0x21, 0xc9, 0x73, 0xb3, 0x20, 0x53, 0xbb,
-	0x33, 0xee, 0x81, 0xdb, 0xb3, 0xa1, 0xcf, 0x76, 0x8a, 0x1d, 0xc9, 0xbd, 0x2e, 0xfb, 0xc6, 0xec,
-	0x9f, 0x0e, 0x9c, 0xb6, 0xd3, 0x6b, 0xf9, 0xf6, 0x2b, 0xd2, 0x79, 0xc7, 0x2b, 0x82, 0x20, 0x56,
-	0xeb, 0xaa, 0x08, 0xea, 0xcd, 0xd9, 0xd8, 0x0a, 0x2a, 0xf3, 0xa0, 0xde, 0x9c, 0xcd, 0x9b, 0x59,
-	0xc9, 0xf9, 0x82, 0xd4, 0xb5, 0xbf, 0xfd, 0x09, 0x4e, 0x2a, 0xf9, 0xdc, 0x62, 0xf4, 0x31, 0x0c,
-	0xcc, 0xc4, 0xa4, 0x22, 0xac, 0xb1, 0x0d, 0x8d, 0xf0, 0xce, 0xd0, 0x9e, 0x70, 0x6f, 0x6f, 0xc2,
-	0x4f, 0x9e, 0xc1, 0xb0, 0xfd, 0x8c, 0xa2, 0x3e, 0x44, 0xd3, 0x97, 0xaf, 0x47, 0xef, 0x21, 0x80,
-	0xde, 0x8b, 0xd9, 0x8b, 0x1b, 0xfc, 0xeb, 0xa8, 0x83, 0x12, 0x88, 0xaf, 0xae, 0x6f, 0x7f, 0x1c,
-	0x75, 0xcd, 0xe9, 0xcd, 0xf7, 0xb3, 0xd9, 0x28, 0x7a, 0x3e, 0xfe, 0xed, 0xf3, 0xb2, 0x52, 0x77,
-	0x7a, 0x71, 0x91, 0x73, 0x36, 0x71, 0x3f, 0x93, 0x3b, 0x52, 0xd5, 0x93, 0xc3, 0xff, 0xca, 0xc2,
-	0xfd, 0x71, 0x9e, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x4e, 0xe4, 0xd3, 0xa2, 0x91, 0x06, 0x00,
-	0x00,
+	// 829 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x55, 0x5d, 0x6f, 0x1b, 0x45,
+	0x14, 0xc5, 0x5e, 0xc7, 0x1f, 0x37, 0x4e, 0x6a, 0x0d, 0x2d, 0x5a, 0x02, 0xa8, 0xd1, 0x82, 0x90,
+	0x55, 0x89, 0x58, 0xa4, 0x12, 0x45, 0x3c, 0x20, 0x51, 0xc7, 0xa0, 0x08, 0x4a, 0xaa, 0x49, 0x2b,
+	0x0a, 0x42, 0xb2, 0xc6, 0xbb, 0xc3, 0x66, 0x14, 0xcf, 0xce, 0x32, 0x1f, 0x96, 0xcd, 0xcf, 0xe2,
+	0x99, 0x67, 0x7e, 0x10, 0xbf, 0x00, 0xcd, 0x97, 0xbd, 0x76, 0xfb, 0x62, 0xcd, 0xb9, 0xf7, 0xdc,
+	0xf1, 0xb9, 0x67, 0xe6, 0xce, 0xc2, 0xa3, 0x5a, 0x0a, 0x2d, 0xd4, 0x24, 0x17, 0x95, 0x96, 0x24,
+	0xd7, 0x17, 0x0e, 0xa3, 0xae, 0x0f, 0x9f, 0x7d, 0xb2, 0x36, 0x35, 0x95, 0xb9, 0x90, 0x74, 0x12,
+	0x88, 0x4b, 0x5a, 0x94, 0x54, 0x7a, 0x5a, 0xf6, 0x17, 0xf4, 0x7f, 0x20, 0xea, 0xa5, 0x64, 0x39,
+	0x45, 0x1f, 0x42, 0x3f, 0xaf, 0xcd, 0x5c, 0x12, 0x4d, 0xd3, 0xd6, 0x79, 0x6b, 0x9c, 0xe0, 0x5e,
+	0x5e, 0x1b, 0x4c, 0xb4, 0x4b, 0x71, 0xca, 0x7d, 0xaa, 0xed, 0x53, 0x9c, 0x72, 0x97, 0xfa, 0x08,
+	0x06, 0x05, 0x53, 0xf7, 0x3e, 0x97, 0xb8, 0x5c, 0xdf, 0x06, 0x62, 0x72, 0xfd, 0x07, 0xa5, 0x3e,
+	0xd9, 0xf1, 0x49, 0x1b, 0xb0, 0xc9, 0xec, 0x06, 0x4e, 0x30, 0x55, 0xc2, 0xc8, 0x9c, 0xfe, 0xc4,
+	0x38, 0xd3, 0x68, 0x0c, 0x1d, 0xbd, 0xa9, 0xfd, 0x9f, 0x9f, 0x5e, 0x3e, 0xf4, 0x12, 0xd5, 0x45,
+	0x24, 0xbd, 0xda, 0xd4, 0x14, 0x3b, 0x06, 0x7a, 0x08, 0x47, 0x4b, 0x5b, 0x12, 0xc4, 0x78, 0x90,
+	0xfd, 0xd3, 0x86, 0x93, 0xeb, 0x6a, 0x25, 0xee, 0x29, 0xa6, 0x7f, 0x1a, 0xaa, 0x34, 0x7a, 0x0c,
+	0xc7, 0x5c, 0x14, 0x66, 0x49, 0xe7, 0x15, 0xe1, 0x7e, 0xe3, 0x01, 0x06, 0x1f, 0xfa, 0x99, 0x70,
+	0x8a, 0x3e, 0x85, 0x93, 0x68, 0x9c, 0xa7, 0xb4, 0x1d, 0x65, 0x18, 0x83, 0x8e, 0x64, 0x77, 0xa1,
+	0xfa, 0x4e, 0x14, 0x9e, 0x92, 0x84, 0x5d, 0x5c, 0xc8, 0x11, 0x9e, 0x42, 0x87, 0xc8, 0x52, 0xa5,
+	0x9d, 0xf3, 0x64, 0x7c, 0x7c, 0xf9, 0x38, 0x0a, 0xdf, 0xd3, 0x72, 0xf1, 0x9d, 0x2c, 0xd5, 0xac,
+	0xd2, 0x72, 0x83, 0x1d, 0x19, 0x7d, 0x0b, 0x0f, 0x64, 0xe8, 0x6c, 0xee, 0xf4, 0xab, 0xf4, 0xc8,
+	0xd5, 0x3f, 0x3a, 0x6c, 0xdc, 0xb9, 0x83, 0x4f, 0x65, 0x13, 0x2a, 0xf4, 0x01, 0x74, 0x09, 0x17,
+	0xa6, 0xd2, 0x69, 0xd7, 0x09, 0x0a, 0xe8, 0xec, 0x19, 0x0c, 0xb6, 0x7f, 0x85, 0x46, 0x90, 0xdc,
+	0xd3, 0x4d, 0x68, 0xdc, 0x2e, 0xad, 0x75, 0x2b, 0xb2, 0x34, 0xbe, 0xd3, 0x21, 0xf6, 0xe0, 0x9b,
+	0xf6, 0xd7, 0xad, 0xec, 0xbf, 0x36, 0x9c, 0x46, 0xc9, 0xaa, 0x16, 0x95, 0xa2, 0xe8, 0x09, 0x74,
+	0x59, 0x55, 0x1b, 0xad, 0xd2, 0x96, 0x93, 0x86, 0xa2, 0xb4, 0x57, 0xeb, 0x6b, 0x1b, 0x9f, 0xad,
+	0x35, 0x0e, 0x0c, 0xf4, 0x05, 0xf4, 0x84, 0xd1, 0x8e, 0xdc, 0x76, 0xe4, 0xf7, 0x77, 0xe4, 0x1b,
+	0xa3, 0x03, 0x3b, 0x72, 0xd0, 0x19, 0xf4, 0x65, 0xf8, 0x9b, 0x34, 0x39, 0x4f, 0xc6, 0x43, 0xbc,
+	0xc5, 0xf6, 0xba, 0x95, 0x44, 0xcd, 0x8d, 0xa2, 0x45, 0xb8, 0x35, 0xbd, 0x92, 0xa8, 0xd7, 0x8a,
+	0x16, 0xe8, 0x4b, 0x5b, 0xe6, 0x0c, 0x7d, 0xcb, 0xae, 0x3d, 0xbb, 0xf1, 0x96, 0x86, 0xbe, 0x82,
+	0x41, 0xdc, 0x59, 0xa5, 0x5d, 0x57, 0x93, 0xc6, 0x9a, 0x69, 0x38, 0xe7, 0xd8, 0x31, 0xde, 0x51,
+	0xd1, 0x04, 0xc0, 0xe8, 0xb5, 0xb8, 0xf6, 0x06, 0xf4, 0x5c, 0xe1, 0x83, 0x03, 0x03, 0x70, 0x83,
+	0x82, 0x2e, 0xe1, 0xd8, 0xa2, 0x9b, 0xe0, 0x42, 0xdf, 0x55, 0x8c, 0x0e, 0x5d, 0xc0, 0x4d, 0x52,
+	0xf6, 0x06, 0x46, 0x87, 0x1a, 0xec, 0xc9, 0x2a, 0x4d, 0xb4, 0x51, 0xee, 0xdc, 0x8e, 0x70, 0x40,
+	0x28, 0x85, 0x1e, 0xa7, 0x4a, 0x91, 0x32, 0x5e, 0xd3, 0x08, 0x11, 0x82, 0xce, 0x42, 0x14, 0x1b,
+	0x77, 0x35, 0x87, 0xd8, 0xad, 0xb3, 0x7f, 0x5b, 0x30, 0xfc, 0x85, 0x28, 0x3e, 0x15, 0x05, 0xbd,
+	0xa2, 0x2a, 0xb7, 0xe5, 0xd2, 0x54, 0x9a, 0x6d, 0x07, 0x21, 0x42, 0x7b, 0x16, 0xb9, 0xe0, 0x35,
+	0x5b, 0x52, 0x19, 0x76, 0xde, 0x62, 0x2b, 0xa6, 0x60, 0x25, 0x55, 0x3a, 0x6c, 0x1e, 0x90, 0x1d,
+	0x8a, 0x15, 0x9f, 0x6f, 0xcb, 0x3a, 0x7e, 0x28, 0x56, 0x7c, 0x1a, 0x0b, 0x9b, 0xa3, 0xe5, 0xc6,
+	0xfa, 0x68, 0x7f, 0xb4, 0xec, 0x38, 0xa3, 0x0c, 0x86, 0x39, 0xa9, 0xc9, 0x82, 0x2d, 0x99, 0x66,
+	0xe1, 0x78, 0x06, 0x78, 0x2f, 0x96, 0xdd, 0xc2, 0x49, 0xb4, 0x68, 0xb6, 0xa2, 0x95, 0xf6, 0x72,
+	0x7d, 0x20, 0x74, 0xb2, 0xc5, 0xd6, 0x89, 0xc6, 0x1c, 0xbb, 0xf5, 0x3b, 0xdd, 0xf9, 0x7d, 0xe7,
+	0xfb, 0xad, 0x26, 0xfa, 0x8a, 0x68, 0x62, 0xc5, 0x90, 0x3c, 0xb7, 0x43, 0x34, 0xb5, 0x3f, 0xe1,
+	0x11, 0xdc, 0x8b, 0xa1, 0xcf, 0x76, 0x5d, 0x79, 0x92, 0x7f, 0x81, 0xf6, 0x83, 0xd9, 0xdf, 0x2d,
+	0x38, 0x6d, 0x6e, 0x6f, 0xd4, 0xdb, 0x2f, 0x4d, 0xeb, 0x1d, 0x2f, 0x0d, 0x82, 0x8e, 0x5e, 0xb3,
+	0x22, 0xaa, 0xb7, 0x6b, 0x1b, 0x2b, 0xa8, 0xca, 0xa3, 0x7a, 0xbb, 0xb6, 0xef, 0x2a, 0x53, 0xf3,
+	0x05, 0xa9, 0xaa, 0x30, 0x21, 0x7d, 0xdc, 0x67, 0xea, 0xb9, 0xc3, 0xe8, 0x63, 0x18, 0xd8, 0x53,
+	0x55, 0x9a, 0xf0, 0xda, 0x99, 0x9e, 0xe0, 0x5d, 0xa0, 0x79, 0x0b, 0xba, 0x7b, 0xb7, 0xe0, 0xc9,
+	0x33, 0x18, 0x36, 0x9f, 0x5a, 0xd4, 0x83, 0x64, 0xfa, 0xf2, 0xf5, 0xe8, 0x3d, 0x04, 0xd0, 0x7d,
+	0x31, 0x7b, 0x71, 0x83, 0x7f, 0x1d, 0xb5, 0x50, 0x1f, 0x3a, 0x57, 0xd7, 0xb7, 0x3f, 0x8e, 0xda,
+	0x76, 0xf5, 0xe6, 0xfb, 0xd9, 0x6c, 0x94, 0x3c, 0x1f, 0xff, 0xf6, 0x79, 0xc9, 0xf4, 0x9d, 0x59,
+	0x5c, 0xe4, 0x82, 0x4f, 0xfc, 0x07, 0xe7, 0x8e, 0xb0, 0x6a, 0x72, 0xf8, 0xed, 0x59, 0xf8, 0xaf,
+	0xd2, 0xd3, 0xff, 0x07, 0x00, 0xc3, 0xde, 0x76, 0xff, 0xb5, 0x06, 0x00, 0x00,
 }
@@ -71,6 +71,9 @@ const (
 	XuperMessage_GET_BLOCKS_HEADERS_RES XuperMessage_MessageType = 27
 	XuperMessage_GET_BLOCK_TXS          XuperMessage_MessageType = 28
 	XuperMessage_GET_BLOCKS_TXS_RES     XuperMessage_MessageType = 29
+	// 获取节点最新的签名检查点
+	XuperMessage_GET_CHECKPOINT     XuperMessage_MessageType = 30
+	XuperMessage_GET_CHECKPOINT_RES XuperMessage_MessageType = 31
 )
 
 var XuperMessage_MessageType_name = map[int32]string{
@@ -104,6 +107,8 @@ var XuperMessage_MessageType_name = map[int32]string{
 	27: "GET_BLOCKS_HEADERS_RES",
 	28: "GET_BLOCK_TXS",
 	29: "GET_BLOCKS_TXS_RES",
+	30: "GET_CHECKPOINT",
+	31: "GET_CHECKPOINT_RES",
 }
 
 var XuperMessage_MessageType_value = map[string]int32{
@@ -137,6 +142,8 @@ var XuperMessage_MessageType_value = map[string]int32{
 	"GET_BLOCKS_HEADERS_RES":       27,
 	"GET_BLOCK_TXS":                28,
 	"GET_BLOCKS_TXS_RES":           29,
+	"GET_CHECKPOINT":               30,
+	"GET_CHECKPOINT_RES":           31,
 }
 
 func (x XuperMessage_MessageType) String() string {
@@ -395,6 +402,7 @@ type PeerInfo struct {
 	Address              string      `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	Account              string      `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
 	Peer                 []*PeerInfo `protobuf:"bytes,4,rep,name=peer,proto3" json:"peer,omitempty"`
+	Capabilities         []string    `protobuf:"bytes,5,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -453,6 +461,13 @@ func (m *PeerInfo) GetPeer() []*PeerInfo {
 	return nil
 }
 
+func (m *PeerInfo) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("protos.XuperMessage_MessageType", XuperMessage_MessageType_name, XuperMessage_MessageType_value)
 	proto.RegisterEnum("protos.XuperMessage_ErrorType", XuperMessage_ErrorType_name, XuperMessage_ErrorType_value)
@@ -465,62 +480,64 @@ func init() {
 func init() { proto.RegisterFile("protos/network.proto", fileDescriptor_9898f5d59e04eeea) }
 
 var fileDescriptor_9898f5d59e04eeea = []byte{
-	// 865 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xcd, 0x73, 0x1a, 0x37,
-	0x14, 0x37, 0x18, 0xf3, 0xf1, 0xf8, 0xb0, 0xfc, 0x4c, 0x9c, 0x2d, 0x71, 0x53, 0x86, 0xe9, 0xa4,
-	0x9c, 0xec, 0x0e, 0xed, 0xa9, 0xd3, 0x0b, 0x2c, 0xb2, 0xd9, 0x71, 0xd0, 0xee, 0x48, 0x22, 0x26,
-	0xbd, 0xec, 0xac, 0x41, 0xb1, 0x99, 0x04, 0x96, 0x59, 0x70, 0xda, 0xfc, 0x29, 0x9d, 0xe9, 0xb9,
-	0x97, 0xfe, 0x93, 0x1d, 0x69, 0x77, 0x31, 0xb6, 0x49, 0x4e, 0xf0, 0x7e, 0x1f, 0xd2, 0x7b, 0x4f,
-	0xd2, 0x5b, 0xa8, 0x2f, 0xa3, 0x70, 0x1d, 0xae, 0xce, 0x17, 0x6a, 0xfd, 0x67, 0x18, 0x7d, 0x3c,
-	0x33, 0x21, 0xe6, 0x63, 0xb4, 0xf5, 0x4f, 0x19, 0x2a, 0xe3, 0xfb, 0xa5, 0x8a, 0x86, 0x6a, 0xb5,
-	0x0a, 0x6e, 0x15, 0xfe, 0x06, 0xf9, 0x81, 0x0a, 0xa6, 0x2a, 0xb2, 0x32, 0xcd, 0x4c, 0xbb, 0xdc,
-	0x69, 0xc5, 0x86, 0xd5, 0xd9, 0xb6, 0xea, 0x2c, 0xf9, 0x8d, 0x95, 0x3c, 0x71, 0xe0, 0xaf, 0x90,
-	0xeb, 0x07, 0xeb, 0xc0, 0xca, 0x1a, 0x67, 0xf3, 0x5b, 0x4e, 0xad, 0xe3, 0x46, 0xdd, 0xf8, 0x2f,
-	0x0b, 0xd5, 0x47, 0xeb, 0xa1, 0x05, 0x85, 0xcf, 0x2a, 0x5a, 0xcd, 0xc2, 0x85, 0x49, 0xa2, 0xc4,
-	0xd3, 0x10, 0xeb, 0x70, 0xf0, 0x29, 0xbc, 0x9d, 0x4d, 0xcd, 0x16, 0x25, 0x1e, 0x07, 0x88, 0x90,
-	0xfb, 0x10, 0x85, 0x73, 0x6b, 0xdf, 0x80, 0xe6, 0x3f, 0x9e, 0x40, 0xfe, 0x66, 0xb2, 0x08, 0xe6,
-	0xca, 0xca, 0x19, 0x34, 0x89, 0x74, 0x8e, 0xeb, 0x2f, 0x4b, 0x65, 0x1d, 0x34, 0x33, 0xed, 0xda,
-	0xb7, 0x73, 0x94, 0x5f, 0x96, 0x8a, 0x1b, 0x35, 0xb6, 0xa0, 0x32, 0x0d, 0xd6, 0x81, 0x7d, 0xa7,
-	0x26, 0x1f, 0xc5, 0xfd, 0xdc, 0xca, 0x37, 0x33, 0xed, 0x2a, 0x7f, 0x84, 0xe1, 0xef, 0x50, 0x52,
-	0x51, 0x14, 0x46, 0xda, 0x66, 0x15, 0xcc, 0xf2, 0xaf, 0x77, 0x2e, 0x4f, 0x53, 0x15, 0x7f, 0x30,
-	0xe0, 0x1b, 0xa8, 0xa9, 0x45, 0x70, 0xf3, 0x49, 0xd9, 0xe1, 0x7c, 0x19, 0xa9, 0xd5, 0xca, 0x2a,
-	0x36, 0x33, 0xed, 0x22, 0x7f, 0x82, 0x36, 0x7e, 0x82, 0xf2, 0x56, 0x0b, 0x75, 0xab, 0xe6, 0xab,
-	0x5b, 0x67, 0xf1, 0x21, 0x34, 0xd5, 0x57, 0x78, 0x1a, 0xb6, 0xfe, 0x3e, 0xd8, 0x28, 0xcd, 0x06,
-	0x55, 0x28, 0x09, 0xca, 0xfa, 0xbd, 0xb7, 0xae, 0x7d, 0x45, 0xf6, 0x10, 0x20, 0xef, 0xb9, 0x42,
-	0xca, 0x31, 0xc9, 0xe0, 0x21, 0x94, 0x7b, 0x5d, 0x69, 0x0f, 0x12, 0x20, 0xab, 0xb5, 0x97, 0x54,
-	0xfa, 0xb1, 0x76, 0x1f, 0x8b, 0x90, 0xf3, 0x1c, 0x76, 0x49, 0x72, 0x68, 0x41, 0x7d, 0x43, 0xd8,
-	0x83, 0xae, 0xc3, 0x84, 0xec, 0xca, 0x91, 0x20, 0x07, 0x78, 0x04, 0xd5, 0x0d, 0xe3, 0x73, 0x2a,
-	0x48, 0x1e, 0x4f, 0xc1, 0xda, 0x25, 0x36, 0x6c, 0x41, 0xb3, 0xb6, 0xcb, 0x2e, 0x1c, 0x3e, 0x7c,
-	0xbe, 0x5c, 0x11, 0x9b, 0x70, 0xfa, 0x35, 0xd6, 0xf8, 0x4b, 0x7a, 0xc3, 0xa1, 0xb8, 0xf4, 0xe5,
-	0x7b, 0x8f, 0xfa, 0xcc, 0x65, 0x94, 0x00, 0x12, 0xa8, 0xe8, 0x0d, 0xb9, 0x67, 0xfb, 0x9e, 0xcb,
-	0x25, 0x29, 0x63, 0x1d, 0xc8, 0x36, 0x62, 0xac, 0x15, 0x3c, 0x01, 0xd4, 0x68, 0x77, 0x24, 0x07,
-	0x94, 0x49, 0xc7, 0xee, 0x4a, 0xc7, 0x65, 0xa4, 0x8a, 0x0d, 0x38, 0x79, 0x8e, 0x1b, 0x4f, 0xcd,
-	0xa4, 0xab, 0x73, 0xa0, 0x7d, 0xbf, 0x77, 0x21, 0x7d, 0x46, 0xaf, 0xfd, 0x77, 0x0e, 0xbd, 0xf6,
-	0x87, 0xe2, 0x92, 0x1c, 0x9a, 0x74, 0x9f, 0xb0, 0x1e, 0x77, 0x3d, 0x57, 0x74, 0xdf, 0x1a, 0x05,
-	0xd1, 0x9d, 0xdb, 0x56, 0xbc, 0x73, 0x25, 0x35, 0xcc, 0x91, 0xee, 0xbe, 0xd6, 0x9b, 0x32, 0x9d,
-	0x3e, 0x41, 0xac, 0x40, 0x51, 0x03, 0xcc, 0xed, 0x53, 0x72, 0x9c, 0x16, 0x95, 0xd0, 0x82, 0xd4,
-	0xd3, 0xa2, 0x52, 0xc4, 0x24, 0xf8, 0x02, 0x6b, 0x00, 0x1b, 0x54, 0x90, 0x13, 0x44, 0xa8, 0x3d,
-	0xc4, 0x46, 0xf3, 0x32, 0x3d, 0x24, 0x8f, 0x52, 0xee, 0x3b, 0xec, 0xc2, 0x25, 0x16, 0xbe, 0x80,
-	0xa3, 0x47, 0x90, 0x51, 0x7e, 0x97, 0xc2, 0xf1, 0x71, 0x0e, 0x68, 0xb7, 0x4f, 0xb9, 0x20, 0x8d,
-	0xb4, 0x43, 0xc9, 0xa2, 0x09, 0x6e, 0x2c, 0xaf, 0x1e, 0xdf, 0x00, 0x39, 0x16, 0xe4, 0x34, 0x6d,
-	0x74, 0x22, 0x97, 0xe3, 0x58, 0xfa, 0x7d, 0xeb, 0xdf, 0x2c, 0x94, 0x36, 0xaf, 0x00, 0xcb, 0x50,
-	0x10, 0x23, 0xdb, 0xa6, 0x42, 0x90, 0x3d, 0x7d, 0xd7, 0xcc, 0x69, 0x66, 0x74, 0xe1, 0x23, 0x76,
-	0xc5, 0xdc, 0x6b, 0x9f, 0x72, 0xee, 0x72, 0x92, 0xc5, 0x63, 0x38, 0xb4, 0x07, 0xd4, 0xbe, 0xf2,
-	0xc5, 0x68, 0x98, 0x80, 0xfb, 0xfa, 0x60, 0x46, 0x6c, 0xd8, 0xe5, 0x62, 0x10, 0xf7, 0xda, 0xef,
-	0xb9, 0xfd, 0xf7, 0x09, 0x9b, 0xd3, 0x5d, 0xb0, 0x5d, 0xc6, 0xa8, 0xad, 0xcf, 0xfe, 0x62, 0x24,
-	0x28, 0x39, 0x78, 0x7e, 0x89, 0x13, 0x75, 0x1e, 0x5f, 0xc2, 0xf1, 0x16, 0xca, 0x5c, 0x49, 0xc7,
-	0x8e, 0x90, 0xa4, 0xa0, 0x77, 0x7e, 0xa8, 0x2d, 0x56, 0x17, 0xb1, 0x05, 0xaf, 0xbf, 0x7a, 0x47,
-	0x63, 0x4d, 0x29, 0x7d, 0x03, 0x4f, 0xae, 0x54, 0xcc, 0x02, 0xfe, 0x00, 0xaf, 0x76, 0xb0, 0xcc,
-	0x95, 0xbe, 0xd7, 0x15, 0x82, 0x94, 0x5b, 0x6b, 0x28, 0x7a, 0x4a, 0x45, 0xfa, 0x41, 0x63, 0x0d,
-	0xb2, 0xb3, 0x69, 0x32, 0x10, 0xb3, 0xb3, 0xa9, 0x7e, 0xfa, 0xc1, 0x74, 0x6a, 0x46, 0x45, 0x3c,
-	0x0d, 0xd3, 0xd0, 0x30, 0x93, 0x49, 0x78, 0xbf, 0x58, 0x27, 0x23, 0x31, 0x0d, 0xf1, 0x47, 0xc8,
-	0x2d, 0x95, 0x8a, 0xac, 0x5c, 0x73, 0xbf, 0x5d, 0xee, 0x90, 0x74, 0x3c, 0xa5, 0x7b, 0x70, 0xc3,
-	0x76, 0x3c, 0x80, 0x65, 0x67, 0x29, 0x54, 0xf4, 0x79, 0x36, 0x51, 0xd8, 0x83, 0x9a, 0x50, 0x8b,
-	0xa9, 0xd7, 0x59, 0xa6, 0xdf, 0x88, 0xfa, 0xae, 0xb1, 0xd6, 0xd8, 0x89, 0xb6, 0xf6, 0xda, 0x99,
-	0x9f, 0x33, 0xbd, 0xf6, 0x1f, 0x6f, 0x6e, 0x67, 0xeb, 0xbb, 0xfb, 0x9b, 0xb3, 0x49, 0x38, 0x3f,
-	0xff, 0x4b, 0x0b, 0x26, 0x77, 0xc1, 0x6c, 0x91, 0xfc, 0x0d, 0x23, 0x75, 0x1e, 0x9b, 0x6f, 0xe2,
-	0x0f, 0xd3, 0x2f, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0xb2, 0xf2, 0x31, 0x64, 0xb7, 0x06, 0x00,
-	0x00,
+	// 899 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xdd, 0x72, 0xe2, 0x36,
+	0x14, 0x8e, 0xf9, 0xe7, 0xf0, 0x13, 0x45, 0x61, 0xb3, 0x2e, 0x9b, 0x66, 0x19, 0xa6, 0xb3, 0xe5,
+	0x2a, 0xe9, 0xd0, 0x5e, 0x75, 0x7a, 0x03, 0x46, 0x09, 0x9e, 0x2c, 0xb6, 0x47, 0x12, 0x1b, 0xb6,
+	0x37, 0x1e, 0x03, 0xda, 0xc4, 0xb3, 0x01, 0x7b, 0x0c, 0xd9, 0x76, 0xdf, 0xa4, 0x4f, 0xd0, 0x9b,
+	0x3e, 0x47, 0x67, 0xfa, 0x58, 0x1d, 0xc9, 0x36, 0x81, 0x84, 0xcd, 0x15, 0x9c, 0xef, 0x47, 0xe7,
+	0xe8, 0x48, 0x3a, 0x86, 0x46, 0x18, 0x05, 0xeb, 0x60, 0x75, 0xb1, 0x14, 0xeb, 0x3f, 0x82, 0xe8,
+	0xf3, 0xb9, 0x0a, 0x71, 0x21, 0x46, 0xdb, 0xff, 0x55, 0xa0, 0x3a, 0x79, 0x08, 0x45, 0x34, 0x12,
+	0xab, 0x95, 0x77, 0x2b, 0xf0, 0xaf, 0x50, 0x18, 0x0a, 0x6f, 0x2e, 0x22, 0x5d, 0x6b, 0x69, 0x9d,
+	0x4a, 0xb7, 0x1d, 0x1b, 0x56, 0xe7, 0xdb, 0xaa, 0xf3, 0xe4, 0x37, 0x56, 0xd2, 0xc4, 0x81, 0x7f,
+	0x81, 0xdc, 0xc0, 0x5b, 0x7b, 0x7a, 0x46, 0x39, 0x5b, 0x2f, 0x39, 0xa5, 0x8e, 0x2a, 0x75, 0xf3,
+	0x9f, 0x0c, 0xd4, 0x76, 0xd6, 0xc3, 0x3a, 0x14, 0xbf, 0x88, 0x68, 0xe5, 0x07, 0x4b, 0x55, 0x44,
+	0x99, 0xa6, 0x21, 0x6e, 0x40, 0xfe, 0x3e, 0xb8, 0xf5, 0xe7, 0x2a, 0x45, 0x99, 0xc6, 0x01, 0xc6,
+	0x90, 0xfb, 0x14, 0x05, 0x0b, 0x3d, 0xab, 0x40, 0xf5, 0x1f, 0x9f, 0x40, 0x61, 0x3a, 0x5b, 0x7a,
+	0x0b, 0xa1, 0xe7, 0x14, 0x9a, 0x44, 0xb2, 0xc6, 0xf5, 0xd7, 0x50, 0xe8, 0xf9, 0x96, 0xd6, 0xa9,
+	0xbf, 0x5c, 0x23, 0xff, 0x1a, 0x0a, 0xaa, 0xd4, 0xb8, 0x0d, 0xd5, 0xb9, 0xb7, 0xf6, 0x8c, 0x3b,
+	0x31, 0xfb, 0xcc, 0x1e, 0x16, 0x7a, 0xa1, 0xa5, 0x75, 0x6a, 0x74, 0x07, 0xc3, 0xbf, 0x41, 0x59,
+	0x44, 0x51, 0x10, 0x49, 0x9b, 0x5e, 0x54, 0xcb, 0x9f, 0xed, 0x5d, 0x9e, 0xa4, 0x2a, 0xfa, 0x68,
+	0xc0, 0xef, 0xa0, 0x2e, 0x96, 0xde, 0xf4, 0x5e, 0x18, 0xc1, 0x22, 0x8c, 0xc4, 0x6a, 0xa5, 0x97,
+	0x5a, 0x5a, 0xa7, 0x44, 0x9f, 0xa0, 0xcd, 0x1f, 0xa1, 0xb2, 0xd5, 0x42, 0xd9, 0xaa, 0xc5, 0xea,
+	0xd6, 0x5c, 0x7e, 0x0a, 0xd4, 0xee, 0xab, 0x34, 0x0d, 0xdb, 0xff, 0xe6, 0x37, 0x4a, 0x95, 0xa0,
+	0x06, 0x65, 0x46, 0xac, 0x41, 0xff, 0xbd, 0x6d, 0x5c, 0xa3, 0x03, 0x0c, 0x50, 0x70, 0x6c, 0xc6,
+	0xf9, 0x04, 0x69, 0xf8, 0x10, 0x2a, 0xfd, 0x1e, 0x37, 0x86, 0x09, 0x90, 0x91, 0xda, 0x2b, 0xc2,
+	0xdd, 0x58, 0x9b, 0xc5, 0x25, 0xc8, 0x39, 0xa6, 0x75, 0x85, 0x72, 0x58, 0x87, 0xc6, 0x86, 0x30,
+	0x86, 0x3d, 0xd3, 0x62, 0xbc, 0xc7, 0xc7, 0x0c, 0xe5, 0xf1, 0x11, 0xd4, 0x36, 0x8c, 0x4b, 0x09,
+	0x43, 0x05, 0x7c, 0x0a, 0xfa, 0x3e, 0xb1, 0x62, 0x8b, 0x92, 0x35, 0x6c, 0xeb, 0xd2, 0xa4, 0xa3,
+	0xe7, 0xcb, 0x95, 0x70, 0x0b, 0x4e, 0xbf, 0xc5, 0x2a, 0x7f, 0x59, 0x26, 0x1c, 0xb1, 0x2b, 0x97,
+	0x7f, 0x74, 0x88, 0x6b, 0xd9, 0x16, 0x41, 0x80, 0x11, 0x54, 0x65, 0x42, 0xea, 0x18, 0xae, 0x63,
+	0x53, 0x8e, 0x2a, 0xb8, 0x01, 0x68, 0x1b, 0x51, 0xd6, 0x2a, 0x3e, 0x01, 0x2c, 0xd1, 0xde, 0x98,
+	0x0f, 0x89, 0xc5, 0x4d, 0xa3, 0xc7, 0x4d, 0xdb, 0x42, 0x35, 0xdc, 0x84, 0x93, 0xe7, 0xb8, 0xf2,
+	0xd4, 0x55, 0xb9, 0xb2, 0x06, 0x32, 0x70, 0xfb, 0x97, 0xdc, 0xb5, 0xc8, 0x8d, 0xfb, 0xc1, 0x24,
+	0x37, 0xee, 0x88, 0x5d, 0xa1, 0x43, 0x55, 0xee, 0x13, 0xd6, 0xa1, 0xb6, 0x63, 0xb3, 0xde, 0x7b,
+	0xa5, 0x40, 0xb2, 0x73, 0xdb, 0x8a, 0x0f, 0x36, 0x27, 0x8a, 0x39, 0x92, 0xdd, 0x97, 0x7a, 0xb5,
+	0x4d, 0x73, 0x80, 0x30, 0xae, 0x42, 0x49, 0x02, 0x96, 0x3d, 0x20, 0xe8, 0x38, 0xdd, 0x54, 0x42,
+	0x33, 0xd4, 0x48, 0x37, 0x95, 0x22, 0xaa, 0xc0, 0x57, 0xb8, 0x0e, 0xb0, 0x41, 0x19, 0x3a, 0xc1,
+	0x18, 0xea, 0x8f, 0xb1, 0xd2, 0xbc, 0x4e, 0x0f, 0xc9, 0x21, 0x84, 0xba, 0xa6, 0x75, 0x69, 0x23,
+	0x1d, 0xbf, 0x82, 0xa3, 0x1d, 0x48, 0x29, 0xbf, 0x4b, 0xe1, 0xf8, 0x38, 0x87, 0xa4, 0x37, 0x20,
+	0x94, 0xa1, 0x66, 0xda, 0xa1, 0x64, 0xd1, 0x04, 0x57, 0x96, 0x37, 0xbb, 0x37, 0x80, 0x4f, 0x18,
+	0x3a, 0x4d, 0x1b, 0x9d, 0xc8, 0xf9, 0x24, 0x96, 0x7e, 0x9f, 0xd6, 0x66, 0x0c, 0x89, 0x71, 0xed,
+	0xd8, 0xa6, 0xc5, 0xd1, 0x59, 0xaa, 0x7d, 0xc4, 0x94, 0xf6, 0x6d, 0xfb, 0xef, 0x0c, 0x94, 0x37,
+	0x2f, 0x06, 0x57, 0xa0, 0xc8, 0xc6, 0x86, 0x41, 0x18, 0x43, 0x07, 0xf2, 0x5e, 0xaa, 0x93, 0xd7,
+	0x64, 0x93, 0xc6, 0xd6, 0xb5, 0x65, 0xdf, 0xb8, 0x84, 0x52, 0x9b, 0xa2, 0x0c, 0x3e, 0x86, 0x43,
+	0xb5, 0x94, 0xcb, 0xc6, 0xa3, 0x04, 0xcc, 0xca, 0x43, 0x1c, 0x5b, 0xa3, 0x1e, 0x65, 0xc3, 0xf8,
+	0x5c, 0xdc, 0xbe, 0x3d, 0xf8, 0x98, 0xb0, 0x39, 0x59, 0x95, 0x61, 0x5b, 0x16, 0x31, 0x64, 0xea,
+	0xcb, 0x31, 0x23, 0x28, 0xff, 0xfc, 0xc2, 0x27, 0xea, 0x02, 0x7e, 0x0d, 0xc7, 0x5b, 0xa8, 0x65,
+	0x73, 0x32, 0x31, 0x19, 0x47, 0x45, 0x99, 0xf9, 0xb1, 0x0f, 0xb1, 0xba, 0x84, 0xdb, 0x70, 0xf6,
+	0xcd, 0xfb, 0x1c, 0x6b, 0xca, 0xe9, 0x7b, 0x79, 0x72, 0xfd, 0x62, 0x16, 0xf0, 0x5b, 0x78, 0xb3,
+	0x87, 0xb5, 0x6c, 0xee, 0x3a, 0x3d, 0xc6, 0x50, 0xa5, 0xfd, 0x97, 0x06, 0x25, 0x47, 0x88, 0x48,
+	0xbe, 0x7e, 0x5c, 0x87, 0x8c, 0x3f, 0x4f, 0xa6, 0x67, 0xc6, 0x9f, 0xcb, 0x39, 0xe1, 0xcd, 0xe7,
+	0x6a, 0xae, 0xc4, 0xa3, 0x33, 0x0d, 0x15, 0x33, 0x9b, 0x05, 0x0f, 0xcb, 0x75, 0x32, 0x3f, 0xd3,
+	0x10, 0xff, 0x00, 0xb9, 0x50, 0x88, 0x48, 0xcf, 0xb5, 0xb2, 0x9d, 0x4a, 0x17, 0xa5, 0xb3, 0x2c,
+	0xcd, 0x41, 0x15, 0x2b, 0x47, 0xe3, 0xcc, 0x0b, 0xbd, 0xa9, 0x7f, 0xef, 0xaf, 0x7d, 0xb1, 0xd2,
+	0xf3, 0xad, 0x6c, 0xa7, 0x4c, 0x77, 0xb0, 0xae, 0x03, 0x10, 0x76, 0x43, 0x26, 0xa2, 0x2f, 0xfe,
+	0x4c, 0xe0, 0x3e, 0xd4, 0x99, 0x58, 0xce, 0x9d, 0x6e, 0x98, 0x7e, 0x74, 0x1a, 0xfb, 0xe6, 0x64,
+	0x73, 0x2f, 0xda, 0x3e, 0xe8, 0x68, 0x3f, 0x69, 0xfd, 0xce, 0xef, 0xef, 0x6e, 0xfd, 0xf5, 0xdd,
+	0xc3, 0xf4, 0x7c, 0x16, 0x2c, 0x2e, 0xfe, 0x94, 0x82, 0xd9, 0x9d, 0xe7, 0x2f, 0x93, 0xbf, 0x41,
+	0x24, 0x2e, 0x62, 0xf3, 0x34, 0xfe, 0xd2, 0xfd, 0xfc, 0xff, 0x00, 0x50, 0x54, 0x55, 0x42, 0x08,
+	0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.